@@ -27,20 +27,22 @@ CREATE TABLE users (
     verified             TINYINT(1)    NOT NULL  DEFAULT 0, /* Used to show who is a legitimate player on the leaderboard */
 
     /* Seeded leaderboard values */
-    seeded_trueskill         FLOAT      NOT NULL  DEFAULT 0,
-    seeded_trueskill_mu      FLOAT      NOT NULL  DEFAULT 25,
-    seeded_trueskill_sigma   FLOAT      NOT NULL  DEFAULT 8.333,
-    seeded_trueskill_change  FLOAT      NOT NULL  DEFAULT 0, /* The amount changed in the last race (can be positive or negative) */
-    seeded_num_races         INT        NOT NULL  DEFAULT 0,
-    seeded_last_race         TIMESTAMP  NULL      DEFAULT NULL,
+    seeded_trueskill             FLOAT      NOT NULL  DEFAULT 0,
+    seeded_trueskill_mu          FLOAT      NOT NULL  DEFAULT 25,
+    seeded_trueskill_sigma       FLOAT      NOT NULL  DEFAULT 8.333,
+    seeded_trueskill_change      FLOAT      NOT NULL  DEFAULT 0, /* The amount changed in the last race (can be positive or negative) */
+    seeded_trueskill_volatility  FLOAT      NOT NULL  DEFAULT 0.06, /* Only used by the Glicko-2 rating engine */
+    seeded_num_races             INT        NOT NULL  DEFAULT 0,
+    seeded_last_race             TIMESTAMP  NULL      DEFAULT NULL,
 
     /* Unseeded leaderboard values */
-    unseeded_trueskill         FLOAT      NOT NULL  DEFAULT 0,
-    unseeded_trueskill_mu      FLOAT      NOT NULL  DEFAULT 25,
-    unseeded_trueskill_sigma   FLOAT      NOT NULL  DEFAULT 8.333,
-    unseeded_trueskill_change  FLOAT      NOT NULL  DEFAULT 0, /* The amount changed in the last race (can be positive or negative) */
-    unseeded_num_races         INT        NOT NULL  DEFAULT 0,
-    unseeded_last_race         TIMESTAMP  NULL      DEFAULT NULL,
+    unseeded_trueskill             FLOAT      NOT NULL  DEFAULT 0,
+    unseeded_trueskill_mu          FLOAT      NOT NULL  DEFAULT 25,
+    unseeded_trueskill_sigma       FLOAT      NOT NULL  DEFAULT 8.333,
+    unseeded_trueskill_change      FLOAT      NOT NULL  DEFAULT 0, /* The amount changed in the last race (can be positive or negative) */
+    unseeded_trueskill_volatility  FLOAT      NOT NULL  DEFAULT 0.06, /* Only used by the Glicko-2 rating engine */
+    unseeded_num_races             INT        NOT NULL  DEFAULT 0,
+    unseeded_last_race             TIMESTAMP  NULL      DEFAULT NULL,
 
     /* Unseeded solo leaderboard values */
     ranked_solo_adjusted_average  INT           NOT NULL  DEFAULT 0, /* Rounded to the second */
@@ -53,12 +55,13 @@ CREATE TABLE users (
     ranked_solo_metadata          INT           NULL      DEFAULT NULL, /* Currently used to store the next build */
 
     /* Diversity leaderboard values */
-    diversity_trueskill         FLOAT      NOT NULL  DEFAULT 0,
-    diversity_trueskill_mu      FLOAT      NOT NULL  DEFAULT 25,
-    diversity_trueskill_sigma   FLOAT      NOT NULL  DEFAULT 8.333,
-    diversity_trueskill_change  FLOAT      NOT NULL  DEFAULT 0, /* The amount changed in the last race (can be positive or negative) */
-    diversity_num_races         INT        NOT NULL  DEFAULT 0,
-    diversity_last_race         TIMESTAMP  NULL      DEFAULT NULL,
+    diversity_trueskill             FLOAT      NOT NULL  DEFAULT 0,
+    diversity_trueskill_mu          FLOAT      NOT NULL  DEFAULT 25,
+    diversity_trueskill_sigma       FLOAT      NOT NULL  DEFAULT 8.333,
+    diversity_trueskill_change      FLOAT      NOT NULL  DEFAULT 0, /* The amount changed in the last race (can be positive or negative) */
+    diversity_trueskill_volatility  FLOAT      NOT NULL  DEFAULT 0.06, /* Only used by the Glicko-2 rating engine */
+    diversity_num_races             INT        NOT NULL  DEFAULT 0,
+    diversity_last_race             TIMESTAMP  NULL      DEFAULT NULL,
 
     /* Stream values */
     stream_url                 NVARCHAR(50)  NOT NULL  DEFAULT "-", /* Their stream URL */
@@ -85,6 +88,7 @@ CREATE TABLE races (
     goal            VARCHAR(50)  NULL, /* Valid entries are contained in the "raceValidateRuleset()" function */
     difficulty      VARCHAR(50)  NULL  DEFAULT "normal", /* Valid entries are contained in the "raceValidateRuleset()" function */
     starting_build  INT          NULL  DEFAULT -1, /* -1 for unseeded & diversity races, otherwise matches the build number */
+    team_size       INT          NOT NULL  DEFAULT 0, /* 0 for races without teams */
 
     /* Other fields */
     seed               VARCHAR(50)  NULL      DEFAULT "-",
@@ -109,6 +113,8 @@ CREATE TABLE race_participants (
     datetime_finished  TIMESTAMP      NOT NULL  DEFAULT 0,
     run_time           INT            NOT NULL, /* in milliseconds */
     comment            NVARCHAR(150)  NOT NULL,
+    team               INT            NOT NULL  DEFAULT 0, /* 0 for races without teams */
+    num_resets         INT            NOT NULL  DEFAULT 0, /* The number of times that they changed seeds */
 
     FOREIGN KEY(user_id) REFERENCES users(id),
     FOREIGN KEY(race_id) REFERENCES races(id) ON DELETE CASCADE,
@@ -148,6 +154,262 @@ CREATE TABLE race_participant_rooms (
 );
 CREATE INDEX race_participant_rooms_index_race_participant_id ON race_participant_rooms (race_participant_id);
 
+DROP TABLE IF EXISTS race_participant_splits;
+CREATE TABLE race_participant_splits (
+    id                   INT        NOT NULL  PRIMARY KEY  AUTO_INCREMENT, /* PRIMARY KEY automatically creates a UNIQUE constraint */
+    race_participant_id  INT        NOT NULL,
+    floor_num            INT        NOT NULL,
+    stage_type           INT        NOT NULL,
+    split_time           INT        NOT NULL, /* in milliseconds since the race started */
+    datetime_arrived     TIMESTAMP  NOT NULL,
+
+    FOREIGN KEY(race_participant_id) REFERENCES race_participants(id) ON DELETE CASCADE
+    /* If the race participant entry is deleted, automatically delete all of their splits */
+);
+CREATE INDEX race_participant_splits_index_race_participant_id ON race_participant_splits (race_participant_id);
+
+DROP TABLE IF EXISTS race_flags;
+CREATE TABLE race_flags (
+    id                 INT            NOT NULL  PRIMARY KEY  AUTO_INCREMENT, /* PRIMARY KEY automatically creates a UNIQUE constraint */
+    race_id            INT            NOT NULL,
+    user_id            INT            NOT NULL,
+    type               VARCHAR(50)    NOT NULL, /* runTime, item, floor */
+    description        NVARCHAR(300)  NOT NULL,
+    hold_leaderboard   TINYINT(1)     NOT NULL  DEFAULT 0, /* 1 if the race is kept out of the leaderboards until the flag is resolved */
+    resolved           TINYINT(1)     NOT NULL  DEFAULT 0, /* Either 0 or 1 */
+    resolved_by        INT            NULL      DEFAULT NULL,
+    datetime_created   TIMESTAMP      NOT NULL  DEFAULT NOW(),
+    datetime_resolved  TIMESTAMP      NULL      DEFAULT NULL,
+
+    FOREIGN KEY(race_id) REFERENCES races(id) ON DELETE CASCADE,
+    /* If the race is deleted, automatically delete all of its flags */
+    FOREIGN KEY(user_id) REFERENCES users(id),
+    FOREIGN KEY(resolved_by) REFERENCES users(id)
+);
+CREATE INDEX race_flags_index_race_id ON race_flags (race_id);
+CREATE INDEX race_flags_index_resolved ON race_flags (resolved);
+
+DROP TABLE IF EXISTS race_checkpoints;
+CREATE TABLE race_checkpoints (
+    race_id           INT         NOT NULL  PRIMARY KEY, /* PRIMARY KEY automatically creates a UNIQUE constraint */
+    state             MEDIUMTEXT  NOT NULL, /* The JSON-encoded in-memory race, including all of the racers */
+    datetime_updated  TIMESTAMP   NOT NULL  DEFAULT NOW(),
+
+    FOREIGN KEY(race_id) REFERENCES races(id) ON DELETE CASCADE
+    /* If the race is deleted, automatically delete the checkpoint */
+);
+
+DROP TABLE IF EXISTS rating_history;
+CREATE TABLE rating_history (
+    id                INT          NOT NULL  PRIMARY KEY  AUTO_INCREMENT, /* PRIMARY KEY automatically creates a UNIQUE constraint */
+    user_id           INT          NOT NULL,
+    race_id           INT          NOT NULL,
+    format            VARCHAR(50)  NOT NULL, /* unseeded, seeded, diversity, ranked_solo */
+    rating            FLOAT        NOT NULL, /* The TrueSkill, or the adjusted average in milliseconds for ranked solo */
+    mu                FLOAT        NULL      DEFAULT NULL, /* NULL for ranked solo */
+    sigma             FLOAT        NULL      DEFAULT NULL, /* NULL for ranked solo */
+    rating_change     FLOAT        NULL      DEFAULT NULL, /* How much the rating moved in this race; NULL for ranked solo */
+    datetime_created  TIMESTAMP    NOT NULL  DEFAULT NOW(), /* The time that the race finished */
+
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    /* If the user is deleted, automatically delete their rating history */
+    FOREIGN KEY(race_id) REFERENCES races(id) ON DELETE CASCADE,
+    /* If the race is deleted, automatically delete the rating changes from it */
+    UNIQUE(user_id, race_id, format)
+);
+CREATE INDEX rating_history_index_format ON rating_history (format);
+
+DROP TABLE IF EXISTS leaderboard_jobs;
+CREATE TABLE leaderboard_jobs (
+    id                 INT          NOT NULL  PRIMARY KEY  AUTO_INCREMENT, /* PRIMARY KEY automatically creates a UNIQUE constraint */
+    format             VARCHAR(50)  NOT NULL, /* unseeded, seeded, diversity, ranked_solo */
+    engine             VARCHAR(50)  NOT NULL  DEFAULT "", /* The rating engine; blank for ranked solo */
+    status             VARCHAR(50)  NOT NULL  DEFAULT "running", /* running, ready, applied, discarded, failed, stale */
+    started_by         INT          NULL      DEFAULT NULL, /* NULL if the server started it */
+    datetime_created   TIMESTAMP    NOT NULL  DEFAULT NOW(),
+
+    FOREIGN KEY(started_by) REFERENCES users(id) ON DELETE SET NULL
+    /* If the user is deleted, keep the job */
+);
+
+/* The results of a leaderboard job before they are applied to the "users" table */
+DROP TABLE IF EXISTS leaderboard_staging_stats;
+CREATE TABLE leaderboard_staging_stats (
+    id               INT    NOT NULL  PRIMARY KEY  AUTO_INCREMENT, /* PRIMARY KEY automatically creates a UNIQUE constraint */
+    job_id           INT    NOT NULL,
+    user_id          INT    NOT NULL,
+    rating           FLOAT  NOT NULL, /* The TrueSkill, or the adjusted average in milliseconds for ranked solo */
+    mu               FLOAT  NOT NULL  DEFAULT 0, /* The rest of the TrueSkill columns are 0 for ranked solo */
+    sigma            FLOAT  NOT NULL  DEFAULT 0,
+    volatility       FLOAT  NOT NULL  DEFAULT 0,
+    rating_change    FLOAT  NOT NULL  DEFAULT 0,
+    real_average     INT    NOT NULL  DEFAULT 0, /* The rest of the ranked solo columns are 0 for TrueSkill */
+    num_forfeits     INT    NOT NULL  DEFAULT 0,
+    forfeit_penalty  INT    NOT NULL  DEFAULT 0,
+    lowest_time      INT    NOT NULL  DEFAULT 0,
+    num_races        INT    NOT NULL,
+
+    FOREIGN KEY(job_id) REFERENCES leaderboard_jobs(id) ON DELETE CASCADE,
+    /* If the job is deleted, automatically delete its results */
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    /* If the user is deleted, automatically delete their staged stats */
+    UNIQUE(job_id, user_id)
+);
+
+/* The rating history of a leaderboard job before it is applied to the "rating_history" table */
+DROP TABLE IF EXISTS leaderboard_staging_history;
+CREATE TABLE leaderboard_staging_history (
+    id             INT    NOT NULL  PRIMARY KEY  AUTO_INCREMENT, /* PRIMARY KEY automatically creates a UNIQUE constraint */
+    job_id         INT    NOT NULL,
+    user_id        INT    NOT NULL,
+    race_id        INT    NOT NULL,
+    rating         FLOAT  NOT NULL,
+    mu             FLOAT  NULL      DEFAULT NULL, /* NULL for ranked solo */
+    sigma          FLOAT  NULL      DEFAULT NULL, /* NULL for ranked solo */
+    rating_change  FLOAT  NULL      DEFAULT NULL, /* NULL for ranked solo */
+
+    FOREIGN KEY(job_id) REFERENCES leaderboard_jobs(id) ON DELETE CASCADE,
+    /* If the job is deleted, automatically delete its history */
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    /* If the user is deleted, automatically delete their staged history */
+    FOREIGN KEY(race_id) REFERENCES races(id) ON DELETE CASCADE
+    /* If the race is deleted, automatically delete the staged rating changes from it */
+);
+CREATE INDEX leaderboard_staging_history_index_job_id ON leaderboard_staging_history (job_id);
+
+DROP TABLE IF EXISTS series;
+CREATE TABLE series (
+    id                 INT            NOT NULL  PRIMARY KEY  AUTO_INCREMENT, /* PRIMARY KEY automatically creates a UNIQUE constraint */
+    name               NVARCHAR(100)  NOT NULL,
+    best_of            INT            NOT NULL, /* e.g. 3 for a best-of-3 */
+    ruleset            TEXT           NOT NULL, /* The JSON-encoded ruleset of the most recent race */
+    rotate_characters  TINYINT(1)     NOT NULL  DEFAULT 0,
+    rotate_builds      TINYINT(1)     NOT NULL  DEFAULT 0,
+    captain            INT            NOT NULL,
+    finished           TINYINT(1)     NOT NULL  DEFAULT 0,
+    winner             INT            NULL      DEFAULT NULL,
+    datetime_created   TIMESTAMP      NOT NULL  DEFAULT NOW(),
+    datetime_finished  TIMESTAMP      NULL      DEFAULT NULL,
+
+    FOREIGN KEY(captain) REFERENCES users(id),
+    FOREIGN KEY(winner) REFERENCES users(id)
+);
+
+DROP TABLE IF EXISTS series_participants;
+CREATE TABLE series_participants (
+    id         INT  NOT NULL  PRIMARY KEY  AUTO_INCREMENT, /* PRIMARY KEY automatically creates a UNIQUE constraint */
+    series_id  INT  NOT NULL,
+    user_id    INT  NOT NULL,
+    score      INT  NOT NULL  DEFAULT 0, /* The number of races won in this series */
+
+    FOREIGN KEY(series_id) REFERENCES series(id) ON DELETE CASCADE,
+    /* If the series is deleted, automatically delete all of the participant rows */
+    FOREIGN KEY(user_id) REFERENCES users(id),
+    UNIQUE(series_id, user_id)
+);
+CREATE INDEX series_participants_index_user_id ON series_participants (user_id);
+
+DROP TABLE IF EXISTS series_races;
+CREATE TABLE series_races (
+    id           INT  NOT NULL  PRIMARY KEY  AUTO_INCREMENT, /* PRIMARY KEY automatically creates a UNIQUE constraint */
+    series_id    INT  NOT NULL,
+    race_id      INT  NOT NULL,
+    race_number  INT  NOT NULL, /* 1 for the first race in the series, and so on */
+
+    FOREIGN KEY(series_id) REFERENCES series(id) ON DELETE CASCADE,
+    FOREIGN KEY(race_id) REFERENCES races(id) ON DELETE CASCADE,
+    /* If the race is deleted (e.g. it never started), automatically delete the row */
+    UNIQUE(race_id)
+);
+CREATE INDEX series_races_index_series_id ON series_races (series_id);
+
+DROP TABLE IF EXISTS challenges;
+CREATE TABLE challenges (
+    id              INT          NOT NULL  PRIMARY KEY  AUTO_INCREMENT, /* PRIMARY KEY automatically creates a UNIQUE constraint */
+    type            VARCHAR(50)  NOT NULL, /* daily, weekly */
+    player_type     VARCHAR(50)  NOT NULL, /* The character; see the "races" table */
+    goal            VARCHAR(50)  NOT NULL,
+    starting_build  INT          NOT NULL,
+    seed            VARCHAR(50)  NOT NULL,
+    datetime_start  TIMESTAMP    NOT NULL  DEFAULT 0,
+    datetime_end    TIMESTAMP    NOT NULL  DEFAULT 0,
+
+    UNIQUE(type, datetime_start)
+);
+
+DROP TABLE IF EXISTS challenge_participants;
+CREATE TABLE challenge_participants (
+    id                INT        NOT NULL  PRIMARY KEY  AUTO_INCREMENT, /* PRIMARY KEY automatically creates a UNIQUE constraint */
+    challenge_id      INT        NOT NULL,
+    user_id           INT        NOT NULL,
+    race_id           INT        NULL, /* NULL if they left the race before it started */
+    datetime_created  TIMESTAMP  NOT NULL  DEFAULT NOW(),
+
+    FOREIGN KEY(challenge_id) REFERENCES challenges(id) ON DELETE CASCADE,
+    /* If the challenge is deleted, automatically delete all of the participant rows */
+    FOREIGN KEY(user_id) REFERENCES users(id),
+    FOREIGN KEY(race_id) REFERENCES races(id) ON DELETE SET NULL,
+    /* If the race is deleted, keep the row, since they have already seen the seed and used up their attempt */
+    UNIQUE(challenge_id, user_id)
+);
+CREATE INDEX challenge_participants_index_user_id ON challenge_participants (user_id);
+
+DROP TABLE IF EXISTS seasons;
+CREATE TABLE seasons (
+    id              INT            NOT NULL  PRIMARY KEY  AUTO_INCREMENT, /* PRIMARY KEY automatically creates a UNIQUE constraint */
+    name            NVARCHAR(100)  NOT NULL, /* e.g. "Ranked Solo Season 3 (Repentance)" */
+    category        VARCHAR(50)    NOT NULL, /* speedrun, ranked_solo */
+    datetime_start  TIMESTAMP      NULL      DEFAULT NULL, /* NULL for old speedrun seasons that have no recorded dates */
+    datetime_end    TIMESTAMP      NULL      DEFAULT NULL, /* NULL if the season does not have a planned end yet */
+    archived        TINYINT(1)     NOT NULL  DEFAULT 0 /* 1 once the season is over and is shown in the hall of fame */
+);
+
+DROP TABLE IF EXISTS hall_of_fame_entries;
+CREATE TABLE hall_of_fame_entries (
+    id                  INT            NOT NULL  PRIMARY KEY  AUTO_INCREMENT, /* PRIMARY KEY automatically creates a UNIQUE constraint */
+    season_id           INT            NOT NULL,
+    `rank`              INT            NOT NULL,
+    racer               NVARCHAR(100)  NOT NULL,
+    profile_name        NVARCHAR(100)  NOT NULL  DEFAULT "", /* Blank if they do not have an account on the website */
+    time                INT            NOT NULL  DEFAULT 0, /* In seconds (only for speedrun seasons) */
+    version             NVARCHAR(50)   NOT NULL  DEFAULT "", /* The version of Racing+ (only for speedrun seasons) */
+    date                NVARCHAR(50)   NOT NULL  DEFAULT "", /* e.g. "2017-07-13" (only for speedrun seasons) */
+    proof               NVARCHAR(300)  NOT NULL  DEFAULT "", /* A link to the video (only for speedrun seasons) */
+    site                NVARCHAR(50)   NOT NULL  DEFAULT "", /* e.g. "Twitch" (only for speedrun seasons) */
+    adjusted_average    INT            NOT NULL  DEFAULT 0, /* In seconds (only for ranked solo seasons) */
+    unadjusted_average  INT            NOT NULL  DEFAULT 0, /* In seconds (only for ranked solo seasons) */
+    forfeit_penalty     INT            NOT NULL  DEFAULT 0, /* In seconds (only for ranked solo seasons) */
+    num_forfeits        INT            NOT NULL  DEFAULT 0, /* Only for ranked solo seasons */
+    num_races           INT            NOT NULL  DEFAULT 0, /* The number of races that the forfeit rate is out of (only for ranked solo seasons) */
+
+    FOREIGN KEY(season_id) REFERENCES seasons(id) ON DELETE CASCADE
+    /* If the season is deleted, automatically delete all of its entries */
+);
+CREATE INDEX hall_of_fame_entries_index_season_id ON hall_of_fame_entries (season_id);
+
+DROP TABLE IF EXISTS hall_of_fame_submissions;
+CREATE TABLE hall_of_fame_submissions (
+    id                 INT            NOT NULL  PRIMARY KEY  AUTO_INCREMENT, /* PRIMARY KEY automatically creates a UNIQUE constraint */
+    season_id          INT            NOT NULL,
+    user_id            INT            NOT NULL,
+    time               INT            NOT NULL, /* In seconds */
+    version            NVARCHAR(50)   NOT NULL, /* The version of Racing+ */
+    proof              NVARCHAR(300)  NOT NULL, /* A link to the video */
+    site               NVARCHAR(50)   NOT NULL, /* Twitch, YouTube */
+    status             VARCHAR(50)    NOT NULL  DEFAULT "pending", /* pending, approved, rejected */
+    reviewed_by        INT            NULL      DEFAULT NULL,
+    reason             NVARCHAR(300)  NOT NULL  DEFAULT "", /* Why it was rejected */
+    datetime_created   TIMESTAMP      NOT NULL  DEFAULT NOW(),
+    datetime_reviewed  TIMESTAMP      NULL      DEFAULT NULL,
+
+    FOREIGN KEY(season_id) REFERENCES seasons(id) ON DELETE CASCADE,
+    /* If the season is deleted, automatically delete all of its submissions */
+    FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE,
+    /* If the user is deleted, automatically delete their submissions */
+    FOREIGN KEY(reviewed_by) REFERENCES users(id)
+);
+CREATE INDEX hall_of_fame_submissions_index_status ON hall_of_fame_submissions (status);
+
 DROP TABLE IF EXISTS banned_users;
 CREATE TABLE banned_users (
     id                 INT            NOT NULL  PRIMARY KEY  AUTO_INCREMENT, /* PRIMARY KEY automatically creates a UNIQUE constraint */
@@ -0,0 +1,192 @@
+/*
+    The seasons from before the season engine existed, along with their hall of fame entries
+    (this only needs to be imported once, after importing "database_schema.sql")
+    All of these seasons are archived; new seasons are opened with the "adminSeasonOpen" command
+*/
+
+USE isaac;
+
+/* R+9 Season 1 (Afterbirth+) */
+INSERT INTO seasons (id, name, category, datetime_start, datetime_end, archived) VALUES (1, "R+9 Season 1 (Afterbirth+)", "speedrun", NULL, NULL, 1);
+INSERT INTO hall_of_fame_entries (season_id, `rank`, racer, profile_name, time, date, proof, site) VALUES
+    (1, 1, "Dea1h", "Dea1h", 5039, "2017-07-13", "https://www.twitch.tv/videos/158833908", "Twitch"),
+    (1, 2, "Cyber_1", "Cyber_1", 5462, "2017-04-23", "https://www.twitch.tv/videos/137587747", "Twitch"),
+    (1, 3, "CrafterLynx", "CrafterLynx", 5620, "2017-09-19", "https://www.twitch.tv/videos/175962871", "Twitch"),
+    (1, 4, "Zamiel", "Zamiel", 5629, "2017-04-12", "https://www.twitch.tv/videos/135084542", "Twitch"),
+    (1, 5, "ReidMercury__", "ReidMercury__", 5655, "2017-09-17", "https://www.twitch.tv/videos/175491970", "Twitch"),
+    (1, 6, "ceehe", "ceehe", 5776, "2017-10-18", "https://www.twitch.tv/videos/180734439", "Twitch"),
+    (1, 7, "leo_ze_tron", "leo_ze_tron", 5925, "2017-08-17", "https://www.twitch.tv/videos/167785993", "Twitch"),
+    (1, 8, "SlashSP", "SlashSP", 5938, "2017-09-22", "https://www.twitch.tv/videos/176523741", "Twitch"),
+    (1, 9, "Shigan", "Shigan", 5984, "2017-05-14", "https://www.twitch.tv/videos/143486007", "Twitch"),
+    (1, 10, "thereisnofuture", "thereisnofuture", 5999, "2017-04-14", "https://www.twitch.tv/videos/135612266", "Twitch");
+
+/* R+14 Season 1 (Afterbirth+) */
+INSERT INTO seasons (id, name, category, datetime_start, datetime_end, archived) VALUES (2, "R+14 Season 1 (Afterbirth+)", "speedrun", NULL, NULL, 1);
+INSERT INTO hall_of_fame_entries (season_id, `rank`, racer, profile_name, time, date, proof, site) VALUES
+    (2, 1, "Dea1h", "Dea1h", 8909, "2017-09-24", "https://www.twitch.tv/videos/177220345", "Twitch"),
+    (2, 2, "CrafterLynx", "CrafterLynx", 9061, "2017-09-09", "https://www.twitch.tv/videos/175961290", "Twitch"),
+    (2, 3, "ceehe", "ceehe", 9782, "2017-10-08", "https://www.twitch.tv/videos/213334990", "Twitch"),
+    (2, 4, "Yama", "Yama", 9837, "2017-09-15", "https://www.twitch.tv/videos/174831927", "Twitch"),
+    (2, 5, "SlashSP", "SlashSP", 9960, "2017-09-22", "https://www.twitch.tv/videos/176523011", "Twitch"),
+    (2, 6, "Shigan", "Shigan", 10188, "2017-05-14", "https://www.twitch.tv/videos/143486007", "Twitch"),
+    (2, 7, "ReidMercury__", "ReidMercury__", 10431, "2017-08-18", "https://www.twitch.tv/videos/167959080", "Twitch"),
+    (2, 8, "Zamiel", "Zamiel", 10616, "2017-04-12", "https://www.twitch.tv/videos/135084542", "Twitch"),
+    (2, 9, "MrPopoche1", "", 10665, "2017-06-06", "https://www.twitch.tv/videos/149949436", "Twitch"),
+    (2, 10, "SergeBenamou31", "", 11364, "2017-08-31", "https://www.twitch.tv/videos/171259098", "Twitch");
+
+/* R+7 Season 2 (Afterbirth+) */
+INSERT INTO seasons (id, name, category, datetime_start, datetime_end, archived) VALUES (3, "R+7 Season 2 (Afterbirth+)", "speedrun", NULL, NULL, 1);
+INSERT INTO hall_of_fame_entries (season_id, `rank`, racer, profile_name, time, date, proof, site) VALUES
+    (3, 1, "Dea1h", "Dea1h", 3673, "2017-07-20", "https://www.twitch.tv/videos/160709749", "Twitch"),
+    (3, 2, "Shigan", "Shigan", 4033, "2017-07-19", "https://www.twitch.tv/videos/160356773", "Twitch"),
+    (3, 3, "ceehe", "ceehe", 4158, "2017-08-10", "https://www.twitch.tv/videos/165878075", "Twitch"),
+    (3, 4, "Zamiel", "Zamiel", 4476, "2017-06-07", "https://www.twitch.tv/videos/150106693", "Twitch"),
+    (3, 5, "CrafterLynx", "CrafterLynx", 4479, "2017-06-06", "https://www.twitch.tv/videos/148982150", "Twitch"),
+    (3, 6, "SlashSP", "SlashSP", 4548, "2017-07-24", "https://www.twitch.tv/videos/161482668", "Twitch"),
+    (3, 7, "thereisnofuture", "thereisnofuture", 4593, "2017-09-26", "https://www.twitch.tv/videos/177693217", "Twitch"),
+    (3, 8, "AdRyDN", "Adrydn", 4619, "2017-06-14", "https://www.twitch.tv/videos/149545223", "Twitch"),
+    (3, 9, "ReidMercury__", "ReidMercury__", 4625, "2017-11-10", "https://www.twitch.tv/videos/200127524", "Twitch"),
+    (3, 10, "thalen22", "thalen22", 4628, "2017-07-31", "https://www.twitch.tv/videos/163297005", "Twitch");
+
+/* R+7 Season 3 (Afterbirth+) */
+INSERT INTO seasons (id, name, category, datetime_start, datetime_end, archived) VALUES (4, "R+7 Season 3 (Afterbirth+)", "speedrun", NULL, NULL, 1);
+INSERT INTO hall_of_fame_entries (season_id, `rank`, racer, profile_name, time, date, proof, site) VALUES
+    (4, 1, "Dea1h", "Dea1h", 3797, "2017-12-04", "https://www.twitch.tv/videos/206706029", "Twitch"),
+    (4, 2, "ReidMercury__", "ReidMercury__", 3844, "2017-11-28", "https://www.twitch.tv/videos/205034914", "Twitch"),
+    (4, 3, "bmzloop", "Loopy", 4091, "2017-12-15", "https://www.youtube.com/watch?v=g02cqQsrH1M", "YouTube"),
+    (4, 4, "Shigan", "Shigan", 4130, "2018-01-19", "https://www.twitch.tv/videos/220225421", "Twitch"),
+    (4, 5, "CrafterLynx", "CrafterLynx", 4133, "2018-01-16", "https://www.twitch.tv/videos/219308798", "Twitch"),
+    (4, 6, "SapphireHX", "Sapphire", 4249, "2018-02-06", "https://www.youtube.com/watch?v=Zw2Ot5hjgZQ", "YouTube"),
+    (4, 7, "MoucheronQuipet", "MoucheronQuipet", 4269, "2018-03-14", "https://www.youtube.com/watch?v=gMT-caoKJE0", "YouTube"),
+    (4, 8, "leo_ze_tron", "leo_ze_tron", 4290, "2018-03-08", "https://www.twitch.tv/videos/236448621", "Twitch"),
+    (4, 9, "Zamiel", "Zamiel", 4362, "2017-12-13", "https://www.twitch.tv/videos/209120653", "Twitch"),
+    (4, 10, "ceehe", "ceehe", 4448, "2017-12-13", "https://www.twitch.tv/videos/209219042", "Twitch");
+
+/* R+7 Season 4 (Afterbirth+) */
+INSERT INTO seasons (id, name, category, datetime_start, datetime_end, archived) VALUES (5, "R+7 Season 4 (Afterbirth+)", "speedrun", NULL, NULL, 1);
+INSERT INTO hall_of_fame_entries (season_id, `rank`, racer, profile_name, time, date, proof, site) VALUES
+    (5, 1, "antizoubilamakA", "CRAZYEIGHTSFAN69", 3406, "2018-09-07", "https://www.twitch.tv/videos/307245073", "Twitch"),
+    (5, 2, "Cyber_1", "Cyber_1", 3475, "2018-05-11", "https://www.twitch.tv/videos/260283073", "Twitch"),
+    (5, 3, "leo_ze_tron", "leo_ze_tron", 3522, "2018-07-11", "https://www.twitch.tv/videos/283570282", "Twitch"),
+    (5, 4, "thereisnofuture", "thereisnofuture", 3543, "2018-08-31", "https://www.twitch.tv/videos/304179755", "Twitch"),
+    (5, 5, "Shigan", "Shigan", 3617, "2018-10-29", "https://www.twitch.tv/videos/328752825", "Twitch"),
+    (5, 6, "ReidMercury__", "ReidMercury__", 3622, "2018-05-07", "https://www.twitch.tv/videos/258743172", "Twitch"),
+    (5, 7, "ceehe", "ceehe", 3727, "2018-04-22", "https://www.twitch.tv/videos/252823258", "Twitch"),
+    (5, 8, "CrafterLynx", "CrafterLynx", 3776, "2018-09-29", "https://www.twitch.tv/videos/316373160", "Twitch"),
+    (5, 9, "bmzloop", "Loopy", 3788, "2018-04-19", "https://www.twitch.tv/videos/251906525", "Twitch"),
+    (5, 10, "thisguyisbarry", "thisguyisbarry", 3814, "2018-05-22", "https://www.twitch.tv/videos/264286364", "Twitch");
+
+/* R+7 Season 5 (Afterbirth+) */
+INSERT INTO seasons (id, name, category, datetime_start, datetime_end, archived) VALUES (6, "R+7 Season 5 (Afterbirth+)", "speedrun", NULL, NULL, 1);
+INSERT INTO hall_of_fame_entries (season_id, `rank`, racer, profile_name, time, date, proof, site) VALUES
+    (6, 1, "Dea1h", "Dea1h", 3773, "2018-12-11", "https://www.twitch.tv/videos/347393032", "Twitch"),
+    (6, 2, "Cyber_1", "Cyber_1", 3806, "2019-01-11", "https://www.twitch.tv/videos/365298470", "Twitch"),
+    (6, 3, "CrafterLynx", "CrafterLynx", 3857, "2019-01-23", "https://www.twitch.tv/videos/368584877", "Twitch"),
+    (6, 4, "thereisnofuture", "thereisnofuture", 3871, "2018-12-05", "https://www.twitch.tv/thereisnofuture/v/344976260", "Twitch"),
+    (6, 5, "mgln", "mgln", 3929, "2019-04-21", "https://www.youtube.com/watch?v=LUjetqZ7O9I", "Twitch"),
+    (6, 6, "sisuka", "sisuka", 4172, "2018-12-23", "https://www.youtube.com/watch?v=Rth9ITDWZ4o", "Twitch"),
+    (6, 7, "Shigan", "Shigan", 4228, "2019-03-12", "https://www.twitch.tv/videos/394474921", "Twitch"),
+    (6, 8, "Zamiel", "Zamiel", 4231, "2018-11-22", "https://www.twitch.tv/videos/339146750", "Twitch"),
+    (6, 9, "Gamonymous", "Gamonymous", 4293, "2019-01-23", "https://www.twitch.tv/gamonymous__/v/368290597", "Twitch"),
+    (6, 10, "SlashSP", "SlashSP", 4305, "2018-12-15", "https://www.twitch.tv/videos/349688950", "Twitch");
+
+/* R+7 Season 6 (Afterbirth+) */
+INSERT INTO seasons (id, name, category, datetime_start, datetime_end, archived) VALUES (7, "R+7 Season 6 (Afterbirth+)", "speedrun", NULL, NULL, 1);
+INSERT INTO hall_of_fame_entries (season_id, `rank`, racer, profile_name, time, date, proof, site) VALUES
+    (7, 1, "Shigan", "Shigan", 2644, "2019-06-05", "https://www.twitch.tv/videos/434894359", "Twitch"),
+    (7, 2, "thereisnofuture", "thereisnofuture", 2754, "2019-07-09", "https://www.twitch.tv/videos/450425805", "Twitch"),
+    (7, 3, "Greg", "Greg570", 2790, "2019-09-24", "https://www.twitch.tv/videos/486128516", "Twitch"),
+    (7, 4, "antizoubilamakA", "CRAZYEIGHTSFAN69", 2813, "2019-05-18", "https://www.twitch.tv/videos/426777217", "Twitch"),
+    (7, 5, "Gamonymous", "Gamonymous", 2850, "2019-06-10", "https://www.youtube.com/watch?v=nl_cvqtAjdg", "Twitch"),
+    (7, 6, "leo_ze_tron", "leo_ze_tron", 2855, "2019-09-17", "https://www.twitch.tv/videos/482932943", "Twitch"),
+    (7, 7, "mgln", "mgln", 2912, "2019-08-30", "https://www.twitch.tv/videos/474508067", "Twitch"),
+    (7, 8, "YuCaesar", "YuCaesar", 3055, "2019-10-16", "https://www.twitch.tv/videos/495431034", "Twitch"),
+    (7, 9, "sisuka", "sisuka", 3062, "2019-08-18", "https://www.youtube.com/watch?v=EaySvHPIQOw", "Twitch"),
+    (7, 10, "Pingouin23", "Pingouin23", 3064, "2019-10-13", "https://www.twitch.tv/videos/494086038", "Twitch");
+
+/* R+7 Season 7 (Afterbirth+) */
+INSERT INTO seasons (id, name, category, datetime_start, datetime_end, archived) VALUES (8, "R+7 Season 7 (Afterbirth+)", "speedrun", NULL, NULL, 1);
+INSERT INTO hall_of_fame_entries (season_id, `rank`, racer, profile_name, time, date, proof, site) VALUES
+    (8, 1, "Dea1h", "Dea1h", 2974, "2019-12-01", "https://www.youtube.com/watch?v=F_I2SQ1uuGg", "Twitch"),
+    (8, 2, "thereisnofuture", "thereisnofuture", 2981, "2020-01-25", "https://www.twitch.tv/videos/541510966", "Twitch"),
+    (8, 3, "mgln", "mgln", 2985, "2020-03-05", "https://www.twitch.tv/videos/562138650", "Twitch"),
+    (8, 4, "Greg", "Greg570", 2992, "2019-12-04", "https://www.twitch.tv/videos/517220728", "Twitch"),
+    (8, 5, "antizoubilamakA", "CRAZYEIGHTSFAN69", 3047, "2019-11-04", "https://www.twitch.tv/videos/504118249", "Twitch"),
+    (8, 6, "sisuka", "sisuka", 3103, "2019-11-20", "https://www.youtube.com/watch?v=Peap-KVcCfs", "YouTube"),
+    (8, 7, "CrafterLynx", "CrafterLynx", 3112, "2020-03-05", "https://www.twitch.tv/videos/562458758", "Twitch"),
+    (8, 8, "Pingouin23", "Pingouin23", 3253, "2019-12-04", "https://www.twitch.tv/videos/517005634", "Twitch"),
+    (8, 9, "tayu", "tayu", 3299, "2020-04-25", "https://www.youtube.com/watch?v=bVMGETluQXQ", "YouTube"),
+    (8, 10, "Gamonymous", "Gamonymous", 3313, "2020-04-14", "https://www.youtube.com/watch?v=ArNq0dp5rs8", "YouTube");
+
+/* R+7 Season 8 (Afterbirth+) */
+INSERT INTO seasons (id, name, category, datetime_start, datetime_end, archived) VALUES (9, "R+7 Season 8 (Afterbirth+)", "speedrun", NULL, NULL, 1);
+INSERT INTO hall_of_fame_entries (season_id, `rank`, racer, profile_name, time, date, proof, site) VALUES
+    (9, 1, "Dea1h", "Dea1h", 3843, "2020-05-22", "https://www.youtube.com/watch?v=qheqrQhaVaA", "YouTube"),
+    (9, 2, "mgln", "mgln", 3908, "2020-05-20", "https://www.youtube.com/watch?v=Co6K3kV8J4s", "YouTube"),
+    (9, 3, "Gamonymous", "Gamonymous", 4037, "2020-06-25", "https://www.youtube.com/watch?v=PPyiZHJsk_A", "YouTube"),
+    (9, 4, "thereisnofuture", "thereisnofuture", 4086, "2020-05-15", "https://www.youtube.com/watch?v=hHLqcK9BZKA", "YouTube"),
+    (9, 5, "sisuka", "sisuka", 4269, "2020-05-16", "https://www.twitch.tv/videos/622770475", "Twitch"),
+    (9, 6, "Pingouin23", "Pingouin23", 4386, "2020-10-20", "https://www.twitch.tv/videos/775990110", "Twitch"),
+    (9, 7, "Shigan", "Shigan", 4707, "2020-10-21", "https://www.youtube.com/watch?v=vWHFO7I0nB8", "YouTube"),
+    (9, 8, "Anidalife", "Anidalife", 4332, "2020-06-13", "https://www.youtube.com/watch?v=Vf4kC_CUitQ", "YouTube"),
+    (9, 9, "Adrayon", "Adrayon", 4881, "2020-06-21", "https://www.twitch.tv/videos/657464282", "Twitch"),
+    (9, 10, "MoucheronQuipet", "MoucheronQuipet", 4887, "2020-05-04", "https://www.twitch.tv/videos/611042129", "Twitch");
+
+/* R+7 Season 1 (Repentance) */
+INSERT INTO seasons (id, name, category, datetime_start, datetime_end, archived) VALUES (10, "R+7 Season 1 (Repentance)", "speedrun", NULL, NULL, 1);
+INSERT INTO hall_of_fame_entries (season_id, `rank`, racer, profile_name, time, date, proof, site) VALUES
+    (10, 1, "Finalkids", "Finalkids", 3669, "2022-01-09", "https://www.youtube.com/watch?v=s-ktyzlmgUk", "YouTube"),
+    (10, 2, "mgln", "mgln", 3687, "2022-02-05", "https://www.twitch.tv/videos/1288027564", "Twitch"),
+    (10, 3, "Greg", "Greg570", 3829, "2021-09-25", "https://www.youtube.com/watch?v=V75hHJKsIQw", "YouTube"),
+    (10, 4, "Cyber_1", "Cyber_1", 3896, "2022-02-02", "https://www.twitch.tv/videos/1286578327", "Twitch"),
+    (10, 5, "Adrayon", "Adrayon", 3949, "2022-03-06", "https://www.twitch.tv/videos/1416760218", "Twitch"),
+    (10, 6, "Gamonymous", "Gamonymous", 3950, "2021-09-25", "https://www.youtube.com/watch?v=bzMP1WYPtkY", "YouTube"),
+    (10, 7, "KiraKeepKool", "KiraKeepKool", 3954, "2022-03-08", "https://www.youtube.com/watch?v=j0uPmUSLxeU&t=625s", "YouTube"),
+    (10, 8, "leo_ze_tron", "leo_ze_tron", 3963, "2022-01-04", "https://www.twitch.tv/videos/1252903653", "Twitch"),
+    (10, 9, "YuCaesar", "YuCaesar", 4048, "2022-02-24", "https://www.twitch.tv/videos/1306657459", "Twitch"),
+    (10, 10, "sisuka", "sisuka", 4078, "2022-02-11", "https://www.twitch.tv/videos/1293502381", "Twitch");
+
+/* R+7 Season 2 (Repentance) */
+INSERT INTO seasons (id, name, category, datetime_start, datetime_end, archived) VALUES (11, "R+7 Season 2 (Repentance)", "speedrun", NULL, NULL, 1);
+INSERT INTO hall_of_fame_entries (season_id, `rank`, racer, profile_name, time, date, proof, site) VALUES
+    (11, 1, "mgln", "mgln", 2716, "2022-05-22", "https://www.twitch.tv/videos/1490540193", "Twitch"),
+    (11, 2, "Finalkids", "Finalkids", 2738, "2022-03-17", "https://www.youtube.com/watch?v=_FMJjWuz9a0", "YouTube"),
+    (11, 3, "tayu", "tayu", 2888, "2022-08-07", "https://www.youtube.com/watch?v=Y9-05jneA24", "YouTube"),
+    (11, 4, "KiraKeepKool", "KiraKeepKool", 2892, "2022-05-13", "https://www.twitch.tv/videos/1482375296", "Twitch"),
+    (11, 5, "leo_ze_tron", "leo_ze_tron", 2926, "2022-04-14", "https://www.twitch.tv/videos/1455280672", "Twitch"),
+    (11, 6, "sisuka", "sisuka", 2946, "2022-05-17", "https://www.twitch.tv/videos/1486409779", "Twitch"),
+    (11, 7, "Cyber_1", "Cyber_1", 2958, "2022-08-23", "https://www.twitch.tv/videos/1570091193", "Twitch"),
+    (11, 8, "YuCaesar", "YuCaesar", 2965, "2022-04-26", "https://www.twitch.tv/videos/1467714564", "Twitch"),
+    (11, 9, "Adrayon", "Adrayon", 2992, "2022-06-12", "https://www.twitch.tv/videos/1501950411", "Twitch"),
+    (11, 10, "thereisnofuture", "thereisnofuture", 3011, "2022-03-21", "https://www.twitch.tv/videos/1432566879", "Twitch");
+
+/* Ranked Solo Season 1 (Afterbirth+) */
+INSERT INTO seasons (id, name, category, datetime_start, datetime_end, archived) VALUES (12, "Ranked Solo Season 1 (Afterbirth+)", "ranked_solo", "2017-10-17 23:00:00", "2018-03-17 00:00:00", 1);
+INSERT INTO hall_of_fame_entries (season_id, `rank`, racer, profile_name, adjusted_average, unadjusted_average, forfeit_penalty, num_forfeits, num_races) VALUES
+    (12, 1, "bmzloop", "Loopy", 761, 761, 0, 0, 50),
+    (12, 2, "Dea1h", "Dea1h", 792, 734, 58, 4, 50),
+    (12, 3, "Yama", "Yama", 842, 779, 62, 4, 50),
+    (12, 4, "Zamiel", "Zamiel", 844, 811, 32, 2, 50),
+    (12, 5, "910dan", "910dan", 879, 879, 0, 0, 50),
+    (12, 6, "SlashSP", "SlashSP", 911, 799, 111, 7, 50),
+    (12, 7, "Cyber_1", "Cyber_1", 913, 773, 139, 9, 50),
+    (12, 8, "AdRyDN", "Adrydn", 928, 814, 113, 7, 50),
+    (12, 9, "Ou_J", "OhJay", 993, 871, 122, 7, 50),
+    (12, 10, "Krakenos", "Krakenos", 1003, 896, 107, 6, 50);
+
+/* Ranked Solo Season 2 (Afterbirth+) */
+INSERT INTO seasons (id, name, category, datetime_start, datetime_end, archived) VALUES (13, "Ranked Solo Season 2 (Afterbirth+)", "ranked_solo", "2018-03-18 23:00:00", "2018-10-26 00:00:00", 1);
+INSERT INTO hall_of_fame_entries (season_id, `rank`, racer, profile_name, adjusted_average, unadjusted_average, forfeit_penalty, num_forfeits, num_races) VALUES
+    (13, 1, "Cyber_1", "Cyber_1", 697, 683, 13, 2, 100),
+    (13, 2, "antizoubilamakA", "CRAZYEIGHTSFAN69", 718, 691, 27, 4, 100),
+    (13, 3, "Zamiel", "Zamiel", 732, 718, 14, 2, 100),
+    (13, 4, "thereisnofuture", "thereisnofuture", 799, 739, 59, 8, 100),
+    (13, 5, "cold90a", "cold90a", 881, 786, 94, 12, 100),
+    (13, 6, "elgirs", "elgirs", 940, 804, 136, 17, 100),
+    (13, 7, "ILLARIO", "ILLARIO", 947, 853, 93, 11, 100),
+    (13, 8, "Noro", "Noro", 1021, 954, 66, 7, 100),
+    (13, 9, "galnegus", "galnegus", 1051, 875, 175, 20, 100),
+    (13, 10, "Im_Cirno", "Im_Cirno", 1191, 1001, 190, 19, 100);
+
+/* The current ranked solo season */
+INSERT INTO seasons (id, name, category, datetime_start, datetime_end, archived) VALUES (14, "Ranked Solo Season 3 (Repentance)", "ranked_solo", "2021-12-03 00:00:00", NULL, 0);
@@ -0,0 +1,11 @@
+/*
+    Adds the rating change to the rating history, which the head-to-head statistics are built from
+    (this only needs to be run once, on databases that were created before the "rating_change" column existed)
+    The existing rows are left as NULL; recalculate the unseeded, seeded, and diversity leaderboards
+    afterward with the "adminLeaderboardJobStart" command to fill them in
+*/
+
+USE isaac;
+
+ALTER TABLE rating_history ADD COLUMN rating_change FLOAT NULL DEFAULT NULL AFTER sigma;
+ALTER TABLE leaderboard_staging_history ADD COLUMN rating_change FLOAT NULL DEFAULT NULL AFTER sigma;
@@ -0,0 +1,24 @@
+/*
+    Moves the run time reviews into the race flags, which are now the only place that
+    mismatched run times are stored
+    (this only needs to be run once, on databases that still have the "run_time_reviews" table)
+*/
+
+USE isaac;
+
+INSERT INTO race_flags (race_id, user_id, type, description, hold_leaderboard, resolved, datetime_created)
+    SELECT
+        race_id,
+        user_id,
+        "runTime",
+        CONCAT(
+            "Reported a run time of ", client_run_time,
+            " ms, but the server measured ", server_run_time,
+            " ms (with a latency of ", latency, " ms)."
+        ),
+        0,
+        reviewed,
+        datetime_created
+    FROM run_time_reviews;
+
+DROP TABLE run_time_reviews;
@@ -12,6 +12,8 @@ $(document).ready(() => {
   ConvertRaceTime("#ranked-solo-fastest-val");
   ConvertRaceTime(".races-td-time");
   BannedUser();
+  LoadRatingHistory();
+  LoadHeadToHead();
   $(".tooltip").tooltipster({
     theme: "tooltipster-shadow",
   });
@@ -233,3 +235,172 @@ function BannedUser() {
     var imgHeight = $("#image-height").height();
   }
 }
+
+// Draw a graph of how the player's rating changed over time
+// (each season is drawn as a separate line)
+let ratingHistory = null;
+let ratingHistoryChart = null;
+const ratingHistoryColors = ["#3e95cd", "#8e5ea2", "#3cba9f", "#e8c3b9", "#c45850"];
+
+function LoadRatingHistory() {
+  const username = $("#rating-history").data("username");
+  if (!username) {
+    return;
+  }
+
+  $.getJSON("/api/profile/" + encodeURIComponent(username) + "/ratings", (data) => {
+    ratingHistory = data;
+    DrawRatingHistory($("#rating-history-format").val());
+  });
+  $("#rating-history-format").change(function () {
+    DrawRatingHistory($(this).val());
+  });
+}
+
+function DrawRatingHistory(format) {
+  if (ratingHistory === null || !(format in ratingHistory.formats)) {
+    return;
+  }
+  const historyFormat = ratingHistory.formats[format];
+
+  // The ranked solo rating is an adjusted average time, so it is shown in minutes
+  const isTime = format === "ranked_solo";
+
+  const datasets = [];
+  historyFormat.seasons.forEach((season, i) => {
+    const points = historyFormat.points.filter(
+      (point) =>
+        point.datetimeCreated >= season.datetimeStart &&
+        (season.datetimeEnd === 0 || point.datetimeCreated < season.datetimeEnd)
+    );
+    if (points.length === 0) {
+      return;
+    }
+    datasets.push({
+      label: season.name,
+      fill: false,
+      borderColor: ratingHistoryColors[i % ratingHistoryColors.length],
+      data: points.map((point) => ({
+        x: new Date(point.datetimeCreated),
+        y: isTime ? point.rating / 1000 / 60 : point.rating,
+      })),
+    });
+  });
+
+  if (ratingHistoryChart !== null) {
+    ratingHistoryChart.destroy();
+    ratingHistoryChart = null;
+  }
+  if (datasets.length === 0) {
+    $("#rating-history-chart").hide();
+    $("#rating-history-empty").show();
+    return;
+  }
+  $("#rating-history-empty").hide();
+  $("#rating-history-chart").show();
+
+  ratingHistoryChart = new Chart($("#rating-history-chart"), {
+    type: "line",
+    data: { datasets },
+    options: {
+      scales: {
+        xAxes: [{ type: "time", time: { unit: "month" } }],
+        yAxes: [
+          {
+            // A lower average time is better
+            ticks: { reverse: isTime },
+            scaleLabel: {
+              display: true,
+              labelString: isTime ? "Adjusted Average (minutes)" : "TrueSkill",
+            },
+          },
+        ],
+      },
+    },
+  });
+}
+
+// Compare the player with someone else
+// (the opponent can also be given in the URL, e.g. "/profile/Alice?vs=Bob")
+function LoadHeadToHead() {
+  const username = $("#head-to-head").data("username");
+  if (!username) {
+    return;
+  }
+
+  $("#head-to-head-table").hide();
+  $("#head-to-head-form").submit((event) => {
+    event.preventDefault();
+    const opponent = $("#head-to-head-opponent").val().trim();
+    if (opponent !== "") {
+      DrawHeadToHead(username, opponent);
+    }
+  });
+
+  const opponent = new URLSearchParams(window.location.search).get("vs");
+  if (opponent) {
+    $("#head-to-head-opponent").val(opponent);
+    DrawHeadToHead(username, opponent);
+  }
+}
+
+function DrawHeadToHead(username, opponent) {
+  const url =
+    "/api/h2h/" +
+    encodeURIComponent(username) +
+    "/" +
+    encodeURIComponent(opponent);
+  $.getJSON(url, (data) => {
+    const tbody = $("#head-to-head-table tbody");
+    tbody.empty();
+
+    const formats = Object.keys(data.formats).sort();
+    if (formats.length === 0) {
+      $("#head-to-head-table").hide();
+      $("#head-to-head-message").text(
+        username + " and " + opponent + " have never raced each other."
+      );
+      return;
+    }
+    $("#head-to-head-message").text("");
+    $("#head-to-head-table").show();
+
+    formats.forEach((format) => {
+      const stats = data.formats[format];
+
+      // A negative gap means that this player was faster
+      let gap = "-";
+      if (stats.numGapRaces > 0) {
+        const seconds = Math.round(Math.abs(stats.averageGap) / 1000);
+        gap =
+          Math.floor(seconds / 60) +
+          ":" +
+          pad(seconds % 60, 2) +
+          (stats.averageGap < 0 ? " faster" : " slower");
+      }
+      const swing =
+        (stats.ratingSwing > 0 ? "+" : "") + stats.ratingSwing.toFixed(2);
+
+      const row = $("<tr>");
+      row.append($("<td>").text(format.charAt(0).toUpperCase() + format.slice(1)));
+      row.append($("<td>").text(stats.numRaces));
+      row.append($("<td>").text(stats.wins));
+      row.append($("<td>").text(stats.losses));
+      row.append($("<td>").text(stats.ties));
+      row.append($("<td>").text(gap));
+      row.append(
+        $("<td>")
+          .text(swing)
+          .addClass(stats.ratingSwing < 0 ? "red" : stats.ratingSwing > 0 ? "green" : "")
+      );
+      tbody.append(row);
+    });
+  }).fail((jqXHR) => {
+    $("#head-to-head-table").hide();
+    const error =
+      jqXHR.responseJSON && jqXHR.responseJSON.error
+        ? jqXHR.responseJSON.error
+        : "Something went wrong.";
+    $("#head-to-head-message").text(error);
+  });
+}
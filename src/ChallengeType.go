@@ -0,0 +1,8 @@
+package server
+
+type ChallengeType string
+
+const (
+	ChallengeTypeDaily  ChallengeType = "daily"
+	ChallengeTypeWeekly ChallengeType = "weekly"
+)
@@ -0,0 +1,9 @@
+package server
+
+type HallOfFameSubmissionStatus string
+
+const (
+	HallOfFameSubmissionStatusPending  HallOfFameSubmissionStatus = "pending"
+	HallOfFameSubmissionStatusApproved HallOfFameSubmissionStatus = "approved"
+	HallOfFameSubmissionStatusRejected HallOfFameSubmissionStatus = "rejected"
+)
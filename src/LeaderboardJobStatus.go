@@ -0,0 +1,12 @@
+package server
+
+type LeaderboardJobStatus string
+
+const (
+	LeaderboardJobStatusRunning   LeaderboardJobStatus = "running"
+	LeaderboardJobStatusReady     LeaderboardJobStatus = "ready"
+	LeaderboardJobStatusApplied   LeaderboardJobStatus = "applied"
+	LeaderboardJobStatusDiscarded LeaderboardJobStatus = "discarded"
+	LeaderboardJobStatusFailed    LeaderboardJobStatus = "failed"
+	LeaderboardJobStatusStale     LeaderboardJobStatus = "stale"
+)
@@ -0,0 +1,10 @@
+package server
+
+type RaceFlagType string
+
+const (
+	RaceFlagTypeRunTime RaceFlagType = "runTime"
+	RaceFlagTypeItem    RaceFlagType = "item"
+	RaceFlagTypeFloor   RaceFlagType = "floor"
+	RaceFlagTypeSeed    RaceFlagType = "seed"
+)
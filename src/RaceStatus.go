@@ -4,5 +4,7 @@ type RaceStatus string
 
 const (
 	RaceStatusOpen       RaceStatus = "open"
+	RaceStatusStarting   RaceStatus = "starting"
 	RaceStatusInProgress RaceStatus = "in progress"
+	RaceStatusFinished   RaceStatus = "finished"
 )
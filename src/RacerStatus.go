@@ -3,8 +3,13 @@ package server
 type RacerStatus string
 
 const (
+	RacerStatusNotReady     RacerStatus = "not ready"
 	RacerStatusReady        RacerStatus = "ready"
 	RacerStatusRacing       RacerStatus = "racing"
+	RacerStatusFinished     RacerStatus = "finished"
 	RacerStatusQuit         RacerStatus = "quit"
 	RacerStatusDisqualified RacerStatus = "disqualified"
+
+	// Only sent to clients; the racer keeps their real status while they are disconnected
+	RacerStatusDisconnected RacerStatus = "disconnected"
 )
@@ -0,0 +1,11 @@
+package server
+
+type SeasonCategory string
+
+const (
+	// Single-player speedruns that were submitted with a video, e.g. R+7 Season 1
+	SeasonCategorySpeedrun SeasonCategory = "speedrun"
+
+	// The online ranked solo leaderboard, which is reset at the end of every season
+	SeasonCategoryRankedSolo SeasonCategory = "ranked_solo"
+)
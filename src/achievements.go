@@ -2,6 +2,7 @@ package server
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/Zamiell/isaac-racing-server/models"
 )
@@ -136,6 +137,11 @@ func achievementsInit() {
 		420: {"Not Very \"Difficult\"", "Complete a race where you spawned as Judas' Shadow with more than 2 hearts."}, // Reference to EladDifficult
 		421: {"Bomb of Kings", "Complete a race where you killed Pin with 1 bomb and no other forms of damage."},       // Reference to the Battle of Kings showmatch series
 		422: {"Curse of the Full Clear", "Complete a race where you entered every room of The Chest."},
+
+		// Challenge streaks
+		501: {"Daily Routine", "Play the daily challenge 7 days in a row."},
+		502: {"Creature of Habit", "Play the daily challenge 30 days in a row."},
+		503: {"Regular Attendance", "Play the weekly challenge 4 weeks in a row."},
 	}
 
 	// Delete every row in the database
@@ -162,6 +168,7 @@ func achievementsCheck(userID int, username string) {
 
 	achievements1_8(userID, username, userAchievements)
 	achievements11_14(userID, username, userAchievements)
+	achievements501_503(userID, username, userAchievements)
 }
 
 // Achievement 1-8 (complete x races)
@@ -277,6 +284,57 @@ func achievements11_14(userID int, username string, userAchievements []int) {
 	// TODO
 }
 
+// Achievement 501-503 (challenge streaks)
+func achievements501_503(userID int, username string, userAchievements []int) {
+	if intInSlice(501, userAchievements) &&
+		intInSlice(502, userAchievements) &&
+		intInSlice(503, userAchievements) {
+
+		return
+	}
+
+	dailyStreak := achievementsGetChallengeStreak(userID, ChallengeTypeDaily)
+	weeklyStreak := achievementsGetChallengeStreak(userID, ChallengeTypeWeekly)
+
+	// Achievement 501 - Daily Routine - Play the daily challenge 7 days in a row.
+	if !intInSlice(501, userAchievements) {
+		if dailyStreak >= 7 {
+			achievementsGive(userID, username, 501)
+		}
+	}
+
+	// Achievement 502 - Creature of Habit - Play the daily challenge 30 days in a row.
+	if !intInSlice(502, userAchievements) {
+		if dailyStreak >= 30 {
+			achievementsGive(userID, username, 502)
+		}
+	}
+
+	// Achievement 503 - Regular Attendance - Play the weekly challenge 4 weeks in a row.
+	if !intInSlice(503, userAchievements) {
+		if weeklyStreak >= 4 {
+			achievementsGive(userID, username, 503)
+		}
+	}
+}
+
+func achievementsGetChallengeStreak(userID int, challengeType ChallengeType) int {
+	var starts []int64
+	if v, err := db.Challenges.GetParticipationStarts(userID, string(challengeType)); err != nil {
+		logger.Error("Database error while getting the "+string(challengeType)+" challenges for user "+strconv.Itoa(userID)+":", err)
+		return 0
+	} else {
+		starts = v
+	}
+
+	startTimes := make([]time.Time, 0, len(starts))
+	for _, start := range starts {
+		startTimes = append(startTimes, time.Unix(0, start*int64(time.Millisecond)))
+	}
+
+	return ChallengeGetLongestStreak(challengeType, startTimes)
+}
+
 func achievementsGive(userID int, username string, achievementID int) {
 	// Give them the achievement in the database
 	if err := db.UserAchievements.Insert(userID, achievementID); err != nil {
@@ -286,7 +344,7 @@ func achievementsGive(userID int, username string, achievementID int) {
 
 	// Send them a notification that they got this achievement
 	// (they should not be offline, but check just in case they went offline immediately after finishing)
-	s, ok := websocketSessions[username]
+	s, ok := websocketGetSession(username)
 	if ok {
 		type AchievementMessage struct {
 			ID          int    `json:"id"`
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Zamiell/isaac-racing-server/models"
+)
+
+/*
+	Daily and weekly challenges are solo seeded races where everyone plays the same seed, character,
+	and build, and only gets one attempt
+	Each challenge is created the first time that someone asks for it
+*/
+
+const (
+	ChallengeGoal = RaceGoalBlueBaby
+)
+
+var (
+	// Used to stop two people from creating the same challenge at the same time
+	challengesMutex = new(sync.Mutex)
+)
+
+// Get the start and end of the challenge that is running at the given time
+// (daily challenges start at midnight UTC and weekly challenges start on Monday at midnight UTC)
+func ChallengeGetPeriod(challengeType ChallengeType, t time.Time) (time.Time, time.Time) {
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	if challengeType == ChallengeTypeWeekly {
+		// In Go, weeks start on Sunday
+		daysSinceMonday := (int(start.Weekday()) + 6) % 7
+		start = start.AddDate(0, 0, -daysSinceMonday)
+		return start, start.AddDate(0, 0, 7)
+	}
+
+	return start, start.AddDate(0, 0, 1)
+}
+
+// Get the longest run of back-to-back challenges out of a list of challenge start times
+// (the start times must be sorted from oldest to newest)
+func ChallengeGetLongestStreak(challengeType ChallengeType, starts []time.Time) int {
+	longestStreak := 0
+	streak := 0
+	for i, start := range starts {
+		if i > 0 {
+			_, previousEnd := ChallengeGetPeriod(challengeType, starts[i-1])
+			if !start.Equal(previousEnd) {
+				streak = 0
+			}
+		}
+
+		streak++
+		if streak > longestStreak {
+			longestStreak = streak
+		}
+	}
+
+	return longestStreak
+}
+
+func challengeGetCurrent(challengeType ChallengeType) (models.Challenge, error) {
+	challengesMutex.Lock()
+	defer challengesMutex.Unlock()
+
+	start, end := ChallengeGetPeriod(challengeType, time.Now())
+	datetimeStart := start.UnixNano() / int64(time.Millisecond)
+	datetimeEnd := end.UnixNano() / int64(time.Millisecond)
+
+	if challenge, exists, err := db.Challenges.Get(string(challengeType), datetimeStart); err != nil {
+		return challenge, err
+	} else if exists {
+		return challenge, nil
+	}
+
+	// Nobody has asked for this challenge yet, so pick the seed, character, and build
+	ruleset := raceRerollRuleset(Ruleset{
+		Solo:       true,
+		Format:     RaceFormatSeeded,
+		Goal:       ChallengeGoal,
+		Difficulty: "normal",
+	}, true, true)
+	challenge := models.Challenge{
+		Type:          string(challengeType),
+		Character:     ruleset.Character,
+		Goal:          string(ruleset.Goal),
+		StartingBuild: ruleset.StartingBuild,
+		Seed:          ruleset.Seed,
+		DatetimeStart: datetimeStart,
+		DatetimeEnd:   datetimeEnd,
+	}
+	if challengeID, err := db.Challenges.Insert(&challenge); err != nil {
+		return challenge, err
+	} else {
+		challenge.ID = challengeID
+	}
+
+	logger.Info("Created the " + string(challengeType) + " challenge #" + strconv.Itoa(challenge.ID) + " (" + challenge.Character + ", build " + strconv.Itoa(challenge.StartingBuild) + ").")
+
+	return challenge, nil
+}
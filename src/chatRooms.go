@@ -4,7 +4,41 @@ package server
 	Chat room subroutines
 */
 
+// Get a copy of the list of users in a chat room
+func chatRoomsGetUsers(room string) ([]User, bool) {
+	chatRoomsMutex.Lock()
+	defer chatRoomsMutex.Unlock()
+
+	users, ok := chatRooms[room]
+	if !ok {
+		return nil, false
+	}
+
+	return append([]User{}, users...), true
+}
+
+// Get the names of all of the chat rooms that a user is in
+func chatRoomsGetRoomsForUser(username string) []string {
+	chatRoomsMutex.Lock()
+	defer chatRoomsMutex.Unlock()
+
+	rooms := make([]string, 0)
+	for room, users := range chatRooms {
+		for _, user := range users {
+			if user.Name == username {
+				rooms = append(rooms, room)
+				break
+			}
+		}
+	}
+
+	return rooms
+}
+
 func chatRoomsUpdate(username string, property string, newValue interface{}) {
+	chatRoomsMutex.Lock()
+	defer chatRoomsMutex.Unlock()
+
 	// Look for this user in all chat rooms
 	for room, users := range chatRooms {
 		// See if the user is in this chat room
@@ -36,7 +70,7 @@ func chatRoomsUpdate(username string, property string, newValue interface{}) {
 		// Send everyone in the room an update
 		for _, user := range users {
 			// All users in the chat room should be online, but check just in case
-			if s2, ok := websocketSessions[user.Name]; ok {
+			if s2, ok := websocketGetSession(user.Name); ok {
 				type RoomUpdateMessage struct {
 					Room string `json:"room"`
 					User User   `json:"user"`
@@ -52,3 +86,23 @@ func chatRoomsUpdate(username string, property string, newValue interface{}) {
 		}
 	}
 }
+
+// Send a message from the server to everyone in a chat room
+// (the message is not written to the chat log since it does not come from a user)
+func chatRoomsSendServerMessage(room string, message string) {
+	users, ok := chatRoomsGetUsers(room)
+	if !ok {
+		return
+	}
+
+	for _, user := range users {
+		// All users in the chat room should be online, but check just in case
+		if s, ok := websocketGetSession(user.Name); ok {
+			websocketEmit(s, "roomMessage", &RoomMessageMessage{
+				room,
+				"!server",
+				message,
+			})
+		}
+	}
+}
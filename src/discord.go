@@ -97,21 +97,24 @@ func discordMessageCreate(s *discordgo.Session, m *discordgo.MessageCreate) {
 		discordSend(m.ChannelID, "Everything in the mod has detailed documentation: https://github.com/Zamiell/racing-plus/blob/main/docs/CHANGES.md")
 	}
 
+	// Stats commands
+	if args := strings.Fields(m.Content); len(args) > 0 && strings.ToLower(args[0]) == "!h2h" {
+		discordSend(m.ChannelID, headToHeadChatCommand(args[1:]))
+	}
+
 	// Copy messages from "racing-plus-lobby"
 	if m.ChannelID == discordLobbyChannelID {
 		// Send everyone the notification
-		commandMutex.Lock()
 		type discordMessageMessage struct {
 			Name    string `json:"name"`
 			Message string `json:"message"`
 		}
-		for _, s := range websocketSessions {
+		for _, s := range websocketGetAllSessions() {
 			websocketEmit(s, "discordMessage", &discordMessageMessage{
 				Name:    m.Author.Username + "#" + m.Author.Discriminator,
 				Message: message,
 			})
 		}
-		commandMutex.Unlock()
 	}
 }
 
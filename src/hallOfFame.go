@@ -1,24 +1,76 @@
 package server
 
-// Used for single-player speedruns, e.g. R+7 S1
-type HallOfFameEntry struct {
-	Rank        int
-	Racer       string
-	ProfileName string
-	Time        int
-	Version     string
-	Date        string
-	Proof       string
-	Site        string
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Zamiell/isaac-racing-server/models"
+)
+
+const (
+	HallOfFameVersionMaxLength = 50
+	HallOfFameReasonMaxLength  = 300
+)
+
+// A season along with its standings
+type HallOfFameSeason struct {
+	ID         int
+	Name       string
+	RankedSolo bool // Otherwise, it is a speedrun season
+	Entries    []models.HallOfFameEntry
+}
+
+// Offline speedruns must be streamed to count, so the proof has to be a Twitch or YouTube link
+// (the second return value is false if the link is not from one of those sites)
+func HallOfFameGetProofSite(proof string) (string, bool) {
+	u, err := url.Parse(proof)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", false
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	host = strings.TrimPrefix(host, "m.")
+	switch host {
+	case "twitch.tv":
+		return "Twitch", true
+	case "youtube.com", "youtu.be":
+		return "YouTube", true
+	default:
+		return "", false
+	}
 }
 
-// Used for online seasons
-type HallOfFameEntryOnline struct {
-	Rank              int
-	Racer             string
-	ProfileName       string
-	AdjustedAverage   int
-	UnadjustedAverage int
-	ForfeitPenalty    int
-	NumForfeits       int
+// Copy an approved submission to the hall of fame and re-rank the season
+// (the first return value is false if the person already had a faster run)
+func hallOfFameApproveSubmission(submission models.HallOfFameSubmission) (bool, error) {
+	entry := models.HallOfFameEntry{
+		Racer:       submission.Username,
+		ProfileName: submission.Username,
+		Time:        submission.Time,
+		Version:     submission.Version,
+		Date:        time.Unix(submission.DatetimeCreated, 0).UTC().Format("2006-01-02"),
+		Proof:       submission.Proof,
+		Site:        submission.Site,
+	}
+
+	if existing, exists, err := db.HallOfFameEntries.GetSpeedrun(submission.SeasonID, submission.Username); err != nil {
+		return false, err
+	} else if exists {
+		if existing.Time <= submission.Time {
+			return false, nil
+		}
+
+		if err := db.HallOfFameEntries.SetSpeedrun(existing.ID, entry); err != nil {
+			return false, err
+		}
+	} else if err := db.HallOfFameEntries.InsertMany(submission.SeasonID, []models.HallOfFameEntry{entry}); err != nil {
+		return false, err
+	}
+
+	if err := db.HallOfFameEntries.RerankSpeedrun(submission.SeasonID); err != nil {
+		return false, err
+	}
+
+	return true, nil
 }
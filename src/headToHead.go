@@ -0,0 +1,236 @@
+package server
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Zamiell/isaac-racing-server/models"
+)
+
+/*
+	Head-to-head statistics between two racers, from the finished multiplayer races that they were
+	both in
+	Races where they were on the same team are skipped, since they were not racing each other
+*/
+
+type HeadToHead struct {
+	Player1 string                       `json:"player1"`
+	Player2 string                       `json:"player2"`
+	Formats map[string]*HeadToHeadFormat `json:"formats"` // Only the formats that they have raced each other in
+}
+
+// All of the stats are from the point of view of the first player
+type HeadToHeadFormat struct {
+	NumRaces int `json:"numRaces"`
+	Wins     int `json:"wins"`
+	Losses   int `json:"losses"`
+	Ties     int `json:"ties"` // Including races where neither of them finished
+
+	// The average of the first player's time minus the second player's time (in milliseconds),
+	// from the races where they both finished
+	// (this is negative if the first player is faster)
+	AverageGap  float64 `json:"averageGap"`
+	NumGapRaces int     `json:"numGapRaces"`
+
+	// The total rating that each player gained or lost in these races
+	// (the swing is the first change minus the second change)
+	RatingChange         float64 `json:"ratingChange"`
+	OpponentRatingChange float64 `json:"opponentRatingChange"`
+	RatingSwing          float64 `json:"ratingSwing"`
+}
+
+// Get the head-to-head statistics between two users, indexed by format
+func headToHeadGet(userID1 int, userID2 int) (map[string]*HeadToHeadFormat, error) {
+	var h2hRaces []models.HeadToHeadRace
+	if v, err := db.RaceParticipants.GetHeadToHead(userID1, userID2); err != nil {
+		return nil, err
+	} else {
+		h2hRaces = v
+	}
+
+	var ratingChanges1 map[int]float64
+	if v, err := headToHeadGetRatingChanges(userID1); err != nil {
+		return nil, err
+	} else {
+		ratingChanges1 = v
+	}
+
+	var ratingChanges2 map[int]float64
+	if v, err := headToHeadGetRatingChanges(userID2); err != nil {
+		return nil, err
+	} else {
+		ratingChanges2 = v
+	}
+
+	return HeadToHeadCalculate(h2hRaces, ratingChanges1, ratingChanges2), nil
+}
+
+// The rating changes are indexed by race ID
+// (races that are missing from a map did not change that player's rating)
+func HeadToHeadCalculate(
+	h2hRaces []models.HeadToHeadRace,
+	ratingChanges1 map[int]float64,
+	ratingChanges2 map[int]float64,
+) map[string]*HeadToHeadFormat {
+	formats := make(map[string]*HeadToHeadFormat)
+	gapSums := make(map[string]int64)
+
+	for _, race := range h2hRaces {
+		// Teammates are not racing each other
+		if race.Team1 != 0 && race.Team1 == race.Team2 {
+			continue
+		}
+
+		stats, ok := formats[race.Format]
+		if !ok {
+			stats = &HeadToHeadFormat{}
+			formats[race.Format] = stats
+		}
+		stats.NumRaces++
+
+		finished1 := race.Place1 > 0
+		finished2 := race.Place2 > 0
+		if finished1 && finished2 {
+			gapSums[race.Format] += race.RunTime1 - race.RunTime2
+			stats.NumGapRaces++
+		}
+
+		// Someone who finished always beats someone who did not
+		if finished1 && (!finished2 || race.Place1 < race.Place2) {
+			stats.Wins++
+		} else if finished2 && (!finished1 || race.Place2 < race.Place1) {
+			stats.Losses++
+		} else {
+			stats.Ties++
+		}
+
+		stats.RatingChange += ratingChanges1[race.RaceID]
+		stats.OpponentRatingChange += ratingChanges2[race.RaceID]
+	}
+
+	for format, stats := range formats {
+		if stats.NumGapRaces > 0 {
+			stats.AverageGap = float64(gapSums[format]) / float64(stats.NumGapRaces)
+		}
+		stats.RatingSwing = stats.RatingChange - stats.OpponentRatingChange
+	}
+
+	return formats
+}
+
+// Get how much someone's rating changed in every race that they have a rating history entry for
+// (indexed by race ID)
+func headToHeadGetRatingChanges(userID int) (map[int]float64, error) {
+	ratingChanges := make(map[int]float64)
+
+	var points []models.RatingHistoryPoint
+	if v, err := db.RatingHistory.GetUser(userID, ""); err != nil {
+		return ratingChanges, err
+	} else {
+		points = v
+	}
+
+	for _, point := range points {
+		// Ranked solo races are never head-to-head
+		// (and they do not have a rating change)
+		if point.RatingChange == nil {
+			continue
+		}
+
+		ratingChanges[point.RaceID] += *point.RatingChange
+	}
+
+	return ratingChanges, nil
+}
+
+/*
+	Chat command
+*/
+
+// Handle the "!h2h" command from Discord and Twitch
+// (the arguments are everything after the command)
+func headToHeadChatCommand(args []string) string {
+	if len(args) != 2 {
+		return "Usage: !h2h [racer] [racer]"
+	}
+
+	userIDs := make([]int, 0, len(args))
+	for _, name := range args {
+		if exists, userID, err := db.Users.Exists(name); err != nil {
+			logger.Error("Failed to check if player \""+name+"\" exists:", err)
+			return "Something went wrong. Please try again later."
+		} else if !exists {
+			return "The racer \"" + name + "\" does not exist."
+		} else {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	if userIDs[0] == userIDs[1] {
+		return "You have to specify two different racers."
+	}
+
+	var formats map[string]*HeadToHeadFormat
+	if v, err := headToHeadGet(userIDs[0], userIDs[1]); err != nil {
+		logger.Error("Failed to get the head-to-head statistics for \""+args[0]+"\" and \""+args[1]+"\":", err)
+		return "Something went wrong. Please try again later."
+	} else {
+		formats = v
+	}
+
+	return headToHeadGetChatMessage(args[0], args[1], formats)
+}
+
+// e.g. "Alice vs. Bob - seeded: 5-3-1, 0:12 faster on average, +4.2 rating swing"
+func headToHeadGetChatMessage(name1 string, name2 string, formats map[string]*HeadToHeadFormat) string {
+	message := name1 + " vs. " + name2
+	if len(formats) == 0 {
+		return message + " - They have never raced each other."
+	}
+
+	formatNames := make([]string, 0, len(formats))
+	for format := range formats {
+		formatNames = append(formatNames, format)
+	}
+	sort.Strings(formatNames)
+
+	formatMessages := make([]string, 0, len(formatNames))
+	for _, format := range formatNames {
+		stats := formats[format]
+		formatMessage := format + ": " +
+			strconv.Itoa(stats.Wins) + "-" +
+			strconv.Itoa(stats.Losses) + "-" +
+			strconv.Itoa(stats.Ties)
+		if stats.NumGapRaces > 0 {
+			formatMessage += ", " + headToHeadFormatGap(stats.AverageGap)
+		}
+		if stats.RatingSwing != 0 {
+			swing := strconv.FormatFloat(stats.RatingSwing, 'f', 1, 64)
+			if stats.RatingSwing > 0 {
+				swing = "+" + swing
+			}
+			formatMessage += ", " + swing + " rating swing"
+		}
+		formatMessages = append(formatMessages, formatMessage)
+	}
+
+	return message + " - " + strings.Join(formatMessages, " | ")
+}
+
+// e.g. "0:12 faster on average"
+func headToHeadFormatGap(gap float64) string {
+	comparison := "slower"
+	if gap < 0 {
+		comparison = "faster"
+	}
+
+	seconds := int(math.Round(math.Abs(gap) / 1000))
+	minutesString := strconv.Itoa(seconds / 60)
+	secondsString := strconv.Itoa(seconds % 60)
+	if len(secondsString) == 1 {
+		secondsString = "0" + secondsString
+	}
+
+	return minutesString + ":" + secondsString + " " + comparison + " on average"
+}
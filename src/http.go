@@ -50,6 +50,10 @@ type TemplateData struct {
 	NextPage          int
 	RaceResultsRanked []models.RaceHistory
 	RaceResultsAll    []models.RaceHistory
+	SingleRaceSeries  models.SeriesHistory
+	SingleRaceSplits  map[string][]models.RaceHistorySplit
+	InSeries          bool
+	SeriesResults     []models.SeriesHistory
 
 	// Profiles/profile stuff
 	ResultsProfiles   []models.ProfilesRow
@@ -64,21 +68,16 @@ type TemplateData struct {
 	LeaderboardUnseeded   []models.LeaderboardRowUnseeded
 	LeaderboardDiversity  []models.LeaderboardRowDiversity
 	LeaderboardRankedSolo []models.LeaderboardRowUnseededSolo
+	RankedSoloSeasonName  string
 
 	// Hall of Fame stuff
-	Season1R9AB       []HallOfFameEntry
-	Season1R14AB      []HallOfFameEntry
-	Season2R7AB       []HallOfFameEntry
-	Season3R7AB       []HallOfFameEntry
-	Season4R7AB       []HallOfFameEntry
-	Season5R7AB       []HallOfFameEntry
-	Season6R7AB       []HallOfFameEntry
-	Season7R7AB       []HallOfFameEntry
-	Season8R7AB       []HallOfFameEntry
-	Season1R7Rep      []HallOfFameEntry
-	Season2R7Rep      []HallOfFameEntry
-	Season1RankedSolo []HallOfFameEntryOnline
-	Season2RankedSolo []HallOfFameEntryOnline
+	HallOfFame []HallOfFameSeason
+
+	// Challenges stuff
+	ChallengesDaily      []models.ChallengeHistory
+	ChallengesWeekly     []models.ChallengeHistory
+	SingleChallenge      models.ChallengeHistory
+	ChallengeLeaderboard []models.ChallengeLeaderboardRow
 
 	// Tournament Stuff
 	CurrentTournament bool
@@ -180,6 +179,12 @@ func httpInit() {
 	httpRouter.POST("/register", httpRegister)
 	httpRouter.GET("/ws", httpWS)
 
+	// Path handlers (for the API)
+	httpRouter.GET("/api/race/:raceid/timeline", httpRaceTimeline)
+	httpRouter.GET("/api/profile/:player/ratings", httpRatingHistory)
+	httpRouter.GET("/api/leaderboards/:format", httpLeaderboardAPI)
+	httpRouter.GET("/api/h2h/:a/:b", httpHeadToHead)
+
 	// Path handlers (for the website)
 	httpRouter.GET("/", httpHome)
 	httpRouter.GET("/news", httpNews)
@@ -191,6 +196,8 @@ func httpInit() {
 	httpRouter.GET("/profiles/:page", httpProfiles) // Handles extra pages for profiles
 	httpRouter.GET("/profile", httpProfile)
 	httpRouter.GET("/profile/:player", httpProfile) // Handles profile username
+	httpRouter.GET("/challenges", httpChallenges)
+	httpRouter.GET("/challenge/:challengeid", httpChallenge)
 	httpRouter.GET("/tournaments", httpTournament)
 	httpRouter.GET("/leaderboards", httpLeaderboards)
 	httpRouter.GET("/info", httpInfo)
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Zamiell/isaac-racing-server/models"
+	"github.com/gin-gonic/gin"
+)
+
+func httpChallenges(c *gin.Context) {
+	w := c.Writer
+	dailyChallengesShown := 30
+	weeklyChallengesShown := 12
+
+	// Make sure that the current challenges exist so that they show up at the top of the page
+	for _, challengeType := range []ChallengeType{ChallengeTypeDaily, ChallengeTypeWeekly} {
+		if _, err := challengeGetCurrent(challengeType); err != nil {
+			logger.Error("Failed to get the current "+string(challengeType)+" challenge: ", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	challengesDaily, err := db.Challenges.GetChallengesHistory(string(ChallengeTypeDaily), dailyChallengesShown)
+	if err != nil {
+		logger.Error("Failed to get the daily challenges: ", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	challengesWeekly, err := db.Challenges.GetChallengesHistory(string(ChallengeTypeWeekly), weeklyChallengesShown)
+	if err != nil {
+		logger.Error("Failed to get the weekly challenges: ", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	for i := range challengesDaily {
+		httpChallengeFill(&challengesDaily[i])
+	}
+	for i := range challengesWeekly {
+		httpChallengeFill(&challengesWeekly[i])
+	}
+
+	data := TemplateData{
+		Title:            "Challenges",
+		ChallengesDaily:  challengesDaily,
+		ChallengesWeekly: challengesWeekly,
+	}
+
+	httpServeTemplate(w, "challenges", data)
+}
+
+func httpChallenge(c *gin.Context) {
+	w := c.Writer
+
+	challengeID, err := strconv.ParseInt(c.Params.ByName("challengeid"), 10, 32)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	challengeData, exists, err := db.Challenges.GetChallengeHistory(int(challengeID))
+	if err != nil {
+		logger.Error("Failed to get the challenge data: ", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	} else if !exists {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+	httpChallengeFill(&challengeData)
+
+	leaderboard, err := db.Challenges.GetChallengeLeaderboard(int(challengeID))
+	if err != nil {
+		logger.Error("Failed to get the challenge leaderboard: ", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	data := TemplateData{
+		Title:                "Challenge",
+		SingleChallenge:      challengeData,
+		ChallengeLeaderboard: leaderboard,
+	}
+
+	httpServeTemplate(w, "challenge", data)
+}
+
+func httpChallengeFill(challenge *models.ChallengeHistory) {
+	startingBuildIndex := int(challenge.ChallengeStartingBuild.Int64)
+	challenge.ChallengeStartingBuildName = getBuildName(startingBuildIndex)
+	challenge.ChallengeStartingBuildID = getBuildID(startingBuildIndex)
+
+	// Nobody should be able to see the seed until everyone has had a chance to play it
+	if challenge.ChallengeOngoing.Bool {
+		challenge.ChallengeSeed.String = "(hidden until the challenge ends)"
+	}
+}
@@ -1,27 +1,42 @@
 package server
 
 import (
+	"net/http"
+
+	"github.com/Zamiell/isaac-racing-server/models"
 	"github.com/gin-gonic/gin"
 )
 
 func httpHallOfFame(c *gin.Context) {
 	w := c.Writer
 
+	var entries map[int][]models.HallOfFameEntry
+	if v, err := db.HallOfFameEntries.GetAll(); err != nil {
+		logger.Error("Failed to get the hall of fame entries:", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	} else {
+		entries = v
+	}
+
+	// Seasons that are over are shown, along with speedrun seasons that have approved runs
+	// (ranked solo seasons in progress are shown on the leaderboards page instead)
+	hallOfFame := make([]HallOfFameSeason, 0)
+	for _, season := range seasonsGetAll() {
+		if !season.Archived && (season.Category != string(SeasonCategorySpeedrun) || len(entries[season.ID]) == 0) {
+			continue
+		}
+		hallOfFame = append(hallOfFame, HallOfFameSeason{
+			ID:         season.ID,
+			Name:       season.Name,
+			RankedSolo: season.Category == string(SeasonCategoryRankedSolo),
+			Entries:    entries[season.ID],
+		})
+	}
+
 	data := TemplateData{
-		Title:             "Hall of Fame",
-		Season1R9AB:       season1R9AB,
-		Season1R14AB:      season1R14AB,
-		Season2R7AB:       season2R7AB,
-		Season3R7AB:       season3R7AB,
-		Season4R7AB:       season4R7AB,
-		Season5R7AB:       season5R7AB,
-		Season6R7AB:       season6R7AB,
-		Season7R7AB:       season7R7AB,
-		Season8R7AB:       season8R7AB,
-		Season1R7Rep:      season1R7Rep,
-		Season2R7Rep:      season2R7Rep,
-		Season1RankedSolo: season1RankedSolo,
-		Season2RankedSolo: season2RankedSolo,
+		Title:      "Hall of Fame",
+		HallOfFame: hallOfFame,
 	}
 	httpServeTemplate(w, "halloffame", data)
 }
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*
+	The head-to-head statistics between two players
+	(this is used on the profile page)
+*/
+
+func httpHeadToHead(c *gin.Context) {
+	// Parse the player names from the URL
+	players := []string{
+		c.Params.ByName("a"),
+		c.Params.ByName("b"),
+	}
+
+	playerIDs := make([]int, 0, len(players))
+	for _, player := range players {
+		if exists, v, err := db.Users.Exists(player); err != nil {
+			logger.Error("Failed to check if player \""+player+"\" exists:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+			return
+		} else if !exists {
+			c.JSON(http.StatusNotFound, gin.H{"error": "The player \"" + player + "\" does not exist."})
+			return
+		} else {
+			playerIDs = append(playerIDs, v)
+		}
+	}
+	if playerIDs[0] == playerIDs[1] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The two players must be different."})
+		return
+	}
+
+	var formats map[string]*HeadToHeadFormat
+	if v, err := headToHeadGet(playerIDs[0], playerIDs[1]); err != nil {
+		logger.Error("Failed to get the head-to-head statistics for \""+players[0]+"\" and \""+players[1]+"\":", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	} else {
+		formats = v
+	}
+
+	c.JSON(http.StatusOK, HeadToHead{
+		Player1: players[0],
+		Player2: players[1],
+		Formats: formats,
+	})
+}
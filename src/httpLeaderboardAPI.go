@@ -0,0 +1,440 @@
+package server
+
+import (
+	"database/sql"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Zamiell/isaac-racing-server/models"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+	The leaderboards as JSON
+	(this is used by community bots, spreadsheets, and the tournament bot)
+
+	Query parameters (all optional):
+	- page: starts at 1
+	- perPage: defaults to 50
+	- minRaces: defaults to the same threshold as the leaderboards page
+	- season: the ID of a ranked solo season (defaults to the current one)
+	- sort: any column of the returned rows (defaults to "rank")
+	- order: either "asc" or "desc"
+*/
+
+const (
+	LeaderboardAPIDefaultPerPage = 50
+	LeaderboardAPIMaxPerPage     = 500
+)
+
+type LeaderboardResponse struct {
+	Format     string      `json:"format"`
+	Season     string      `json:"season"`
+	MinRaces   int         `json:"minRaces"`
+	Sort       string      `json:"sort"`
+	Order      string      `json:"order"`
+	Page       int         `json:"page"`
+	PerPage    int         `json:"perPage"`
+	TotalRows  int         `json:"totalRows"`
+	TotalPages int         `json:"totalPages"`
+	Rows       interface{} `json:"rows"`
+}
+
+// Used for the seeded, unseeded, and diversity leaderboards
+type LeaderboardTrueSkillRow struct {
+	Rank            int     `json:"rank"`
+	Name            string  `json:"name"`
+	TrueSkill       float64 `json:"trueSkill"`
+	TrueSkillChange float64 `json:"trueSkillChange"`
+	NumRaces        int64   `json:"numRaces"`
+	LowestTime      int64   `json:"lowestTime"` // In milliseconds
+	LastRace        int64   `json:"lastRace"`   // Epoch timestamp in milliseconds
+	LastRaceID      int     `json:"lastRaceID"` // nolint:tagliatelle
+	Verified        bool    `json:"verified"`
+	StreamURL       string  `json:"streamURL"` // nolint:tagliatelle
+}
+
+// Archived seasons only have the averages and the forfeits, so the other columns will be 0
+type LeaderboardRankedSoloRow struct {
+	Rank            int    `json:"rank"`
+	Name            string `json:"name"`
+	AdjustedAverage int    `json:"adjustedAverage"` // In milliseconds
+	RealAverage     int    `json:"realAverage"`     // In milliseconds
+	NumRaces        int    `json:"numRaces"`
+	NumForfeits     int    `json:"numForfeits"`
+	ForfeitPenalty  int    `json:"forfeitPenalty"` // In milliseconds
+	LowestTime      int    `json:"lowestTime"`     // In milliseconds
+	LastRace        int64  `json:"lastRace"`       // Epoch timestamp in milliseconds
+	LastRaceID      int    `json:"lastRaceID"`     // nolint:tagliatelle
+	Verified        bool   `json:"verified"`
+	StreamURL       string `json:"streamURL"` // nolint:tagliatelle
+}
+
+var (
+	// The columns that can be sorted on, other than "name"
+	leaderboardTrueSkillColumns = map[string]func(row *LeaderboardTrueSkillRow) float64{
+		"rank":            func(row *LeaderboardTrueSkillRow) float64 { return float64(row.Rank) },
+		"trueSkill":       func(row *LeaderboardTrueSkillRow) float64 { return row.TrueSkill },
+		"trueSkillChange": func(row *LeaderboardTrueSkillRow) float64 { return row.TrueSkillChange },
+		"numRaces":        func(row *LeaderboardTrueSkillRow) float64 { return float64(row.NumRaces) },
+		"lowestTime":      func(row *LeaderboardTrueSkillRow) float64 { return float64(row.LowestTime) },
+		"lastRace":        func(row *LeaderboardTrueSkillRow) float64 { return float64(row.LastRace) },
+	}
+	leaderboardRankedSoloColumns = map[string]func(row *LeaderboardRankedSoloRow) float64{
+		"rank":            func(row *LeaderboardRankedSoloRow) float64 { return float64(row.Rank) },
+		"adjustedAverage": func(row *LeaderboardRankedSoloRow) float64 { return float64(row.AdjustedAverage) },
+		"realAverage":     func(row *LeaderboardRankedSoloRow) float64 { return float64(row.RealAverage) },
+		"numRaces":        func(row *LeaderboardRankedSoloRow) float64 { return float64(row.NumRaces) },
+		"numForfeits":     func(row *LeaderboardRankedSoloRow) float64 { return float64(row.NumForfeits) },
+		"forfeitPenalty":  func(row *LeaderboardRankedSoloRow) float64 { return float64(row.ForfeitPenalty) },
+		"lowestTime":      func(row *LeaderboardRankedSoloRow) float64 { return float64(row.LowestTime) },
+		"lastRace":        func(row *LeaderboardRankedSoloRow) float64 { return float64(row.LastRace) },
+	}
+)
+
+func httpLeaderboardAPI(c *gin.Context) {
+	format := c.Params.ByName("format")
+
+	response := LeaderboardResponse{
+		Format:  format,
+		Sort:    c.DefaultQuery("sort", "rank"),
+		Order:   c.DefaultQuery("order", "asc"),
+		Page:    1,
+		PerPage: LeaderboardAPIDefaultPerPage,
+	}
+
+	/*
+		Validation
+	*/
+
+	// Validate the format and get the default threshold for it
+	switch format {
+	case string(RaceFormatSeeded):
+		response.MinRaces = LeaderboardSeededRacesNeeded
+	case string(RaceFormatUnseeded):
+		response.MinRaces = LeaderboardUnseededRacesNeeded
+	case string(RaceFormatDiversity):
+		response.MinRaces = LeaderboardDiversityRacesNeeded
+	case "ranked_solo":
+		response.MinRaces = LeaderboardRankedSoloRacesNeeded
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "That is not a valid format."})
+		return
+	}
+
+	if v, ok := httpLeaderboardAPIGetInt(c, "page", 1, 1); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The page must be a positive number."})
+		return
+	} else {
+		response.Page = v
+	}
+
+	if v, ok := httpLeaderboardAPIGetInt(c, "perPage", response.PerPage, 1); !ok || v > LeaderboardAPIMaxPerPage {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The number of rows per page must be between 1 and " + strconv.Itoa(LeaderboardAPIMaxPerPage) + "."})
+		return
+	} else {
+		response.PerPage = v
+	}
+
+	// A threshold of 0 is allowed so that everyone can be shown
+	if v, ok := httpLeaderboardAPIGetInt(c, "minRaces", response.MinRaces, 0); !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The minimum number of races must be 0 or more."})
+		return
+	} else {
+		response.MinRaces = v
+	}
+
+	if response.Order != "asc" && response.Order != "desc" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The order must be either \"asc\" or \"desc\"."})
+		return
+	}
+
+	validSort := response.Sort == "name"
+	if format == "ranked_solo" {
+		if _, ok := leaderboardRankedSoloColumns[response.Sort]; ok {
+			validSort = true
+		}
+	} else if _, ok := leaderboardTrueSkillColumns[response.Sort]; ok {
+		validSort = true
+	}
+	if !validSort {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "That is not a valid column to sort by."})
+		return
+	}
+
+	// Only ranked solo has seasons that can be looked at
+	var season models.Season
+	seasonParam := c.Query("season")
+	if seasonParam != "" {
+		if format != "ranked_solo" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Only the ranked_solo leaderboard has seasons."})
+			return
+		}
+
+		var ok bool
+		if seasonID, err := strconv.Atoi(seasonParam); err == nil {
+			season, ok = seasonsGet(seasonID)
+		}
+		if !ok || season.Category != string(SeasonCategoryRankedSolo) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "That is not a valid ranked solo season."})
+			return
+		}
+	} else if format == "ranked_solo" {
+		if v, ok := seasonGetCurrentRankedSolo(); ok {
+			season = v
+		}
+	}
+	response.Season = season.Name
+
+	/*
+		Build the leaderboard
+	*/
+
+	var rows interface{}
+	var totalRows int
+	if format == "ranked_solo" {
+		var soloRows []LeaderboardRankedSoloRow
+		if v, err := httpLeaderboardAPIGetRankedSolo(season, response.MinRaces); err != nil {
+			logger.Error("Failed to get the ranked solo leaderboard:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+			return
+		} else {
+			soloRows = v
+		}
+
+		if getValue, ok := leaderboardRankedSoloColumns[response.Sort]; ok {
+			sort.SliceStable(soloRows, func(i, j int) bool {
+				return leaderboardLess(getValue(&soloRows[i]), getValue(&soloRows[j]), response.Order)
+			})
+		} else {
+			sort.SliceStable(soloRows, func(i, j int) bool {
+				return leaderboardLessName(soloRows[i].Name, soloRows[j].Name, response.Order)
+			})
+		}
+
+		totalRows = len(soloRows)
+		start, end, _ := LeaderboardPaginate(totalRows, response.Page, response.PerPage)
+		rows = soloRows[start:end]
+	} else {
+		var trueSkillRows []LeaderboardTrueSkillRow
+		if v, err := httpLeaderboardAPIGetTrueSkill(RaceFormat(format), response.MinRaces); err != nil {
+			logger.Error("Failed to get the "+format+" leaderboard:", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+			return
+		} else {
+			trueSkillRows = v
+		}
+
+		if getValue, ok := leaderboardTrueSkillColumns[response.Sort]; ok {
+			sort.SliceStable(trueSkillRows, func(i, j int) bool {
+				return leaderboardLess(getValue(&trueSkillRows[i]), getValue(&trueSkillRows[j]), response.Order)
+			})
+		} else {
+			sort.SliceStable(trueSkillRows, func(i, j int) bool {
+				return leaderboardLessName(trueSkillRows[i].Name, trueSkillRows[j].Name, response.Order)
+			})
+		}
+
+		totalRows = len(trueSkillRows)
+		start, end, _ := LeaderboardPaginate(totalRows, response.Page, response.PerPage)
+		rows = trueSkillRows[start:end]
+	}
+
+	response.Rows = rows
+	response.TotalRows = totalRows
+	_, _, response.TotalPages = LeaderboardPaginate(totalRows, response.Page, response.PerPage)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// The rows are in the same order as the leaderboards page
+func httpLeaderboardAPIGetTrueSkill(format RaceFormat, minRaces int) ([]LeaderboardTrueSkillRow, error) {
+	rows := make([]LeaderboardTrueSkillRow, 0)
+
+	switch format {
+	case RaceFormatSeeded:
+		leaderboard, err := db.Users.GetLeaderboardSeeded(minRaces)
+		if err != nil {
+			return rows, err
+		}
+		for _, row := range leaderboard {
+			rows = append(rows, LeaderboardTrueSkillRow{
+				Name:            row.Name,
+				TrueSkill:       row.SeededTrueSkill,
+				TrueSkillChange: row.SeededTrueSkillDelta,
+				NumRaces:        row.SeededNumRaces.Int64,
+				LowestTime:      row.SeededLowestTime.Int64,
+				LastRace:        leaderboardGetTimestamp(row.SeededLastRace),
+				LastRaceID:      row.SeededLastRaceID,
+				Verified:        row.Verified == 1,
+				StreamURL:       row.StreamURL,
+			})
+		}
+
+	case RaceFormatUnseeded:
+		leaderboard, err := db.Users.GetLeaderboardUnseeded(minRaces)
+		if err != nil {
+			return rows, err
+		}
+		for _, row := range leaderboard {
+			rows = append(rows, LeaderboardTrueSkillRow{
+				Name:            row.Name,
+				TrueSkill:       row.UnseededTrueSkill,
+				TrueSkillChange: row.UnseededTrueSkillDelta,
+				NumRaces:        row.UnseededNumRaces.Int64,
+				LowestTime:      row.UnseededLowestTime.Int64,
+				LastRace:        leaderboardGetTimestamp(row.UnseededLastRace),
+				LastRaceID:      row.UnseededLastRaceID,
+				Verified:        row.Verified == 1,
+				StreamURL:       row.StreamURL,
+			})
+		}
+
+	case RaceFormatDiversity:
+		leaderboard, err := db.Users.GetLeaderboardDiversity(minRaces)
+		if err != nil {
+			return rows, err
+		}
+		for _, row := range leaderboard {
+			rows = append(rows, LeaderboardTrueSkillRow{
+				Name:            row.Name,
+				TrueSkill:       row.DivTrueSkill,
+				TrueSkillChange: row.DivTrueSkillDelta,
+				NumRaces:        row.DivNumRaces.Int64,
+				LowestTime:      row.DivLowestTime.Int64,
+				LastRace:        leaderboardGetTimestamp(row.DivLastRace),
+				LastRaceID:      row.DivLastRaceID,
+				Verified:        row.Verified == 1,
+				StreamURL:       row.StreamURL,
+			})
+		}
+	}
+
+	for i := range rows {
+		rows[i].Rank = i + 1
+	}
+
+	return rows, nil
+}
+
+// Seasons that are over come from the hall of fame, since the users table has already been reset
+func httpLeaderboardAPIGetRankedSolo(season models.Season, minRaces int) ([]LeaderboardRankedSoloRow, error) {
+	rows := make([]LeaderboardRankedSoloRow, 0)
+
+	if season.Archived {
+		var entries map[int][]models.HallOfFameEntry
+		if v, err := db.HallOfFameEntries.GetAll(); err != nil {
+			return rows, err
+		} else {
+			entries = v
+		}
+
+		for _, entry := range entries[season.ID] {
+			if entry.NumRaces < minRaces {
+				continue
+			}
+			rows = append(rows, LeaderboardRankedSoloRow{
+				Rank:            entry.Rank,
+				Name:            entry.Racer,
+				AdjustedAverage: entry.AdjustedAverage * 1000,
+				RealAverage:     entry.UnadjustedAverage * 1000,
+				NumRaces:        entry.NumRaces,
+				NumForfeits:     entry.NumForfeits,
+				ForfeitPenalty:  entry.ForfeitPenalty * 1000,
+			})
+		}
+
+		return rows, nil
+	}
+
+	leaderboard, err := db.Users.GetLeaderboardRankedSolo(minRaces)
+	if err != nil {
+		return rows, err
+	}
+	for i, row := range leaderboard {
+		rows = append(rows, LeaderboardRankedSoloRow{
+			Rank:            i + 1,
+			Name:            row.Name,
+			AdjustedAverage: row.AdjustedAverage,
+			RealAverage:     row.RealAverage,
+			NumRaces:        row.NumRaces,
+			NumForfeits:     row.NumForfeits,
+			ForfeitPenalty:  row.ForfeitPenalty,
+			LowestTime:      row.LowestTime,
+			LastRace:        leaderboardGetTimestamp(row.LastRace),
+			LastRaceID:      row.LastRaceID,
+			Verified:        row.Verified == 1,
+			StreamURL:       row.StreamURL,
+		})
+	}
+
+	return rows, nil
+}
+
+// Get the slice bounds for a page of rows, along with the total number of pages
+// (pages past the end are empty)
+func LeaderboardPaginate(totalRows int, page int, perPage int) (int, int, int) {
+	totalPages := (totalRows + perPage - 1) / perPage
+
+	// The page comes from the query string, so it has to be clamped before it is multiplied
+	// (otherwise, a huge page number could overflow)
+	if page > totalPages+1 {
+		page = totalPages + 1
+	}
+
+	start := (page - 1) * perPage
+	if start > totalRows {
+		start = totalRows
+	}
+	end := start + perPage
+	if end > totalRows {
+		end = totalRows
+	}
+
+	return start, end, totalPages
+}
+
+/*
+	Subroutines
+*/
+
+// The second return value is false if the parameter is not a number or is below the minimum
+func httpLeaderboardAPIGetInt(c *gin.Context, key string, defaultValue int, minValue int) (int, bool) {
+	value := c.Query(key)
+	if value == "" {
+		return defaultValue, true
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil || i < minValue {
+		return 0, false
+	}
+
+	return i, true
+}
+
+func leaderboardLess(a float64, b float64, order string) bool {
+	if order == "desc" {
+		return a > b
+	}
+	return a < b
+}
+
+func leaderboardLessName(a string, b string, order string) bool {
+	a = strings.ToLower(a)
+	b = strings.ToLower(b)
+	if order == "desc" {
+		return a > b
+	}
+	return a < b
+}
+
+func leaderboardGetTimestamp(datetime sql.NullTime) int64 {
+	if !datetime.Valid {
+		return 0
+	}
+	return datetime.Time.UnixNano() / int64(time.Millisecond)
+}
@@ -33,33 +33,29 @@ type LeaderboardUnseeded []models.LeaderboardRowUnseeded
 
 func httpLeaderboards(c *gin.Context) {
 	w := c.Writer
-	seededRacesNeeded := 5
-	unseededRacesNeeded := 5
-	unseededSoloRacesNeeded := 20
-	diversityRacesNeeded := 10
 
-	leaderboardSeeded, err := db.Users.GetLeaderboardSeeded(seededRacesNeeded)
+	leaderboardSeeded, err := db.Users.GetLeaderboardSeeded(LeaderboardSeededRacesNeeded)
 	if err != nil {
 		logger.Error("Failed to get the seeded leaderboard:", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
 
-	leaderboardUnseeded, err := db.Users.GetLeaderboardUnseeded(unseededRacesNeeded)
+	leaderboardUnseeded, err := db.Users.GetLeaderboardUnseeded(LeaderboardUnseededRacesNeeded)
 	if err != nil {
 		logger.Error("Failed to get the unseeded leaderboard:", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
 
-	leaderboardDiversity, err := db.Users.GetLeaderboardDiversity(diversityRacesNeeded)
+	leaderboardDiversity, err := db.Users.GetLeaderboardDiversity(LeaderboardDiversityRacesNeeded)
 	if err != nil {
 		logger.Error("Failed to get the diversity leaderboard:", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
 
-	leaderboardRankedSolo, err := db.Users.GetLeaderboardRankedSolo(unseededSoloRacesNeeded)
+	leaderboardRankedSolo, err := db.Users.GetLeaderboardRankedSolo(LeaderboardRankedSoloRacesNeeded)
 	if err != nil {
 		logger.Error("Failed to get the ranked solo leaderboard:", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -76,12 +72,18 @@ func httpLeaderboards(c *gin.Context) {
 	// Construct the "Most Races Played" leaderboard
 	// TODO
 
+	rankedSoloSeasonName := "Ranked Solo"
+	if season, ok := seasonGetCurrentRankedSolo(); ok {
+		rankedSoloSeasonName = season.Name
+	}
+
 	data := TemplateData{
 		Title:                 "Leaderboards",
 		LeaderboardSeeded:     leaderboardSeeded,
 		LeaderboardUnseeded:   leaderboardUnseeded,
 		LeaderboardDiversity:  leaderboardDiversity,
 		LeaderboardRankedSolo: leaderboardRankedSolo,
+		RankedSoloSeasonName:  rankedSoloSeasonName,
 	}
 
 	httpServeTemplate(w, "leaderboards", data)
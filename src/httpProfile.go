@@ -10,6 +10,7 @@ import (
 func httpProfile(c *gin.Context) {
 	w := c.Writer
 	racesAllTotal := 5
+	seriesTotal := 5
 
 	// Parse the player name from the URL
 	player := c.Params.ByName("player")
@@ -65,6 +66,13 @@ func httpProfile(c *gin.Context) {
 		return
 	}
 
+	seriesData, err := db.Series.GetSeriesProfileHistory(player, seriesTotal)
+	if err != nil {
+		logger.Error("Failed to get the series data: ", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
 	for i := range raceDataSoloRanked {
 		raceDataSoloRanked[i].RaceFormat.String = strings.Title(raceDataSoloRanked[i].RaceFormat.String)
 		for p := range raceDataSoloRanked[i].RaceParticipants {
@@ -95,6 +103,7 @@ func httpProfile(c *gin.Context) {
 		TotalTime:         totalTime,
 		RaceResultsRanked: raceDataSoloRanked,
 		RaceResultsAll:    raceDataAll,
+		SeriesResults:     seriesData,
 	}
 
 	httpServeTemplate(w, "profile", data)
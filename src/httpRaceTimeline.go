@@ -0,0 +1,167 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Zamiell/isaac-racing-server/models"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+	Every item, room, and floor for every racer in a finished race, as one ordered stream of events
+	(this is used for things like VOD-sync overlays and post-race analysis)
+*/
+
+type RaceTimeline struct {
+	ID              int                 `json:"id"`
+	Name            string              `json:"name"`
+	Format          string              `json:"format"`
+	Character       string              `json:"character"`
+	Goal            string              `json:"goal"`
+	TeamSize        int                 `json:"teamSize"`
+	DatetimeStarted int64               `json:"datetimeStarted"`
+	Racers          []RaceTimelineRacer `json:"racers"`
+	Events          []RaceTimelineEvent `json:"events"`
+}
+
+type RaceTimelineRacer struct {
+	Name    string `json:"name"`
+	Place   int    `json:"place"`
+	RunTime int64  `json:"runTime"`
+	Team    int    `json:"team"`
+}
+
+type RaceTimelineEvent struct {
+	Name             string `json:"name"`
+	Type             string `json:"type"` // "floor", "room", or "item"
+	Time             int64  `json:"time"` // In milliseconds since the race started
+	Datetime         int64  `json:"datetime"`
+	FloorNum         int    `json:"floorNum"`
+	AdjustedFloorNum int    `json:"adjustedFloorNum"`
+	StageType        int    `json:"stageType"`
+	ItemID           int    `json:"itemID,omitempty"` // nolint:tagliatelle
+	ItemName         string `json:"itemName,omitempty"`
+	RoomID           string `json:"roomID,omitempty"` // nolint:tagliatelle
+}
+
+func httpRaceTimeline(c *gin.Context) {
+	raceID, err := strconv.Atoi(c.Params.ByName("raceid"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "That is not a valid race ID."})
+		return
+	}
+
+	race, finished, err := db.Races.GetFinished(raceID)
+	if err != nil {
+		logger.Error("Failed to get race #"+strconv.Itoa(raceID)+":", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	if !finished {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Race #" + strconv.Itoa(raceID) + " does not exist or has not finished yet."})
+		return
+	}
+
+	// The route of a challenge race would give away the seed to people who can still play it
+	if ongoing, err := db.Challenges.RaceIsOngoing(raceID); err != nil {
+		logger.Error("Failed to check the challenge for race #"+strconv.Itoa(raceID)+":", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	} else if ongoing {
+		c.JSON(http.StatusNotFound, gin.H{"error": "The timeline for race #" + strconv.Itoa(raceID) + " is hidden until the challenge ends."})
+		return
+	}
+
+	participants, err := db.Races.GetTimelineParticipants(raceID)
+	if err != nil {
+		logger.Error("Failed to get the participants for race #"+strconv.Itoa(raceID)+":", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	timelineRows, err := db.Races.GetTimelineRows(raceID)
+	if err != nil {
+		logger.Error("Failed to get the timeline for race #"+strconv.Itoa(raceID)+":", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
+	timeline := RaceTimeline{
+		ID:              race.ID,
+		Name:            race.Name,
+		Format:          race.Format,
+		Character:       race.Character,
+		Goal:            race.Goal,
+		TeamSize:        race.TeamSize,
+		DatetimeStarted: race.DatetimeStarted,
+		Racers:          make([]RaceTimelineRacer, 0),
+		Events:          make([]RaceTimelineEvent, 0),
+	}
+	for _, participant := range participants {
+		timeline.Racers = append(timeline.Racers, RaceTimelineRacer{
+			Name:    participant.Name,
+			Place:   participant.Place,
+			RunTime: participant.RunTime,
+			Team:    participant.Team,
+		})
+	}
+
+	// Races from before splits were recorded only have rooms,
+	// so for those racers we make the floor events from the first room on each floor
+	racersWithSplits := make(map[string]bool)
+	for _, timelineRow := range timelineRows {
+		if timelineRow.Type == "floor" {
+			racersWithSplits[timelineRow.Name] = true
+		}
+	}
+	type Floor struct {
+		FloorNum  int
+		StageType int
+	}
+	lastRoomFloor := make(map[string]Floor)
+
+	for _, timelineRow := range timelineRows {
+		if timelineRow.Type == "room" && !racersWithSplits[timelineRow.Name] {
+			lastFloor, ok := lastRoomFloor[timelineRow.Name]
+			if !ok || lastFloor.FloorNum != timelineRow.FloorNum || lastFloor.StageType != timelineRow.StageType {
+				floorRow := timelineRow
+				floorRow.Type = "floor"
+				floorRow.RoomID = ""
+				timeline.Events = append(timeline.Events, httpRaceTimelineEvent(floorRow, race.DatetimeStarted))
+				lastRoomFloor[timelineRow.Name] = Floor{
+					FloorNum:  timelineRow.FloorNum,
+					StageType: timelineRow.StageType,
+				}
+			}
+		}
+
+		timeline.Events = append(timeline.Events, httpRaceTimelineEvent(timelineRow, race.DatetimeStarted))
+	}
+
+	c.JSON(http.StatusOK, timeline)
+}
+
+func httpRaceTimelineEvent(timelineRow models.RaceTimelineRow, datetimeStarted int64) RaceTimelineEvent {
+	event := RaceTimelineEvent{
+		Name:      timelineRow.Name,
+		Type:      timelineRow.Type,
+		Time:      timelineRow.Datetime - datetimeStarted,
+		Datetime:  timelineRow.Datetime,
+		FloorNum:  timelineRow.FloorNum,
+		StageType: timelineRow.StageType,
+		AdjustedFloorNum: getAdjustedFloorNum(&Racer{
+			FloorNum:  timelineRow.FloorNum,
+			StageType: timelineRow.StageType,
+		}),
+	}
+
+	if timelineRow.Type == "item" {
+		event.ItemID = timelineRow.ItemID
+		event.ItemName = allItemNames[timelineRow.ItemID]
+	} else if timelineRow.Type == "room" {
+		event.RoomID = timelineRow.RoomID
+	}
+
+	return event
+}
@@ -90,10 +90,40 @@ func httpRace(c *gin.Context) {
 		}
 	}
 
+	// Hide the seed if the race was for a challenge that other people can still play
+	if ongoing, err := db.Challenges.RaceIsOngoing(int(raceID)); err != nil {
+		logger.Error("Failed to check the challenge for the race: ", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	} else if ongoing {
+		for p := range raceData.RaceParticipants {
+			raceData.RaceParticipants[p].RacerSeed.String = "(hidden until the challenge ends)"
+		}
+	}
+
+	// Get the best-of-N series that this race was a part of, if any
+	seriesData, inSeries, err := db.Series.GetSeriesHistoryForRace(int(raceID))
+	if err != nil {
+		logger.Error("Failed to get the series data: ", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	// Get the floor splits for every racer
+	splitsData, err := db.Races.GetRaceSplitsHistory(int(raceID))
+	if err != nil {
+		logger.Error("Failed to get the splits data: ", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
 	data := TemplateData{
 		Title:             "Race",
 		SingleRaceFormat:  raceFormat,
 		SingleRaceResults: raceData,
+		SingleRaceSeries:  seriesData,
+		InSeries:          inSeries,
+		SingleRaceSplits:  splitsData,
 	}
 
 	httpServeTemplate(w, "race", data)
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Zamiell/isaac-racing-server/models"
+	"github.com/gin-gonic/gin"
+)
+
+/*
+	Every rating change for a player, grouped by format
+	(this is used for the graphs on the profile page)
+*/
+
+const (
+	// The same format that is used for the season constants in the models package
+	ratingSeasonDatetimeLayout = "2006-01-02 15:04:05"
+)
+
+var (
+	// "ranked_solo" refers to the prefix on the "users" table name in the database
+	ratingHistoryFormats = []string{
+		string(RaceFormatUnseeded),
+		string(RaceFormatSeeded),
+		string(RaceFormatDiversity),
+		"ranked_solo",
+	}
+)
+
+type RatingHistoryResponse struct {
+	Username string                         `json:"username"`
+	Formats  map[string]RatingHistoryFormat `json:"formats"`
+}
+
+type RatingHistoryFormat struct {
+	Points  []models.RatingHistoryPoint `json:"points"`
+	Seasons []RatingSeason              `json:"seasons"`
+}
+
+type RatingSeason struct {
+	Name          string `json:"name"`
+	DatetimeStart int64  `json:"datetimeStart"` // Epoch timestamp in milliseconds
+	DatetimeEnd   int64  `json:"datetimeEnd"`   // 0 if the season is still going
+}
+
+func httpRatingHistory(c *gin.Context) {
+	// Parse the player name from the URL
+	player := c.Params.ByName("player")
+
+	var playerID int
+	if exists, v, err := db.Users.Exists(player); err != nil {
+		logger.Error("Failed to check if player \""+player+"\" exists:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	} else if !exists {
+		c.JSON(http.StatusNotFound, gin.H{"error": "The player \"" + player + "\" does not exist."})
+		return
+	} else {
+		playerID = v
+	}
+
+	// The format is optional
+	format := c.Query("format")
+	if format != "" && !ratingHistoryFormatIsValid(format) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "That is not a valid format."})
+		return
+	}
+
+	var points []models.RatingHistoryPoint
+	if v, err := db.RatingHistory.GetUser(playerID, format); err != nil {
+		logger.Error("Failed to get the rating history for player \""+player+"\":", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	} else {
+		points = v
+	}
+
+	response := RatingHistoryResponse{
+		Username: player,
+		Formats:  make(map[string]RatingHistoryFormat),
+	}
+	for _, historyFormat := range ratingHistoryFormats {
+		if format != "" && historyFormat != format {
+			continue
+		}
+		response.Formats[historyFormat] = RatingHistoryFormat{
+			Points:  make([]models.RatingHistoryPoint, 0),
+			Seasons: ratingHistoryGetSeasons(historyFormat),
+		}
+	}
+	for _, point := range points {
+		historyFormat, ok := response.Formats[point.Format]
+		if !ok {
+			continue
+		}
+		historyFormat.Points = append(historyFormat.Points, point)
+		response.Formats[point.Format] = historyFormat
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func ratingHistoryFormatIsValid(format string) bool {
+	for _, validFormat := range ratingHistoryFormats {
+		if format == validFormat {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Get the periods of time that each leaderboard covered
+func ratingHistoryGetSeasons(format string) []RatingSeason {
+	ratingSeasons := make([]RatingSeason, 0)
+
+	if format == "ranked_solo" {
+		for _, season := range seasonsGetAll() {
+			if season.Category == string(SeasonCategoryRankedSolo) && season.DatetimeStart != 0 {
+				ratingSeasons = append(ratingSeasons, RatingSeason{
+					Name:          season.Name,
+					DatetimeStart: season.DatetimeStart,
+					DatetimeEnd:   season.DatetimeEnd,
+				})
+			}
+		}
+		return ratingSeasons
+	}
+
+	// The multiplayer leaderboards were reset when the Repentance version of Racing+ came out
+	repentance := RatingSeason{
+		Name: "Repentance",
+	}
+	if t, err := time.Parse(ratingSeasonDatetimeLayout, models.RepentanceReleasedDatetime); err == nil {
+		repentance.DatetimeStart = t.UnixNano() / int64(time.Millisecond)
+	}
+	ratingSeasons = append(ratingSeasons, repentance)
+
+	return ratingSeasons
+}
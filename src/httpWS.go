@@ -37,6 +37,7 @@ func httpWS(c *gin.Context) {
 	keys["twitchBotDelay"] = sessionValues.TwitchBotDelay
 	keys["rateLimitAllowance"] = RateLimitRate
 	keys["rateLimitLastCheck"] = time.Now()
+	keys["disconnected"] = make(chan struct{}) // Closed in the "websocketHandleDisconnect()" function
 
 	// Validation succeeded, so establish the WebSocket connection
 	if err := m.HandleRequestWithKeys(w, r, keys); err != nil {
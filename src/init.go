@@ -1,11 +1,13 @@
 package server
 
 import (
+	"math/rand"
 	"os"
 	"os/exec"
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/Zamiell/isaac-racing-server/models"
 	"github.com/joho/godotenv"
@@ -83,6 +85,9 @@ func Init() {
 		isDev = true
 	}
 
+	// Seed the random number generator once for everything that picks random characters and builds
+	rand.Seed(time.Now().UnixNano())
+
 	// Initialize Sentry (in "sentry.go")
 	usingSentry = sentryInit()
 
@@ -103,6 +108,30 @@ func Init() {
 		}
 	}
 
+	// Read the disconnect grace period for races in progress (in raceDisconnect.go)
+	raceDisconnectInit()
+
+	// Read which race flags keep races out of the leaderboards (in raceFlags.go)
+	raceFlagsInit()
+
+	// Read which rating engine each format uses (in ratingEngine.go)
+	ratingEngineInit()
+
+	// Load the seasons and start opening and closing them as needed (in seasons.go)
+	seasonsInit()
+
+	// Fail any leaderboard jobs that were interrupted when the server went down (in leaderboardJobs.go)
+	leaderboardJobsInit()
+
+	// Restore any races that were in progress when the server went down (in raceCheckpoint.go)
+	raceRestoreAll()
+
+	// Restore any series that were being played when the server went down (in series.go)
+	seriesRestoreAll()
+
+	// Start matching the players who are waiting in the queues (in matchmaking.go)
+	matchmakingInit()
+
 	// Populate the achievements map (in achievements.go)
 	achievementsInit()
 
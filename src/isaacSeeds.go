@@ -2,6 +2,7 @@ package server
 
 import (
 	"math/rand"
+	"strings"
 	"time"
 )
 
@@ -49,3 +50,23 @@ func isaacSeedToString(num uint32) string {
 
 	return s
 }
+
+// The mod might not report the seed in exactly the same format that we generate it in
+// (e.g. "ABCD EFGH" versus "abcdefgh")
+func isaacSeedsAreEqual(seed1 string, seed2 string) bool {
+	normalize := func(seed string) string {
+		return strings.ToUpper(strings.ReplaceAll(seed, " ", ""))
+	}
+
+	return normalize(seed1) == normalize(seed2)
+}
+
+// Racers in seeded races have to be on the seed of the race
+// (later characters in multi-character races are on different seeds)
+func RaceSeedIsValid(race *Race, racer *Racer, seed string) bool {
+	if race.Ruleset.Format != RaceFormatSeeded || racer.CharacterNum != 1 {
+		return true
+	}
+
+	return isaacSeedsAreEqual(seed, race.Ruleset.Seed)
+}
@@ -0,0 +1,315 @@
+package server
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+
+	"github.com/Zamiell/isaac-racing-server/models"
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	Recalculating a leaderboard goes through every race of its format, so it is done as a background
+	job that writes the results to the staging tables instead of the "users" table
+	Once a job is ready, an administrator can look at how everyone's rating would change and then
+	apply it (which swaps in the new leaderboard in one transaction) or discard it
+	Jobs that the server starts by itself (e.g. after a race flag is resolved) are applied as soon
+	as they are ready
+*/
+
+const (
+	// "ranked_solo" is not a real race format, but it is the prefix of the ranked solo columns in
+	// the "users" table
+	LeaderboardJobFormatRankedSolo = "ranked_solo"
+
+	// The number of times that a job started by the server is retried if races finish while it is
+	// running
+	leaderboardJobMaxAttempts = 5
+
+	// The number of jobs that are shown in the "adminLeaderboardJobs" command
+	leaderboardJobsNumRecent = 20
+)
+
+// Called with the number of races that have been replayed so far
+type leaderboardProgressFunc func(numDone int, numTotal int)
+
+type leaderboardJob struct {
+	ID        int
+	Format    string
+	Engine    RatingEngine    // nil for ranked solo
+	Session   *melody.Session // The administrator who started the job; nil if the server started it
+	AutoApply bool
+	Attempt   int
+	Progress  int // From 0 to 100
+
+	// Set when the races of the format change while the job is running
+	Stale bool
+	Rerun bool // Start a new job that is applied automatically once this one finishes
+}
+
+// Sent to the administrator who started a job whenever its progress or status changes
+type LeaderboardJobMessage struct {
+	ID       int                  `json:"id"`
+	Format   string               `json:"format"`
+	Engine   string               `json:"engine"`
+	Status   LeaderboardJobStatus `json:"status"`
+	Progress int                  `json:"progress"`
+}
+
+var (
+	// Indexed by format; there can only be one running job per format
+	leaderboardJobsRunning = make(map[string]*leaderboardJob)
+	leaderboardJobsMutex   = sync.Mutex{}
+
+	// Held while writing the leaderboard stats or the rating history to the database, so that a
+	// race finishing, a recalculation, and a leaderboard job being applied cannot overwrite each
+	// other's changes
+	leaderboardWriteMutex = sync.Mutex{}
+)
+
+func leaderboardJobsInit() {
+	// Jobs that were running when the server was stopped will never finish
+	if err := db.LeaderboardJobs.SetAllStatus(
+		string(LeaderboardJobStatusRunning),
+		string(LeaderboardJobStatusFailed),
+	); err != nil {
+		logger.Fatal("Database error while failing the interrupted leaderboard jobs:", err)
+	}
+}
+
+// Start recalculating the leaderboard for a format in a new goroutine
+// The engine is nil for ranked solo
+// The second return value is false if there is already a job running for this format
+func leaderboardJobStart(
+	format string,
+	engine RatingEngine,
+	userID int,
+	s *melody.Session,
+	autoApply bool,
+	attempt int,
+) (int, bool, error) {
+	leaderboardJobsMutex.Lock()
+	defer leaderboardJobsMutex.Unlock()
+
+	if _, ok := leaderboardJobsRunning[format]; ok {
+		return 0, false, nil
+	}
+
+	engineName := ""
+	if engine != nil {
+		engineName = engine.Name()
+	}
+	jobID, err := db.LeaderboardJobs.Insert(format, engineName, userID)
+	if err != nil {
+		return 0, false, err
+	}
+
+	job := &leaderboardJob{
+		ID:        jobID,
+		Format:    format,
+		Engine:    engine,
+		Session:   s,
+		AutoApply: autoApply,
+		Attempt:   attempt,
+	}
+	leaderboardJobsRunning[format] = job
+	go leaderboardJobRun(job)
+
+	return jobID, true, nil
+}
+
+// The races of a format changed, so the jobs that are running or ready for it are out of date
+// If "rerun" is true, the leaderboard is recalculated and applied automatically
+func leaderboardJobsInvalidate(format string, rerun bool) {
+	// The ready jobs are marked while holding the lock so that a job that is just finishing cannot
+	// be missed
+	leaderboardJobsMutex.Lock()
+	if err := db.LeaderboardJobs.SetFormatStatus(
+		format,
+		string(LeaderboardJobStatusReady),
+		string(LeaderboardJobStatusStale),
+	); err != nil {
+		logger.Error("Database error while marking the leaderboard jobs for "+format+" as stale:", err)
+	}
+	if job, ok := leaderboardJobsRunning[format]; ok {
+		// The job will start a new one when it finishes
+		job.Stale = true
+		job.Rerun = job.Rerun || rerun
+		leaderboardJobsMutex.Unlock()
+		return
+	}
+	leaderboardJobsMutex.Unlock()
+
+	if rerun {
+		leaderboardJobRerun(format, 1)
+	}
+}
+
+func leaderboardJobRerun(format string, attempt int) {
+	var engine RatingEngine
+	if format != LeaderboardJobFormatRankedSolo {
+		engine = ratingEngineGetFormat(RaceFormat(format))
+	}
+
+	if _, started, err := leaderboardJobStart(format, engine, 0, nil, true, attempt); err != nil {
+		logger.Error("Database error while starting a leaderboard job for "+format+":", err)
+	} else if !started {
+		// Someone else started a job in the meantime
+		leaderboardJobsInvalidate(format, true)
+	}
+}
+
+func leaderboardJobRun(job *leaderboardJob) {
+	leaderboardJobEmit(job, LeaderboardJobStatusRunning)
+
+	calculateErr := leaderboardJobCalculate(job)
+	if calculateErr != nil {
+		logger.Error("Failed to calculate leaderboard job #"+strconv.Itoa(job.ID)+":", calculateErr)
+	}
+
+	// The status is written before the job is removed from the running jobs so that it cannot miss
+	// being invalidated
+	leaderboardJobsMutex.Lock()
+	status := LeaderboardJobStatusReady
+	if calculateErr != nil {
+		status = LeaderboardJobStatusFailed
+	} else if job.Stale {
+		status = LeaderboardJobStatusStale
+	}
+	if err := db.LeaderboardJobs.SetStatus(job.ID, string(status)); err != nil {
+		logger.Error("Database error while setting the status of leaderboard job #"+strconv.Itoa(job.ID)+":", err)
+		status = LeaderboardJobStatusFailed
+	}
+	delete(leaderboardJobsRunning, job.Format)
+	rerun := job.Rerun
+	leaderboardJobsMutex.Unlock()
+
+	if status == LeaderboardJobStatusReady && job.AutoApply {
+		leaderboardWriteMutex.Lock()
+		err := db.LeaderboardJobs.Apply(job.ID, job.Format)
+		leaderboardWriteMutex.Unlock()
+		if err == nil {
+			status = LeaderboardJobStatusApplied
+			logger.Info("Successfully recalculated the leaderboard for " + job.Format + " (with leaderboard job #" + strconv.Itoa(job.ID) + ").")
+		} else if errors.Is(err, models.ErrLeaderboardJobStale) {
+			status = LeaderboardJobStatusStale
+			rerun = true
+			if err := db.LeaderboardJobs.SetStatus(job.ID, string(status)); err != nil {
+				logger.Error("Database error while setting the status of leaderboard job #"+strconv.Itoa(job.ID)+":", err)
+			}
+		} else if errors.Is(err, models.ErrLeaderboardJobNotReady) {
+			// It was invalidated after it finished, which already started a new job
+			status = LeaderboardJobStatusStale
+		} else {
+			logger.Error("Database error while applying leaderboard job #"+strconv.Itoa(job.ID)+":", err)
+			status = LeaderboardJobStatusFailed
+		}
+	}
+
+	// Only the results of a job that is ready are kept
+	if status != LeaderboardJobStatusReady && status != LeaderboardJobStatusApplied {
+		if err := db.LeaderboardJobs.DeleteStaging(job.ID); err != nil {
+			logger.Error("Database error while deleting the staged results of leaderboard job #"+strconv.Itoa(job.ID)+":", err)
+		}
+	}
+
+	leaderboardJobEmit(job, status)
+
+	if rerun {
+		attempt := 1
+		if job.AutoApply {
+			attempt = job.Attempt + 1
+		}
+		if attempt > leaderboardJobMaxAttempts {
+			logger.Error("Failed to recalculate the leaderboard for " + job.Format + " after " + strconv.Itoa(leaderboardJobMaxAttempts) + " attempts.")
+			return
+		}
+		leaderboardJobRerun(job.Format, attempt)
+	}
+}
+
+// Replay every race of the format and write the results to the staging tables
+func leaderboardJobCalculate(job *leaderboardJob) error {
+	progress := func(numDone int, numTotal int) {
+		leaderboardJobSetProgress(job, numDone*100/numTotal)
+	}
+
+	if job.Engine == nil {
+		stats, history, err := leaderboardReplayRankedSolo(progress)
+		if err != nil {
+			return err
+		}
+
+		return db.LeaderboardJobs.InsertStaging(job.ID, stats, history)
+	}
+
+	allStats, _, history, err := leaderboardReplayTrueSkill(RaceFormat(job.Format), job.Engine, progress)
+	if err != nil {
+		return err
+	}
+
+	stats := make([]models.LeaderboardStagedStats, 0, len(allStats))
+	for userID, userStats := range allStats {
+		stats = append(stats, models.LeaderboardStagedStats{
+			UserID:     userID,
+			Rating:     userStats.TrueSkill,
+			Mu:         userStats.Mu,
+			Sigma:      userStats.Sigma,
+			Volatility: userStats.Volatility,
+			Change:     userStats.Change,
+			NumRaces:   userStats.NumRaces,
+		})
+	}
+
+	return db.LeaderboardJobs.InsertStaging(job.ID, stats, history)
+}
+
+// The administrator is only told about every 10 percent so that they are not flooded with messages
+func leaderboardJobSetProgress(job *leaderboardJob, progress int) {
+	leaderboardJobsMutex.Lock()
+	previousProgress := job.Progress
+	job.Progress = progress
+	leaderboardJobsMutex.Unlock()
+
+	if progress/10 > previousProgress/10 {
+		leaderboardJobEmit(job, LeaderboardJobStatusRunning)
+	}
+}
+
+// Get the progress of a job that is running
+// (the second return value is false if the job is not running)
+func leaderboardJobGetProgress(jobID int) (int, bool) {
+	leaderboardJobsMutex.Lock()
+	defer leaderboardJobsMutex.Unlock()
+
+	for _, job := range leaderboardJobsRunning {
+		if job.ID == jobID {
+			return job.Progress, true
+		}
+	}
+
+	return 0, false
+}
+
+func leaderboardJobEmit(job *leaderboardJob, status LeaderboardJobStatus) {
+	if job.Session == nil {
+		return
+	}
+
+	leaderboardJobsMutex.Lock()
+	progress := job.Progress
+	leaderboardJobsMutex.Unlock()
+
+	engineName := ""
+	if job.Engine != nil {
+		engineName = job.Engine.Name()
+	}
+	websocketEmit(job.Session, "adminLeaderboardJob", &LeaderboardJobMessage{
+		ID:       job.ID,
+		Format:   job.Format,
+		Engine:   engineName,
+		Status:   status,
+		Progress: progress,
+	})
+}
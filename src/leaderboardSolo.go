@@ -9,6 +9,9 @@ import (
 const (
 	NumRankedSoloRacesForAverage = 100
 	ThirtyMinutesInMilliseconds  = 30 * 60 * 1000
+
+	// The number of races that someone has to play to show up on the ranked solo leaderboard
+	LeaderboardRankedSoloRacesNeeded = 20
 )
 
 func leaderboardUpdateRankedSolo(race *Race) {
@@ -20,112 +23,144 @@ func leaderboardUpdateRankedSolo(race *Race) {
 
 	// Get their ranked solo results for this season
 	var raceResults []models.RaceResult
-	if v, err := db.RaceParticipants.GetNRankedSoloRaceResults(racer.ID, NumRankedSoloRacesForAverage); err != nil {
+	if v, err := db.RaceParticipants.GetNRankedSoloRaceResults(racer.ID, race.ID, NumRankedSoloRacesForAverage); err != nil {
 		logger.Error("Database error while getting the ranked solo times:", err)
 		return
 	} else {
 		raceResults = v
 	}
 
-	var numForfeits int
-	lowestTime := int64(ThirtyMinutesInMilliseconds)
-	var sumTimes int64
-	for _, raceResult := range raceResults {
-		if raceResult.Place > 0 {
-			// They finished
-			sumTimes += raceResult.RunTime
-
-			if raceResult.RunTime < lowestTime {
-				lowestTime = raceResult.RunTime
-			}
-		} else {
-			// They quit
-			numForfeits++
-		}
-	}
-
-	var averageTime float64
-	var forfeitPenalty float64
-	if len(raceResults) == numForfeits {
-		// If they forfeited every race, then we will have a divide by 0 later on,
-		// so arbitrarily set it to 30 minutes (1000 * 60 * 30)
-		averageTime = 1800000
-		forfeitPenalty = 1800000
-	} else {
-		averageTime = float64(sumTimes) / float64(len(raceResults)-numForfeits)
-		forfeitPenalty = averageTime * float64(numForfeits) / float64(len(raceResults))
-	}
+	averageTime, numForfeits, forfeitPenalty, lowestTime := leaderboardGetRankedSoloStats(raceResults)
 
 	// Update their stats in the database
 	if err := db.Users.SetStatsRankedSolo(
 		racer.ID,
-		int(averageTime),
+		averageTime,
 		numForfeits,
-		int(forfeitPenalty),
+		forfeitPenalty,
 		lowestTime,
 		race.Ruleset.StartingBuild,
 	); err != nil {
 		logger.Error("Database error while setting the ranked solo stats for \""+racer.Name+"\":", err)
 		return
 	}
-}
-
-func leaderboardRecalculateRankedSoloAll() {
-	// This is equal to either the format in the database, or "ranked_solo" as an arbitrary string
-	// ("ranked_solo" is not a real format, but it lets the child function know what specified rows
-	// to query)
-	// ("ranked_solo" refers to the prefix on the "users" table name in the database)
-	format := "ranked_solo"
 
-	if err := db.Users.ResetRankedSoloAll(); err != nil {
-		logger.Error("Database error while resetting the unseeded solo stats:", err)
+	// Keep track of how their adjusted average changes over time
+	if err := db.RatingHistory.Insert("ranked_solo", models.RatingHistoryEntry{
+		UserID: racer.ID,
+		RaceID: race.ID,
+		Rating: float64(averageTime + forfeitPenalty),
+	}); err != nil {
+		logger.Error("Database error while inserting the rating history for \""+racer.Name+"\":", err)
 		return
 	}
+}
+
+// Calculate the ranked solo stats of everyone who has played this season, without touching the
+// "users" table
+// The number of races only counts the races that are on the leaderboard
+// The progress function is called after every race (if it is not nil)
+func leaderboardReplayRankedSolo(progress leaderboardProgressFunc) (
+	[]models.LeaderboardStagedStats,
+	[]models.RatingHistoryEntry,
+	error,
+) {
+	allStats := make([]models.LeaderboardStagedStats, 0)
+	history := make([]models.RatingHistoryEntry, 0)
 
 	var allRaces []models.RaceHistory
-	if v, err := db.Races.GetAllRacesForLeaderboard(format); err != nil {
-		logger.Error("Database error while getting all of the races:", err)
-		return
+	if v, err := db.Races.GetAllRacesForLeaderboard(LeaderboardJobFormatRankedSolo); err != nil {
+		return allStats, history, err
 	} else {
 		allRaces = v
 	}
 
-	// Go through every race for this particular format in the database
-	for _, modelsRace := range allRaces {
-		// Convert the "RaceHistory" struct to a "Race" struct
-		race := &Race{}
-		race.ID = int(modelsRace.RaceID.Int64)
-
-		newFormat := format
-		if format == "ranked_solo" {
-			newFormat = "unseeded"
+	// Indexed by user ID
+	statsIndexes := make(map[int]int)
+	for i, modelsRace := range allRaces {
+		if progress != nil {
+			progress(i, len(allRaces))
 		}
-		race.Ruleset.Format = RaceFormat(newFormat)
 
-		race.Racers = make(map[string]*Racer)
+		raceID := int(modelsRace.RaceID.Int64)
 		for _, modelsRacer := range modelsRace.RaceParticipants {
-			racer := &Racer{
-				ID:    int(modelsRacer.ID.Int64),
-				Name:  modelsRacer.RacerName.String,
-				Place: int(modelsRacer.RacerPlace.Int64),
+			userID := int(modelsRacer.ID.Int64)
+
+			// Get the results that they had at the time that this race finished
+			var raceResults []models.RaceResult
+			if v, err := db.RaceParticipants.GetNRankedSoloRaceResults(userID, raceID, NumRankedSoloRacesForAverage); err != nil {
+				return allStats, history, err
+			} else {
+				raceResults = v
 			}
-			race.Racers[modelsRacer.RacerName.String] = racer
+			averageTime, numForfeits, forfeitPenalty, lowestTime := leaderboardGetRankedSoloStats(raceResults)
+
+			index, ok := statsIndexes[userID]
+			if !ok {
+				index = len(allStats)
+				statsIndexes[userID] = index
+				allStats = append(allStats, models.LeaderboardStagedStats{
+					UserID: userID,
+				})
+			}
+			stats := &allStats[index]
+			stats.Rating = float64(averageTime + forfeitPenalty)
+			stats.RealAverage = averageTime
+			stats.NumForfeits = numForfeits
+			stats.ForfeitPenalty = forfeitPenalty
+			stats.LowestTime = int(lowestTime)
+			stats.NumRaces++
+
+			history = append(history, models.RatingHistoryEntry{
+				UserID: userID,
+				RaceID: raceID,
+				Rating: stats.Rating,
+			})
 		}
+	}
 
-		// Pretend like this race just finished
-		leaderboardUpdateRankedSolo(race)
+	return allStats, history, nil
+}
+
+// Get the average time, the number of forfeits, the forfeit penalty, and the lowest time from
+// someone's most recent ranked solo races (all of the times are in milliseconds)
+func leaderboardGetRankedSoloStats(raceResults []models.RaceResult) (int, int, int, int64) {
+	var numForfeits int
+	lowestTime := int64(ThirtyMinutesInMilliseconds)
+	var sumTimes int64
+	for _, raceResult := range raceResults {
+		if raceResult.Place > 0 {
+			// They finished
+			sumTimes += raceResult.RunTime
+
+			if raceResult.RunTime < lowestTime {
+				lowestTime = raceResult.RunTime
+			}
+		} else {
+			// They quit
+			numForfeits++
+		}
 	}
 
-	// Fix the "Date of Last Race" column
-	if err := db.Users.SetLastRace(format); err != nil {
-		logger.Error("Database error while setting the last race:", err)
-		return
+	var averageTime float64
+	var forfeitPenalty float64
+	if len(raceResults) == numForfeits {
+		// If they forfeited every race, then we will have a divide by 0 later on,
+		// so arbitrarily set it to 30 minutes (1000 * 60 * 30)
+		averageTime = 1800000
+		forfeitPenalty = 1800000
+	} else {
+		averageTime = float64(sumTimes) / float64(len(raceResults)-numForfeits)
+		forfeitPenalty = averageTime * float64(numForfeits) / float64(len(raceResults))
 	}
 
-	logger.Info("Successfully reset the leaderboard for " + format + ".")
+	return int(averageTime), numForfeits, int(forfeitPenalty), lowestTime
 }
 
 func leaderboardRecalculateRankedSoloSpecificUser(userID int) {
+	leaderboardWriteMutex.Lock()
+	defer leaderboardWriteMutex.Unlock()
+
 	// This is equal to either the format in the database, or "ranked_solo" as an arbitrary string
 	// ("ranked_solo" is not a real format, but it lets the child function know what specified rows
 	// to query)
@@ -137,6 +172,12 @@ func leaderboardRecalculateRankedSoloSpecificUser(userID int) {
 		return
 	}
 
+	// The history will be written again as we go through every race
+	if err := db.RatingHistory.DeleteUserFormat(userID, format); err != nil {
+		logger.Error("Database error while deleting the ranked solo rating history for user "+strconv.Itoa(userID)+":", err)
+		return
+	}
+
 	var allRaces []models.RaceHistory
 	if v, err := db.Races.GetRankedSoloRacesForUser(format, userID); err != nil {
 		logger.Error("Database error while getting the ranked solo races for user "+strconv.Itoa(userID)+":", err)
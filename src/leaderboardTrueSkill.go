@@ -1,191 +1,309 @@
 package server
 
 import (
+	"database/sql"
+	"math"
 	"sort"
 	"strconv"
 
 	"github.com/Zamiell/isaac-racing-server/models"
-	trueskill "github.com/mafredri/go-trueskill"
 )
 
+const (
+	// Quits and disqualifications are treated as ties at the bottom of the race
+	trueSkillPlaceQuit         = 999
+	trueSkillPlaceDisqualified = 1000
+
+	// The number of races that someone has to play to show up on each TrueSkill leaderboard
+	LeaderboardSeededRacesNeeded    = 5
+	LeaderboardUnseededRacesNeeded  = 5
+	LeaderboardDiversityRacesNeeded = 10
+)
+
+// Used to show what would happen to someone's rating if the leaderboard was recalculated
+// (the ranks are 0 if the person does not have a rating on that leaderboard)
+type TrueSkillComparison struct {
+	UserID       int     `json:"userID"` // nolint:tagliatelle
+	Username     string  `json:"username"`
+	OldTrueSkill float64 `json:"oldTrueSkill"`
+	NewTrueSkill float64 `json:"newTrueSkill"`
+	Difference   float64 `json:"difference"`
+	OldRank      int     `json:"oldRank"`
+	NewRank      int     `json:"newRank"`
+	NumRaces     int     `json:"numRaces"`
+}
+
 func leaderboardUpdateTrueSkill(race *Race) {
-	// The racer map is not in order, so first make a sorted list of usernames
-	racerNames := make([]string, 0)
-	for username := range race.Racers {
-		racerNames = append(racerNames, username)
-	}
-	sort.Strings(racerNames)
+	engine := ratingEngineGetFormat(race.Ruleset.Format)
 
 	// Get the stats for every person in the race
-	var statsSlice []*models.StatsTrueSkill
-	for _, racerName := range racerNames {
-		racer := race.Racers[racerName]
-		if v, err := db.Users.GetTrueSkill(racer.ID, string(race.Ruleset.Format)); err != nil {
+	stats := make(map[string]*models.StatsTrueSkill)
+	for racerName, racer := range race.Racers {
+		if v, err := ratingEngineGetStats(engine, racer.ID, race.Ruleset.Format); err != nil {
 			logger.Error("Database error while getting the TrueSkill stats for \""+racer.Name+"\":", err)
 			return
 		} else {
-			// Increment the number of races
-			v.NumRaces++
-
-			statsSlice = append(statsSlice, &v)
+			stats[racerName] = &v
 		}
 	}
 
-	// Do a 1v1 TrueSkill calculation for everyone in the race
-	for i, racer1 := range racerNames {
-		p1stats := statsSlice[i]
-		p1Place := race.Racers[racer1].Place
-		if p1Place == -1 {
-			// Change forfeits to place 999 to simplify the below calculation
-			p1Place = 999
-		} else if p1Place == -2 {
-			// Change disqualifications to place 1000 to simplify the below calculation
-			p1Place = 1000
+	previousRatings := leaderboardGetTrueSkillRatings(engine, stats)
+	leaderboardAdjustTrueSkillRace(engine, race, stats)
+
+	// Write the values back to the database
+	for racerName, racer := range race.Racers {
+		if err := db.Users.SetTrueSkill(racer.ID, *stats[racerName], string(race.Ruleset.Format)); err != nil {
+			logger.Error("Database error while setting the TrueSkill stats for user "+strconv.Itoa(racer.ID)+":", err)
+			continue
 		}
 
-		for j, racer2 := range racerNames {
-			// Skip this player if they are facing themself or we have already done the matchup
-			if j <= i {
-				continue
-			}
+		// Nobody's rating changes in a race with only one person
+		if len(race.Racers) < 2 {
+			continue
+		}
+		entry := leaderboardGetTrueSkillHistoryEntry(racer.ID, race.ID, previousRatings[racerName], stats[racerName])
+		if err := db.RatingHistory.Insert(string(race.Ruleset.Format), entry); err != nil {
+			logger.Error("Database error while inserting the rating history for user "+strconv.Itoa(racer.ID)+":", err)
+		}
+	}
+}
 
-			p2stats := statsSlice[j]
-			p2Place := race.Racers[racer2].Place
-			if p2Place == -1 {
-				// Change forfeits to place 999 to simplify the below calculation
-				p2Place = 999
-			} else if p2Place == -2 {
-				// Change disqualifications to place 1000 to simplify the below calculation
-				p2Place = 1000
-			}
+// Get how much everyone's rating would move if the leaderboard was recalculated from scratch with
+// the given rating engine, without changing anything
+// If the engine is the one that the format already uses, the biggest changes are first
+// Otherwise, the ratings are not comparable, so the result is the shadow leaderboard in rank order
+func leaderboardCompareTrueSkill(format RaceFormat, engine RatingEngine) ([]TrueSkillComparison, error) {
+	comparisons := make([]TrueSkillComparison, 0)
 
-			var p1Mu, p1Sigma, p2Mu, p2Sigma float64
-			if p1Place < p2Place {
-				// Player 1 wins
-				p1Mu, p1Sigma, p2Mu, p2Sigma = leaderboardAdjustTrueSkill(
-					p1stats.Mu,
-					p1stats.Sigma,
-					p2stats.Mu,
-					p2stats.Sigma,
-					false,
-				)
-				//logger.Info("Race #" + strconv.Itoa(race.ID) + ": player \"" + racer1 + "\" (place " + strconv.Itoa(race.Racers[racer1].Place) + ") WINS OVER player \"" + racer2 + "\" (place " + strconv.Itoa(race.Racers[racer2].Place) + ")")
-			} else if p1Place > p2Place {
-				// Player 2 wins
-				p2Mu, p2Sigma, p1Mu, p1Sigma = leaderboardAdjustTrueSkill(
-					p2stats.Mu,
-					p2stats.Sigma,
-					p1stats.Mu,
-					p1stats.Sigma,
-					false,
-				)
-				//logger.Info("Race #" + strconv.Itoa(race.ID) + ": player \"" + racer1 + "\" (place " + strconv.Itoa(race.Racers[racer1].Place) + ") LOSES TO player \"" + racer2 + "\" (place " + strconv.Itoa(race.Racers[racer2].Place) + ")")
-			} else {
-				// Player 1 and 2 tied; this can only happen if both players quit
-				// (or they were both disqualified)
-				p1Mu, p1Sigma, p2Mu, p2Sigma = leaderboardAdjustTrueSkill(
-					p1stats.Mu,
-					p1stats.Sigma,
-					p2stats.Mu,
-					p2stats.Sigma,
-					true,
-				)
-				//logger.Info("Race #" + strconv.Itoa(race.ID) + ": player \"" + racer1 + "\" (place " + strconv.Itoa(race.Racers[racer1].Place) + ") TIES player \"" + racer2 + "\" (place " + strconv.Itoa(race.Racers[racer2].Place) + ")")
-			}
+	allStats, usernames, _, err := leaderboardReplayTrueSkill(format, engine, nil)
+	if err != nil {
+		return comparisons, err
+	}
 
-			p1stats.Mu = p1Mu
-			p1stats.Sigma = p1Sigma
-			p2stats.Mu = p2Mu
-			p2stats.Sigma = p2Sigma
+	currentStats, currentUsernames, err := db.Users.GetAllTrueSkill(string(format))
+	if err != nil {
+		return comparisons, err
+	}
+
+	for userID, stats := range allStats {
+		comparison := TrueSkillComparison{
+			UserID:       userID,
+			Username:     usernames[userID],
+			NewTrueSkill: stats.TrueSkill,
+			NumRaces:     stats.NumRaces,
 		}
+		if current, ok := currentStats[userID]; ok {
+			comparison.OldTrueSkill = current.TrueSkill
+		}
+		comparison.Difference = comparison.NewTrueSkill - comparison.OldTrueSkill
+		comparisons = append(comparisons, comparison)
 	}
 
-	for i, racerName := range racerNames {
-		// Get the player's new "TrueSkill" and the change
-		stats := statsSlice[i]
-		trueSkill := leaderboardGetTrueSkill(stats.Mu, stats.Sigma)
-		stats.Change = trueSkill - stats.TrueSkill
-		stats.TrueSkill = trueSkill
-
-		// Write the values back to the database
-		racer := race.Racers[racerName]
-		if err := db.Users.SetTrueSkill(racer.ID, *stats, string(race.Ruleset.Format)); err != nil {
-			logger.Error("Database error while setting the TrueSkill stats for user "+strconv.Itoa(racer.ID)+":", err)
+	// Someone could also have a rating from races that no longer count
+	for userID, current := range currentStats {
+		if _, ok := allStats[userID]; !ok {
+			comparisons = append(comparisons, TrueSkillComparison{
+				UserID:       userID,
+				Username:     currentUsernames[userID],
+				OldTrueSkill: current.TrueSkill,
+				NewTrueSkill: 0,
+				Difference:   -current.TrueSkill,
+			})
 		}
 	}
-}
 
-func leaderboardRecalculateTrueSkill(format RaceFormat) {
-	if err := db.Users.ResetTrueSkill(string(format)); err != nil {
-		logger.Error("Database error while resetting the TrueSkill stats:", err)
-		return
+	// Rank everyone on both leaderboards
+	// (people who are not on a leaderboard are sorted to the bottom and do not get a rank)
+	sort.Slice(comparisons, func(i, j int) bool {
+		_, iRanked := currentStats[comparisons[i].UserID]
+		_, jRanked := currentStats[comparisons[j].UserID]
+		if iRanked != jRanked {
+			return iRanked
+		}
+		return comparisons[i].OldTrueSkill > comparisons[j].OldTrueSkill
+	})
+	for i := range comparisons {
+		if _, ok := currentStats[comparisons[i].UserID]; ok {
+			comparisons[i].OldRank = i + 1
+		}
+	}
+	sort.Slice(comparisons, func(i, j int) bool {
+		_, iRanked := allStats[comparisons[i].UserID]
+		_, jRanked := allStats[comparisons[j].UserID]
+		if iRanked != jRanked {
+			return iRanked
+		}
+		return comparisons[i].NewTrueSkill > comparisons[j].NewTrueSkill
+	})
+	for i := range comparisons {
+		if _, ok := allStats[comparisons[i].UserID]; ok {
+			comparisons[i].NewRank = i + 1
+		}
 	}
 
+	if engine.Name() == ratingEngineGetFormat(format).Name() {
+		sort.SliceStable(comparisons, func(i, j int) bool {
+			return math.Abs(comparisons[i].Difference) > math.Abs(comparisons[j].Difference)
+		})
+	}
+
+	return comparisons, nil
+}
+
+// Calculate the stats of everyone who has played this format with the given rating engine,
+// starting from the default values (without touching the database)
+// The stats and the usernames are both indexed by user ID
+// The history has an entry for every racer in every race, in the order that the races finished
+// The progress function is called after every race (if it is not nil)
+func leaderboardReplayTrueSkill(format RaceFormat, engine RatingEngine, progress leaderboardProgressFunc) (
+	map[int]*models.StatsTrueSkill,
+	map[int]string,
+	[]models.RatingHistoryEntry,
+	error,
+) {
+	allStats := make(map[int]*models.StatsTrueSkill)
+	usernames := make(map[int]string)
+	history := make([]models.RatingHistoryEntry, 0)
+
 	var allRaces []models.RaceHistory
 	if v, err := db.Races.GetAllRacesForLeaderboard(string(format)); err != nil {
-		logger.Error("Database error while getting all of the races:", err)
-		return
+		return allStats, usernames, history, err
 	} else {
 		allRaces = v
 	}
 
-	// Go through every race for this particular format in the database
-	for _, modelsRace := range allRaces {
+	for i, modelsRace := range allRaces {
+		if progress != nil {
+			progress(i, len(allRaces))
+		}
+
 		// Convert the "RaceHistory" struct to a "Race" struct
 		race := &Race{}
 		race.ID = int(modelsRace.RaceID.Int64)
 		race.Ruleset.Format = format
+		race.Ruleset.TeamSize = int(modelsRace.RaceTeamSize.Int64)
 		race.Racers = make(map[string]*Racer)
+		stats := make(map[string]*models.StatsTrueSkill)
 		for _, modelsRacer := range modelsRace.RaceParticipants {
 			racer := &Racer{
 				ID:    int(modelsRacer.ID.Int64),
 				Name:  modelsRacer.RacerName.String,
 				Place: int(modelsRacer.RacerPlace.Int64),
+				Team:  int(modelsRacer.RacerTeam.Int64),
 			}
-			race.Racers[modelsRacer.RacerName.String] = racer
+			race.Racers[racer.Name] = racer
+			usernames[racer.ID] = racer.Name
+
+			if _, ok := allStats[racer.ID]; !ok {
+				defaultStats := engine.DefaultStats()
+				allStats[racer.ID] = &defaultStats
+			}
+			stats[racer.Name] = allStats[racer.ID]
 		}
 
 		// Pretend like this race just finished
-		leaderboardUpdateTrueSkill(race)
-	}
+		previousRatings := leaderboardGetTrueSkillRatings(engine, stats)
+		leaderboardAdjustTrueSkillRace(engine, race, stats)
 
-	// Fix the "Date of Last Race" column
-	if err := db.Users.SetLastRace(string(format)); err != nil {
-		logger.Error("Failed to set set the last race:", err)
-		return
+		// Nobody's rating changes in a race with only one person
+		if len(race.Racers) < 2 {
+			continue
+		}
+		for _, racer := range race.Racers {
+			history = append(history, leaderboardGetTrueSkillHistoryEntry(racer.ID, race.ID, previousRatings[racer.Name], stats[racer.Name]))
+		}
 	}
 
-	logger.Info("Successfully reset the TrueSkill leaderboard for " + format + ".")
+	return allStats, usernames, history, nil
 }
 
 /*
 	Subroutines
 */
 
-func leaderboardAdjustTrueSkill(
-	p1Mu float64,
-	p1Sigma float64,
-	p2Mu float64,
-	p2Sigma float64,
-	draw bool,
-) (float64, float64, float64, float64) {
-	// Based on code from:
-	// https://godoc.org/github.com/mafredri/go-trueskill
-	ts := trueskill.New()
-	p1 := trueskill.NewPlayer(p1Mu, p1Sigma)
-	p2 := trueskill.NewPlayer(p2Mu, p2Sigma)
-	// The first player that is put into the "tsPlayers" slice is the one who wins
-	tsPlayers := []trueskill.Player{p1, p2}
-	newTSPlayers, _ := ts.AdjustSkills(tsPlayers, draw)
-
-	return newTSPlayers[0].Mu(), newTSPlayers[0].Sigma(), newTSPlayers[1].Mu(), newTSPlayers[1].Sigma()
+// Update the stats of everyone in a race based on how they placed
+// (the stats are indexed by racer name and are changed in place)
+func leaderboardAdjustTrueSkillRace(engine RatingEngine, race *Race, stats map[string]*models.StatsTrueSkill) {
+	if len(race.Racers) < 2 {
+		return
+	}
+
+	// Team races are rated as one team against the other
+	if race.IsTeamRace() {
+		leaderboardAdjustTrueSkillRaceTeams(engine, race, stats)
+	} else {
+		leaderboardAdjustTrueSkillRaceFreeForAll(engine, race, stats)
+	}
+
+	for _, racerStats := range stats {
+		// Get the player's new "TrueSkill" and the change
+		trueSkill := engine.GetRating(racerStats)
+		racerStats.Change = trueSkill - racerStats.TrueSkill
+		racerStats.TrueSkill = trueSkill
+		racerStats.NumRaces++
+	}
+}
+
+// Everyone in the race is rated at the same time
+// (the racers are sorted by place and then by name so that recalculations are deterministic)
+func leaderboardAdjustTrueSkillRaceFreeForAll(engine RatingEngine, race *Race, stats map[string]*models.StatsTrueSkill) {
+	racers := make([]*Racer, 0, len(race.Racers))
+	for _, racer := range race.Racers {
+		racers = append(racers, racer)
+	}
+	sort.Slice(racers, func(i, j int) bool {
+		iPlace := leaderboardGetTrueSkillPlace(racers[i].Place)
+		jPlace := leaderboardGetTrueSkillPlace(racers[j].Place)
+		if iPlace != jPlace {
+			return iPlace < jPlace
+		}
+		return racers[i].Name < racers[j].Name
+	})
+
+	sortedStats := make([]*models.StatsTrueSkill, 0, len(racers))
+	places := make([]int, 0, len(racers))
+	for _, racer := range racers {
+		sortedStats = append(sortedStats, stats[racer.Name])
+		places = append(places, leaderboardGetTrueSkillPlace(racer.Place))
+	}
+
+	engine.AdjustFreeForAll(sortedStats, places)
 }
 
-func leaderboardGetTrueSkill(mu float64, sigma float64) float64 {
-	// Based on code from:
-	// https://godoc.org/github.com/mafredri/go-trueskill
+// Change forfeits and disqualifications to a place that is behind everyone who finished
+func leaderboardGetTrueSkillPlace(place int) int {
+	if place == -1 {
+		return trueSkillPlaceQuit
+	} else if place == -2 {
+		return trueSkillPlaceDisqualified
+	}
+
+	return place
+}
+
+// Get everyone's rating before a race is applied, so that the history can record how much it moved
+// (indexed by racer name)
+func leaderboardGetTrueSkillRatings(engine RatingEngine, stats map[string]*models.StatsTrueSkill) map[string]float64 {
+	ratings := make(map[string]float64)
+	for racerName, racerStats := range stats {
+		ratings[racerName] = engine.GetRating(racerStats)
+	}
+
+	return ratings
+}
 
-	ts := trueskill.New()
-	player := trueskill.NewPlayer(mu, sigma)
-	return ts.TrueSkill(player)
+func leaderboardGetTrueSkillHistoryEntry(
+	userID int,
+	raceID int,
+	previousRating float64,
+	stats *models.StatsTrueSkill,
+) models.RatingHistoryEntry {
+	return models.RatingHistoryEntry{
+		UserID:       userID,
+		RaceID:       raceID,
+		Rating:       stats.TrueSkill,
+		Mu:           sql.NullFloat64{Float64: stats.Mu, Valid: true},
+		Sigma:        sql.NullFloat64{Float64: stats.Sigma, Valid: true},
+		RatingChange: sql.NullFloat64{Float64: stats.TrueSkill - previousRating, Valid: true},
+	}
 }
@@ -0,0 +1,159 @@
+package server
+
+import (
+	"math"
+	"sort"
+
+	"github.com/Zamiell/isaac-racing-server/models"
+	trueskill "github.com/mafredri/go-trueskill"
+	"github.com/mafredri/go-trueskill/gaussian"
+)
+
+/*
+	The "go-trueskill" library only supports free-for-all matches, so team races
+	use the two-team update from "Computing Your Skill" by Jeff Moser:
+	http://www.moserware.com/assets/computing-your-skill/The%20Math%20Behind%20TrueSkill.pdf
+	(the constants are the same as the library defaults)
+*/
+
+const (
+	trueSkillBeta            = trueskill.DefaultBeta
+	trueSkillTau             = trueskill.DefaultTau
+	trueSkillDrawProbability = trueskill.DefaultDrawProbability / 100
+
+	// Below this, the normal distribution functions lose all precision
+	trueSkillDenominatorMin = 2.222758749e-162
+)
+
+// The stats are indexed by racer name and are changed in place
+func leaderboardAdjustTrueSkillRaceTeams(engine RatingEngine, race *Race, stats map[string]*models.StatsTrueSkill) {
+	// Group the stats of every person in the race by team
+	// (the racers are sorted by name so that recalculations are deterministic)
+	teamStats := make([][]*models.StatsTrueSkill, RaceNumTeams)
+	for i := range teamStats {
+		racers := race.GetTeamRacers(i + 1)
+		sort.Slice(racers, func(a, b int) bool {
+			return racers[a].Name < racers[b].Name
+		})
+
+		for _, racer := range racers {
+			teamStats[i] = append(teamStats[i], stats[racer.Name])
+		}
+	}
+
+	// Do a 2-team calculation
+	team1Place := race.GetTeamPlace(1)
+	team2Place := race.GetTeamPlace(2)
+	if team1Place < team2Place {
+		engine.AdjustTeams(teamStats[0], teamStats[1], false)
+	} else if team1Place > team2Place {
+		engine.AdjustTeams(teamStats[1], teamStats[0], false)
+	} else {
+		// The teams tied; this can only happen if both teams quit
+		// (or they were both disqualified)
+		engine.AdjustTeams(teamStats[0], teamStats[1], true)
+	}
+}
+
+/*
+	Subroutines
+*/
+
+// The mu and sigma of every player in both teams are updated in place
+func leaderboardAdjustTrueSkillTeams(
+	winners []*models.StatsTrueSkill,
+	losers []*models.StatsTrueSkill,
+	draw bool,
+) {
+	numPlayers := float64(len(winners) + len(losers))
+
+	winnersMu := 0.0
+	variance := numPlayers * trueSkillBeta * trueSkillBeta
+	for _, stats := range winners {
+		winnersMu += stats.Mu
+		variance += stats.Sigma * stats.Sigma
+	}
+	losersMu := 0.0
+	for _, stats := range losers {
+		losersMu += stats.Mu
+		variance += stats.Sigma * stats.Sigma
+	}
+	c := math.Sqrt(variance)
+
+	drawMargin := gaussian.NormPpf((trueSkillDrawProbability+1)/2) * math.Sqrt(numPlayers) * trueSkillBeta
+	t := (winnersMu - losersMu) / c
+	epsilon := drawMargin / c
+
+	var v, w float64
+	if draw {
+		v = trueSkillVWithinMargin(t, epsilon)
+		w = trueSkillWWithinMargin(t, epsilon)
+	} else {
+		v = trueSkillVExceedsMargin(t, epsilon)
+		w = trueSkillWExceedsMargin(t, epsilon)
+	}
+
+	adjust := func(stats *models.StatsTrueSkill, rankMultiplier float64) {
+		sigmaSquared := stats.Sigma*stats.Sigma + trueSkillTau*trueSkillTau
+		stats.Mu += rankMultiplier * (sigmaSquared / c) * v
+		stats.Sigma = math.Sqrt(sigmaSquared * (1 - w*sigmaSquared/variance))
+	}
+	for _, stats := range winners {
+		adjust(stats, 1)
+	}
+	for _, stats := range losers {
+		// For draws, this is equivalent to calculating "v" from the perspective of the other team,
+		// since the "within margin" function is odd in "t"
+		adjust(stats, -1)
+	}
+}
+
+func trueSkillVExceedsMargin(t float64, epsilon float64) float64 {
+	denominator := gaussian.NormCdf(t - epsilon)
+	if denominator < trueSkillDenominatorMin {
+		return -t + epsilon
+	}
+
+	return gaussian.NormPdf(t-epsilon) / denominator
+}
+
+func trueSkillWExceedsMargin(t float64, epsilon float64) float64 {
+	denominator := gaussian.NormCdf(t - epsilon)
+	if denominator < trueSkillDenominatorMin {
+		if t < 0 {
+			return 1
+		}
+		return 0
+	}
+
+	v := trueSkillVExceedsMargin(t, epsilon)
+	return v * (v + t - epsilon)
+}
+
+func trueSkillVWithinMargin(t float64, epsilon float64) float64 {
+	tAbs := math.Abs(t)
+	denominator := gaussian.NormCdf(epsilon-tAbs) - gaussian.NormCdf(-epsilon-tAbs)
+	if denominator < trueSkillDenominatorMin {
+		if t < 0 {
+			return -t - epsilon
+		}
+		return -t + epsilon
+	}
+
+	numerator := gaussian.NormPdf(-epsilon-tAbs) - gaussian.NormPdf(epsilon-tAbs)
+	if t < 0 {
+		return -numerator / denominator
+	}
+	return numerator / denominator
+}
+
+func trueSkillWWithinMargin(t float64, epsilon float64) float64 {
+	tAbs := math.Abs(t)
+	denominator := gaussian.NormCdf(epsilon-tAbs) - gaussian.NormCdf(-epsilon-tAbs)
+	if denominator < trueSkillDenominatorMin {
+		return 1
+	}
+
+	v := trueSkillVWithinMargin(tAbs, epsilon)
+	return v*v + ((epsilon-tAbs)*gaussian.NormPdf(epsilon-tAbs)-(-epsilon-tAbs)*gaussian.NormPdf(-epsilon-tAbs))/denominator
+}
@@ -0,0 +1,353 @@
+package server
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	Players who join the queue for a format are matched against other queued players with a similar
+	TrueSkill rating, and a race is automatically created for them
+	The longer that someone waits, the wider the range of ratings that they can be matched against
+*/
+
+const (
+	MatchmakingInterval   = 5 * time.Second
+	MatchmakingMaxRacers  = 4
+	MatchmakingWindowBase = 3.0  // In TrueSkill mu points; the sigma of the player is added to this
+	MatchmakingWindowRate = 2.0  // In TrueSkill mu points per minute of waiting
+	MatchmakingWindowMax  = 25.0 // In TrueSkill mu points
+)
+
+// Used to track the players who are waiting for a match
+type QueueEntry struct {
+	UserID         int
+	Username       string
+	Mu             float64 // In TrueSkill units, no matter which rating engine the format uses
+	Sigma          float64
+	DatetimeJoined int64 // Epoch timestamp in milliseconds
+}
+
+var (
+	queues      = make(map[RaceFormat][]*QueueEntry)
+	queuesMutex = new(sync.Mutex)
+)
+
+func matchmakingInit() {
+	go func() {
+		for {
+			time.Sleep(MatchmakingInterval)
+			matchmakingTick()
+		}
+	}()
+}
+
+func queueFormatIsValid(format RaceFormat) bool {
+	return format == RaceFormatUnseeded ||
+		format == RaceFormatSeeded ||
+		format == RaceFormatDiversity
+}
+
+// Get the format of the queue that someone is in
+// (the second return value is false if they are not in a queue)
+func queueGetFormat(username string) (RaceFormat, bool) {
+	queuesMutex.Lock()
+	defer queuesMutex.Unlock()
+
+	for format, entries := range queues {
+		for _, entry := range entries {
+			if entry.Username == username {
+				return format, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func queueAdd(format RaceFormat, entry *QueueEntry) {
+	queuesMutex.Lock()
+	defer queuesMutex.Unlock()
+
+	queues[format] = append(queues[format], entry)
+}
+
+// Take someone out of whatever queue they are in
+// (the second return value is false if they were not in a queue)
+func queueRemove(username string) (RaceFormat, bool) {
+	queuesMutex.Lock()
+	defer queuesMutex.Unlock()
+
+	for format, entries := range queues {
+		for i, entry := range entries {
+			if entry.Username == username {
+				queues[format] = append(entries[:i], entries[i+1:]...)
+				return format, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func queueCount(format RaceFormat) int {
+	queuesMutex.Lock()
+	defer queuesMutex.Unlock()
+
+	return len(queues[format])
+}
+
+// Get how far away someone's rating can be from their opponents at the given time
+func (entry *QueueEntry) GetWindow(now int64) float64 {
+	minutesWaited := float64(now-entry.DatetimeJoined) / float64(time.Minute/time.Millisecond)
+	window := MatchmakingWindowBase + entry.Sigma + MatchmakingWindowRate*minutesWaited
+	return math.Min(window, MatchmakingWindowMax)
+}
+
+// Two players can only be matched if they are both inside of each other's window
+func (entry *QueueEntry) CanMatch(other *QueueEntry, now int64) bool {
+	difference := math.Abs(entry.Mu - other.Mu)
+	return difference <= entry.GetWindow(now) && difference <= other.GetWindow(now)
+}
+
+// Split the players in a queue into groups that should race each other
+// (the people who have waited the longest get matched first; everyone in a group has to be able to
+// match with everyone else in it)
+func MatchmakingFindMatches(entries []*QueueEntry, now int64) [][]*QueueEntry {
+	waiting := make([]*QueueEntry, len(entries))
+	copy(waiting, entries)
+	sort.SliceStable(waiting, func(i, j int) bool {
+		return waiting[i].DatetimeJoined < waiting[j].DatetimeJoined
+	})
+
+	matches := make([][]*QueueEntry, 0)
+	matched := make(map[*QueueEntry]bool)
+	for _, anchor := range waiting {
+		if matched[anchor] {
+			continue
+		}
+
+		// Prefer the opponents with the closest ratings
+		candidates := make([]*QueueEntry, 0)
+		for _, other := range waiting {
+			if other != anchor && !matched[other] && anchor.CanMatch(other, now) {
+				candidates = append(candidates, other)
+			}
+		}
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return math.Abs(candidates[i].Mu-anchor.Mu) < math.Abs(candidates[j].Mu-anchor.Mu)
+		})
+
+		group := []*QueueEntry{anchor}
+		for _, candidate := range candidates {
+			if len(group) >= MatchmakingMaxRacers {
+				break
+			}
+
+			fitsGroup := true
+			for _, member := range group {
+				if !member.CanMatch(candidate, now) {
+					fitsGroup = false
+					break
+				}
+			}
+			if fitsGroup {
+				group = append(group, candidate)
+			}
+		}
+
+		if len(group) < 2 {
+			continue
+		}
+		for _, member := range group {
+			matched[member] = true
+		}
+		matches = append(matches, group)
+	}
+
+	return matches
+}
+
+func matchmakingTick() {
+	now := getTimestamp()
+
+	// Take all of the matched players out of the queues first so that nobody can be matched twice
+	matchesByFormat := make(map[RaceFormat][][]*QueueEntry)
+	queuesMutex.Lock()
+	for format, entries := range queues {
+		matches := MatchmakingFindMatches(entries, now)
+		if len(matches) == 0 {
+			continue
+		}
+		matchesByFormat[format] = matches
+
+		matched := make(map[*QueueEntry]bool)
+		for _, match := range matches {
+			for _, entry := range match {
+				matched[entry] = true
+			}
+		}
+		remaining := make([]*QueueEntry, 0, len(entries))
+		for _, entry := range entries {
+			if !matched[entry] {
+				remaining = append(remaining, entry)
+			}
+		}
+		queues[format] = remaining
+	}
+	queuesMutex.Unlock()
+
+	for format, matches := range matchesByFormat {
+		for _, match := range matches {
+			matchmakingCreateRace(format, match)
+		}
+	}
+}
+
+// Create a race for a group of matched players and put all of them in it
+func matchmakingCreateRace(format RaceFormat, match []*QueueEntry) {
+	// Creating a race is a lobby command
+	lobbyMutex.Lock()
+	defer lobbyMutex.Unlock()
+
+	// Someone might have gone offline or joined a race by hand since they were matched
+	sessions := make([]*melody.Session, 0, len(match))
+	entries := make([]*QueueEntry, 0, len(match))
+	for _, entry := range match {
+		if racerIsInRace(entry.Username) {
+			continue
+		}
+		if s, ok := websocketGetSession(entry.Username); ok {
+			sessions = append(sessions, s)
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) < 2 {
+		// Put the people who are still here back in the queue
+		// (they keep their place in line, since the oldest entries are always matched first)
+		for _, entry := range entries {
+			queueAdd(format, entry)
+		}
+		return
+	}
+
+	ruleset := raceRerollRuleset(Ruleset{
+		Ranked:          true,
+		Solo:            false,
+		Format:          format,
+		CharacterRandom: true,
+		Goal:            RaceGoalDefault,
+		StartingBuild:   -1,
+		Difficulty:      "normal",
+	}, false, true)
+	name := "Ranked " + string(format) + " match"
+
+	var race *Race
+	if v, err := raceCreateSub(name, ruleset, entries[0].Username, "", nil); err != nil {
+		logger.Error("Database error while inserting the matchmaking race:", err)
+		for _, s := range sessions {
+			websocketWarning(s, "queueJoin", "Something went wrong when creating your match. Please join the queue again.")
+		}
+		return
+	} else {
+		race = v
+	}
+
+	racerNames := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		racerNames = append(racerNames, entry.Username)
+	}
+	logger.Info("Matched "+strconv.Itoa(len(entries))+" players in the "+string(format)+" queue into race "+strconv.Itoa(race.ID)+":", racerNames)
+
+	race.Send(func() {
+		race.SendCreated()
+
+		joined := make([]*melody.Session, 0, len(sessions))
+		joinedNames := make([]string, 0, len(sessions))
+		for _, s := range sessions {
+			d := &IncomingWebsocketData{
+				Command: "queueJoin",
+				ID:      race.ID,
+			}
+			if !websocketGetSessionValues(s, d) {
+				continue
+			}
+			raceJoinSub(s, d, true)
+
+			// They might have joined another race in the meantime
+			if _, ok := race.Racers[d.v.Username]; !ok {
+				continue
+			}
+			joined = append(joined, s)
+			joinedNames = append(joinedNames, d.v.Username)
+		}
+
+		if len(joined) < 2 {
+			matchmakingAbortRace(race, format, entries)
+			return
+		}
+
+		for _, s := range joined {
+			type QueueMatchedMessage struct {
+				ID     int        `json:"id"`
+				Format RaceFormat `json:"format"`
+				Racers []string   `json:"racers"`
+			}
+			websocketEmit(s, "queueMatched", &QueueMatchedMessage{
+				ID:     race.ID,
+				Format: format,
+				Racers: joinedNames,
+			})
+		}
+
+		// Nobody else should be able to join a race that was made by the matchmaker
+		race.Locked = true
+		for _, s := range websocketGetAllSessions() {
+			type RaceSetLockedMessage struct {
+				ID     int  `json:"id"`
+				Locked bool `json:"locked"`
+			}
+			websocketEmit(s, "raceSetLocked", &RaceSetLockedMessage{
+				ID:     race.ID,
+				Locked: race.Locked,
+			})
+		}
+	})
+}
+
+// Not enough of the matched players made it into the race, so get rid of it and put the players
+// who are still around back in the queue
+// (this runs on the goroutine for the race)
+func matchmakingAbortRace(race *Race, format RaceFormat, entries []*QueueEntry) {
+	logger.Info("Fewer than 2 players joined matchmaking race " + strconv.Itoa(race.ID) + ", so it is being deleted.")
+
+	// Removing the last racer deletes the race
+	if len(race.Racers) == 0 {
+		race.Delete()
+	} else {
+		for username := range race.Racers {
+			race.RemoveRacer(username)
+		}
+	}
+
+	for _, entry := range entries {
+		if racerIsInRace(entry.Username) {
+			continue
+		}
+		s, ok := websocketGetSession(entry.Username)
+		if !ok {
+			continue
+		}
+
+		queueAdd(format, entry)
+		websocketEmit(s, "queueJoined", &QueueMessage{
+			Format:    format,
+			NumQueued: queueCount(format),
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package models
+
+import (
+	"database/sql"
+)
+
+type Challenges struct{}
+
+// This mirrors the "challenges" table row
+type Challenge struct {
+	ID            int
+	Type          string
+	Character     string
+	Goal          string
+	StartingBuild int
+	Seed          string
+	DatetimeStart int64 // Epoch timestamp in milliseconds
+	DatetimeEnd   int64 // Epoch timestamp in milliseconds
+}
+
+func (*Challenges) Insert(challenge *Challenge) (int, error) {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		INSERT INTO challenges (
+			type,
+			player_type,
+			goal,
+			starting_build,
+			seed,
+			datetime_start,
+			datetime_end
+		)
+		VALUES (
+			?,
+			?,
+			?,
+			?,
+			?,
+			FROM_UNIXTIME(? / 1000),
+			FROM_UNIXTIME(? / 1000)
+		)
+	`); err != nil {
+		return 0, err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	var result sql.Result
+	if v, err := stmt.Exec(
+		challenge.Type,
+		challenge.Character,
+		challenge.Goal,
+		challenge.StartingBuild,
+		challenge.Seed,
+		challenge.DatetimeStart,
+		challenge.DatetimeEnd,
+	); err != nil {
+		return 0, err
+	} else {
+		result = v
+	}
+
+	var challengeID int
+	if challengeID64, err := result.LastInsertId(); err != nil {
+		return 0, err
+	} else {
+		challengeID = int(challengeID64)
+	}
+
+	return challengeID, nil
+}
+
+// Get the challenge of the given type that started at the given time
+// (the second return value is false if it has not been created yet)
+func (*Challenges) Get(challengeType string, datetimeStart int64) (Challenge, bool, error) {
+	challenge := Challenge{
+		Type: challengeType,
+	}
+	if err := db.QueryRow(`
+		SELECT
+			id,
+			player_type,
+			goal,
+			starting_build,
+			seed,
+			UNIX_TIMESTAMP(datetime_start) * 1000,
+			UNIX_TIMESTAMP(datetime_end) * 1000
+		FROM challenges
+		WHERE type = ? AND datetime_start = FROM_UNIXTIME(? / 1000)
+	`, challengeType, datetimeStart).Scan(
+		&challenge.ID,
+		&challenge.Character,
+		&challenge.Goal,
+		&challenge.StartingBuild,
+		&challenge.Seed,
+		&challenge.DatetimeStart,
+		&challenge.DatetimeEnd,
+	); err == sql.ErrNoRows {
+		return challenge, false, nil
+	} else if err != nil {
+		return challenge, false, err
+	}
+
+	return challenge, true, nil
+}
+
+// Use up someone's attempt at a challenge
+// (this happens as soon as the race is created, since that is when they see the seed)
+func (*Challenges) InsertParticipant(challengeID int, userID int, raceID int) error {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		INSERT INTO challenge_participants (challenge_id, user_id, race_id)
+		VALUES (?, ?, ?)
+	`); err != nil {
+		return err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.Exec(challengeID, userID, raceID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (*Challenges) HasParticipated(challengeID int, userID int) (bool, error) {
+	var count int
+	if err := db.QueryRow(`
+		SELECT COUNT(id)
+		FROM challenge_participants
+		WHERE challenge_id = ? AND user_id = ?
+	`, challengeID, userID).Scan(&count); err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// Get the start times of every challenge of the given type that this user has played, from oldest
+// to newest
+// Used in the "achievements501_503()" function
+func (*Challenges) GetParticipationStarts(userID int, challengeType string) ([]int64, error) {
+	starts := make([]int64, 0)
+
+	var rows *sql.Rows
+	if v, err := db.Query(`
+		SELECT UNIX_TIMESTAMP(c.datetime_start) * 1000
+		FROM challenge_participants cp
+			JOIN challenges c ON c.id = cp.challenge_id
+		WHERE cp.user_id = ? AND c.type = ?
+		ORDER BY c.datetime_start
+	`, userID, challengeType); err != nil {
+		return starts, err
+	} else {
+		rows = v
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var start int64
+		if err := rows.Scan(&start); err != nil {
+			return starts, err
+		}
+		starts = append(starts, start)
+	}
+
+	if err := rows.Err(); err != nil {
+		return starts, err
+	}
+
+	return starts, nil
+}
+
+// Check to see if a race was played for a challenge that has not ended yet
+// (the seed of these races should not be shown to anyone else)
+func (*Challenges) RaceIsOngoing(raceID int) (bool, error) {
+	var count int
+	if err := db.QueryRow(`
+		SELECT COUNT(cp.id)
+		FROM challenge_participants cp
+			JOIN challenges c ON c.id = cp.challenge_id
+		WHERE cp.race_id = ? AND c.datetime_end > NOW()
+	`, raceID).Scan(&count); err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
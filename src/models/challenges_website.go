@@ -0,0 +1,210 @@
+package models
+
+import (
+	"database/sql"
+)
+
+/*
+	These are more functions for querying the "challenges" table,
+	but these functions are only used for the website
+*/
+
+// ChallengeHistory gets the details of a daily or weekly challenge
+type ChallengeHistory struct {
+	ChallengeID                sql.NullInt64
+	ChallengeType              sql.NullString
+	ChallengeChar              sql.NullString
+	ChallengeGoal              sql.NullString
+	ChallengeStartingBuild     sql.NullInt64
+	ChallengeSeed              sql.NullString
+	ChallengeDateStart         sql.NullTime
+	ChallengeDateEnd           sql.NullTime
+	ChallengeOngoing           sql.NullBool
+	ChallengeNumParticipants   sql.NullInt64
+	ChallengeWinner            sql.NullString
+	ChallengeWinnerTime        sql.NullInt64
+	ChallengeStartingBuildName string
+	ChallengeStartingBuildID   int
+}
+
+// ChallengeLeaderboardRow gets the result of one attempt at a challenge
+type ChallengeLeaderboardRow struct {
+	Rank       int // 0 if they did not finish
+	RacerName  sql.NullString
+	RaceID     sql.NullInt64 // Null if they left the race before it started
+	RacerPlace sql.NullInt64 // Null if the race is still going; -1 is quit, -2 is disqualified
+	RacerTime  sql.NullInt64
+}
+
+// The columns that are selected for every "ChallengeHistory"
+// (the winner is the fastest finisher whose race is not being held back by an unresolved flag)
+const challengeHistoryColumns = `
+	c.id,
+	c.type,
+	c.player_type,
+	c.goal,
+	c.starting_build,
+	c.seed,
+	c.datetime_start,
+	c.datetime_end,
+	c.datetime_end > NOW(),
+	(
+		SELECT COUNT(cp.id)
+		FROM challenge_participants cp
+		WHERE cp.challenge_id = c.id
+	),
+	(
+		SELECT u.username
+		FROM challenge_participants cp
+			JOIN race_participants rp ON rp.race_id = cp.race_id AND rp.user_id = cp.user_id
+			JOIN users u ON u.id = cp.user_id
+		WHERE
+			cp.challenge_id = c.id
+			AND rp.place > 0
+			AND cp.race_id NOT IN (SELECT race_id FROM race_flags WHERE hold_leaderboard = 1 AND resolved = 0)
+		ORDER BY rp.run_time
+		LIMIT 1
+	),
+	(
+		SELECT rp.run_time
+		FROM challenge_participants cp
+			JOIN race_participants rp ON rp.race_id = cp.race_id AND rp.user_id = cp.user_id
+		WHERE
+			cp.challenge_id = c.id
+			AND rp.place > 0
+			AND cp.race_id NOT IN (SELECT race_id FROM race_flags WHERE hold_leaderboard = 1 AND resolved = 0)
+		ORDER BY rp.run_time
+		LIMIT 1
+	)
+`
+
+func scanChallengeHistory(scanner interface{ Scan(...interface{}) error }, challenge *ChallengeHistory) error {
+	return scanner.Scan(
+		&challenge.ChallengeID,
+		&challenge.ChallengeType,
+		&challenge.ChallengeChar,
+		&challenge.ChallengeGoal,
+		&challenge.ChallengeStartingBuild,
+		&challenge.ChallengeSeed,
+		&challenge.ChallengeDateStart,
+		&challenge.ChallengeDateEnd,
+		&challenge.ChallengeOngoing,
+		&challenge.ChallengeNumParticipants,
+		&challenge.ChallengeWinner,
+		&challenge.ChallengeWinnerTime,
+	)
+}
+
+// GetChallengesHistory gets the most recent challenges of a given type for the challenges page
+func (*Challenges) GetChallengesHistory(challengeType string, challengesPerPage int) ([]ChallengeHistory, error) {
+	challengesHistory := make([]ChallengeHistory, 0)
+
+	var rows *sql.Rows
+	if v, err := db.Query(`
+		SELECT `+challengeHistoryColumns+`
+		FROM challenges c
+		WHERE c.type = ?
+		ORDER BY c.datetime_start DESC
+		LIMIT ?
+	`, challengeType, challengesPerPage); err != nil {
+		return challengesHistory, err
+	} else {
+		rows = v
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var challenge ChallengeHistory
+		if err := scanChallengeHistory(rows, &challenge); err != nil {
+			return challengesHistory, err
+		}
+		challengesHistory = append(challengesHistory, challenge)
+	}
+
+	if err := rows.Err(); err != nil {
+		return challengesHistory, err
+	}
+
+	return challengesHistory, nil
+}
+
+// GetChallengeHistory gets a single challenge
+// (the second return value is false if the challenge does not exist)
+func (*Challenges) GetChallengeHistory(challengeID int) (ChallengeHistory, bool, error) {
+	var challenge ChallengeHistory
+	if err := scanChallengeHistory(db.QueryRow(`
+		SELECT `+challengeHistoryColumns+`
+		FROM challenges c
+		WHERE c.id = ?
+	`, challengeID), &challenge); err == sql.ErrNoRows {
+		return challenge, false, nil
+	} else if err != nil {
+		return challenge, false, err
+	}
+
+	return challenge, true, nil
+}
+
+// GetChallengeLeaderboard gets every attempt at a challenge, with the fastest finishers first
+// (races that are being held back by an unresolved flag are left out)
+func (*Challenges) GetChallengeLeaderboard(challengeID int) ([]ChallengeLeaderboardRow, error) {
+	leaderboard := make([]ChallengeLeaderboardRow, 0)
+
+	var rows *sql.Rows
+	if v, err := db.Query(`
+		SELECT
+			u.username,
+			cp.race_id,
+			rp.place,
+			rp.run_time
+		FROM
+			challenge_participants cp
+		JOIN
+			users u
+				ON u.id = cp.user_id
+		LEFT JOIN
+			race_participants rp
+				ON rp.race_id = cp.race_id AND rp.user_id = cp.user_id
+		WHERE
+			cp.challenge_id = ?
+			AND (
+				cp.race_id IS NULL
+				OR cp.race_id NOT IN (SELECT race_id FROM race_flags WHERE hold_leaderboard = 1 AND resolved = 0)
+			)
+		ORDER BY
+			CASE WHEN rp.place > 0 THEN 0 ELSE 1 END,
+			rp.run_time,
+			cp.datetime_created
+	`, challengeID); err != nil {
+		return leaderboard, err
+	} else {
+		rows = v
+	}
+	defer rows.Close()
+
+	rank := 0
+	for rows.Next() {
+		var row ChallengeLeaderboardRow
+		if err := rows.Scan(
+			&row.RacerName,
+			&row.RaceID,
+			&row.RacerPlace,
+			&row.RacerTime,
+		); err != nil {
+			return leaderboard, err
+		}
+
+		if row.RacerPlace.Valid && row.RacerPlace.Int64 > 0 {
+			rank++
+			row.Rank = rank
+		}
+
+		leaderboard = append(leaderboard, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return leaderboard, err
+	}
+
+	return leaderboard, nil
+}
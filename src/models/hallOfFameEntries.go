@@ -0,0 +1,286 @@
+package models
+
+import (
+	"database/sql"
+)
+
+type HallOfFameEntries struct{}
+
+// Speedrun seasons use the time, date, proof, and site
+// Ranked solo seasons use the averages and the forfeits
+type HallOfFameEntry struct {
+	ID                int
+	Rank              int
+	Racer             string
+	ProfileName       string
+	Time              int // In seconds
+	Version           string
+	Date              string
+	Proof             string
+	Site              string
+	AdjustedAverage   int // In seconds
+	UnadjustedAverage int // In seconds
+	ForfeitPenalty    int // In seconds
+	NumForfeits       int
+	NumRaces          int
+}
+
+// Get the entries for every season (indexed by season ID)
+func (*HallOfFameEntries) GetAll() (map[int][]HallOfFameEntry, error) {
+	entries := make(map[int][]HallOfFameEntry)
+
+	var rows *sql.Rows
+	if v, err := db.Query(`
+		SELECT
+			id,
+			season_id,
+			` + "`rank`" + `,
+			racer,
+			profile_name,
+			time,
+			version,
+			date,
+			proof,
+			site,
+			adjusted_average,
+			unadjusted_average,
+			forfeit_penalty,
+			num_forfeits,
+			num_races
+		FROM hall_of_fame_entries
+		ORDER BY season_id, ` + "`rank`" + `
+	`); err != nil {
+		return entries, err
+	} else {
+		rows = v
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var seasonID int
+		var entry HallOfFameEntry
+		if err := rows.Scan(
+			&entry.ID,
+			&seasonID,
+			&entry.Rank,
+			&entry.Racer,
+			&entry.ProfileName,
+			&entry.Time,
+			&entry.Version,
+			&entry.Date,
+			&entry.Proof,
+			&entry.Site,
+			&entry.AdjustedAverage,
+			&entry.UnadjustedAverage,
+			&entry.ForfeitPenalty,
+			&entry.NumForfeits,
+			&entry.NumRaces,
+		); err != nil {
+			return entries, err
+		}
+		entries[seasonID] = append(entries[seasonID], entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return entries, err
+	}
+
+	return entries, nil
+}
+
+// Used when a submission is approved
+func (*HallOfFameEntries) InsertMany(seasonID int, entries []HallOfFameEntry) error {
+	var tx *sql.Tx
+	if v, err := db.Begin(); err != nil {
+		return err
+	} else {
+		tx = v
+	}
+	defer tx.Rollback() // nolint: errcheck
+
+	if err := hallOfFameEntriesInsert(tx, seasonID, entries); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Also used when a season closes and the leaderboard is copied to the hall of fame
+func hallOfFameEntriesInsert(tx *sql.Tx, seasonID int, entries []HallOfFameEntry) error {
+	var stmt *sql.Stmt
+	if v, err := tx.Prepare(`
+		INSERT INTO hall_of_fame_entries (
+			season_id,
+			` + "`rank`" + `,
+			racer,
+			profile_name,
+			time,
+			version,
+			date,
+			proof,
+			site,
+			adjusted_average,
+			unadjusted_average,
+			forfeit_penalty,
+			num_forfeits,
+			num_races
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`); err != nil {
+		return err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	for _, entry := range entries {
+		if _, err := stmt.Exec(
+			seasonID,
+			entry.Rank,
+			entry.Racer,
+			entry.ProfileName,
+			entry.Time,
+			entry.Version,
+			entry.Date,
+			entry.Proof,
+			entry.Site,
+			entry.AdjustedAverage,
+			entry.UnadjustedAverage,
+			entry.ForfeitPenalty,
+			entry.NumForfeits,
+			entry.NumRaces,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Get the speedrun entry for a specific person in a season
+// (the second return value is false if they do not have one)
+func (*HallOfFameEntries) GetSpeedrun(seasonID int, profileName string) (HallOfFameEntry, bool, error) {
+	var entry HallOfFameEntry
+	if err := db.QueryRow(`
+		SELECT
+			id,
+			`+"`rank`"+`,
+			racer,
+			profile_name,
+			time,
+			version,
+			date,
+			proof,
+			site
+		FROM hall_of_fame_entries
+		WHERE season_id = ? AND profile_name = ?
+	`, seasonID, profileName).Scan(
+		&entry.ID,
+		&entry.Rank,
+		&entry.Racer,
+		&entry.ProfileName,
+		&entry.Time,
+		&entry.Version,
+		&entry.Date,
+		&entry.Proof,
+		&entry.Site,
+	); err == sql.ErrNoRows {
+		return entry, false, nil
+	} else if err != nil {
+		return entry, false, err
+	}
+
+	return entry, true, nil
+}
+
+// Used when someone improves on their existing run
+func (*HallOfFameEntries) SetSpeedrun(entryID int, entry HallOfFameEntry) error {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		UPDATE hall_of_fame_entries
+		SET
+			time = ?,
+			version = ?,
+			date = ?,
+			proof = ?,
+			site = ?
+		WHERE id = ?
+	`); err != nil {
+		return err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.Exec(
+		entry.Time,
+		entry.Version,
+		entry.Date,
+		entry.Proof,
+		entry.Site,
+		entryID,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Rank every run in a speedrun season from fastest to slowest
+// (people with the same time share the same rank)
+func (*HallOfFameEntries) RerankSpeedrun(seasonID int) error {
+	type RankedEntry struct {
+		ID   int
+		Time int
+	}
+	entries := make([]RankedEntry, 0)
+
+	var rows *sql.Rows
+	if v, err := db.Query(`
+		SELECT id, time
+		FROM hall_of_fame_entries
+		WHERE season_id = ?
+		ORDER BY time, id
+	`, seasonID); err != nil {
+		return err
+	} else {
+		rows = v
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry RankedEntry
+		if err := rows.Scan(&entry.ID, &entry.Time); err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		UPDATE hall_of_fame_entries
+		SET ` + "`rank`" + ` = ?
+		WHERE id = ?
+	`); err != nil {
+		return err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	rank := 0
+	for i, entry := range entries {
+		if i == 0 || entry.Time != entries[i-1].Time {
+			rank = i + 1
+		}
+		if _, err := stmt.Exec(rank, entry.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+package models
+
+import (
+	"database/sql"
+)
+
+type HallOfFameSubmissions struct{}
+
+type HallOfFameSubmission struct {
+	ID              int    `json:"id"`
+	SeasonID        int    `json:"seasonID"` // nolint:tagliatelle
+	SeasonName      string `json:"seasonName"`
+	UserID          int    `json:"userID"` // nolint:tagliatelle
+	Username        string `json:"username"`
+	Time            int    `json:"time"` // In seconds
+	Version         string `json:"version"`
+	Proof           string `json:"proof"`
+	Site            string `json:"site"`
+	Status          string `json:"status"`
+	DatetimeCreated int64  `json:"datetimeCreated"` // Epoch timestamp in seconds
+}
+
+const hallOfFameSubmissionColumns = `
+	hfs.id,
+	hfs.season_id,
+	s.name,
+	hfs.user_id,
+	u.username,
+	hfs.time,
+	hfs.version,
+	hfs.proof,
+	hfs.site,
+	hfs.status,
+	UNIX_TIMESTAMP(hfs.datetime_created)
+`
+
+const hallOfFameSubmissionJoins = `
+	hall_of_fame_submissions hfs
+	JOIN seasons s ON s.id = hfs.season_id
+	JOIN users u ON u.id = hfs.user_id
+`
+
+func scanHallOfFameSubmission(scanner interface{ Scan(...interface{}) error }, submission *HallOfFameSubmission) error {
+	return scanner.Scan(
+		&submission.ID,
+		&submission.SeasonID,
+		&submission.SeasonName,
+		&submission.UserID,
+		&submission.Username,
+		&submission.Time,
+		&submission.Version,
+		&submission.Proof,
+		&submission.Site,
+		&submission.Status,
+		&submission.DatetimeCreated,
+	)
+}
+
+// Add a run to the staff review queue
+func (*HallOfFameSubmissions) Insert(submission HallOfFameSubmission) (int, error) {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		INSERT INTO hall_of_fame_submissions (
+			season_id,
+			user_id,
+			time,
+			version,
+			proof,
+			site
+		)
+		VALUES (
+			?,
+			?,
+			?,
+			?,
+			?,
+			?
+		)
+	`); err != nil {
+		return 0, err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	var res sql.Result
+	if v, err := stmt.Exec(
+		submission.SeasonID,
+		submission.UserID,
+		submission.Time,
+		submission.Version,
+		submission.Proof,
+		submission.Site,
+	); err != nil {
+		return 0, err
+	} else {
+		res = v
+	}
+
+	var submissionID int
+	if v, err := res.LastInsertId(); err != nil {
+		return 0, err
+	} else {
+		submissionID = int(v)
+	}
+
+	return submissionID, nil
+}
+
+// The second return value is false if the submission does not exist
+func (*HallOfFameSubmissions) Get(submissionID int) (HallOfFameSubmission, bool, error) {
+	var submission HallOfFameSubmission
+	if err := scanHallOfFameSubmission(db.QueryRow(`
+		SELECT `+hallOfFameSubmissionColumns+`
+		FROM `+hallOfFameSubmissionJoins+`
+		WHERE hfs.id = ?
+	`, submissionID), &submission); err == sql.ErrNoRows {
+		return submission, false, nil
+	} else if err != nil {
+		return submission, false, err
+	}
+
+	return submission, true, nil
+}
+
+// Get the review queue, oldest first
+func (*HallOfFameSubmissions) GetAllPending() ([]HallOfFameSubmission, error) {
+	submissions := make([]HallOfFameSubmission, 0)
+
+	var rows *sql.Rows
+	if v, err := db.Query(`
+		SELECT ` + hallOfFameSubmissionColumns + `
+		FROM ` + hallOfFameSubmissionJoins + `
+		WHERE hfs.status = "pending"
+		ORDER BY hfs.id
+	`); err != nil {
+		return submissions, err
+	} else {
+		rows = v
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var submission HallOfFameSubmission
+		if err := scanHallOfFameSubmission(rows, &submission); err != nil {
+			return submissions, err
+		}
+		submissions = append(submissions, submission)
+	}
+
+	if err := rows.Err(); err != nil {
+		return submissions, err
+	}
+
+	return submissions, nil
+}
+
+// Each person can only have one run waiting to be reviewed for each season
+func (*HallOfFameSubmissions) HasPending(seasonID int, userID int) (bool, error) {
+	var id int
+	if err := db.QueryRow(`
+		SELECT id
+		FROM hall_of_fame_submissions
+		WHERE season_id = ? AND user_id = ? AND status = "pending"
+		LIMIT 1
+	`, seasonID, userID).Scan(&id); err == sql.ErrNoRows {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (*HallOfFameSubmissions) SetReviewed(submissionID int, status string, reviewedBy int, reason string) error {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		UPDATE hall_of_fame_submissions
+		SET
+			status = ?,
+			reviewed_by = ?,
+			reason = ?,
+			datetime_reviewed = NOW()
+		WHERE id = ?
+	`); err != nil {
+		return err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.Exec(status, reviewedBy, reason, submissionID); err != nil {
+		return err
+	}
+
+	return nil
+}
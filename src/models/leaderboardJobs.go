@@ -0,0 +1,538 @@
+package models
+
+import (
+	"database/sql"
+	"errors"
+)
+
+type LeaderboardJobs struct{}
+
+var (
+	// Returned by "Apply()" when the live leaderboard has moved on since the job was started
+	ErrLeaderboardJobStale = errors.New("races for this leaderboard have finished since the job was started")
+
+	// Returned by "Apply()" when the job is not in the "ready" status
+	ErrLeaderboardJobNotReady = errors.New("the leaderboard job is not ready to be applied")
+)
+
+type LeaderboardJob struct {
+	ID              int    `json:"id"`
+	Format          string `json:"format"`
+	Engine          string `json:"engine"` // Blank for ranked solo
+	Status          string `json:"status"`
+	StartedBy       string `json:"startedBy"`       // Blank if the server started it
+	DatetimeCreated int64  `json:"datetimeCreated"` // Epoch timestamp in seconds
+}
+
+// The stats of one person on a staged leaderboard
+// The TrueSkill formats use the rating, mu, sigma, volatility, and change
+// Ranked solo uses the rating (which is the adjusted average), the real average, and the forfeits
+type LeaderboardStagedStats struct {
+	UserID         int
+	Rating         float64
+	Mu             float64
+	Sigma          float64
+	Volatility     float64
+	Change         float64
+	RealAverage    int
+	NumForfeits    int
+	ForfeitPenalty int
+	LowestTime     int
+	NumRaces       int
+}
+
+// How one person's rating would change if a staged leaderboard was applied
+// (the ratings are 0 if they are not on that version of the leaderboard)
+type LeaderboardJobDiffRow struct {
+	UserID      int
+	Username    string
+	OldRating   float64
+	NewRating   float64
+	OldNumRaces int
+	NewNumRaces int
+}
+
+var leaderboardStagingTables = []string{
+	"leaderboard_staging_stats",
+	"leaderboard_staging_history",
+}
+
+const leaderboardJobColumns = `
+	lj.id,
+	lj.format,
+	lj.engine,
+	lj.status,
+	IFNULL(u.username, ""),
+	UNIX_TIMESTAMP(lj.datetime_created)
+`
+
+const leaderboardJobJoins = `
+	leaderboard_jobs lj
+	LEFT JOIN users u ON u.id = lj.started_by
+`
+
+func scanLeaderboardJob(scanner interface{ Scan(...interface{}) error }, job *LeaderboardJob) error {
+	return scanner.Scan(
+		&job.ID,
+		&job.Format,
+		&job.Engine,
+		&job.Status,
+		&job.StartedBy,
+		&job.DatetimeCreated,
+	)
+}
+
+// A user ID of 0 means that the server started the job
+func (*LeaderboardJobs) Insert(format string, engine string, startedBy int) (int, error) {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		INSERT INTO leaderboard_jobs (format, engine, started_by)
+		VALUES (?, ?, NULLIF(?, 0))
+	`); err != nil {
+		return 0, err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	var res sql.Result
+	if v, err := stmt.Exec(format, engine, startedBy); err != nil {
+		return 0, err
+	} else {
+		res = v
+	}
+
+	var jobID int
+	if v, err := res.LastInsertId(); err != nil {
+		return 0, err
+	} else {
+		jobID = int(v)
+	}
+
+	return jobID, nil
+}
+
+// The second return value is false if the job does not exist
+func (*LeaderboardJobs) Get(jobID int) (LeaderboardJob, bool, error) {
+	var job LeaderboardJob
+	if err := scanLeaderboardJob(db.QueryRow(`
+		SELECT `+leaderboardJobColumns+`
+		FROM `+leaderboardJobJoins+`
+		WHERE lj.id = ?
+	`, jobID), &job); err == sql.ErrNoRows {
+		return job, false, nil
+	} else if err != nil {
+		return job, false, err
+	}
+
+	return job, true, nil
+}
+
+// Get the most recent jobs, newest first
+func (*LeaderboardJobs) GetRecent(n int) ([]LeaderboardJob, error) {
+	jobs := make([]LeaderboardJob, 0)
+
+	var rows *sql.Rows
+	if v, err := db.Query(`
+		SELECT `+leaderboardJobColumns+`
+		FROM `+leaderboardJobJoins+`
+		ORDER BY lj.id DESC
+		LIMIT ?
+	`, n); err != nil {
+		return jobs, err
+	} else {
+		rows = v
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var job LeaderboardJob
+		if err := scanLeaderboardJob(rows, &job); err != nil {
+			return jobs, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return jobs, err
+	}
+
+	return jobs, nil
+}
+
+func (*LeaderboardJobs) SetStatus(jobID int, status string) error {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		UPDATE leaderboard_jobs
+		SET status = ?
+		WHERE id = ?
+	`); err != nil {
+		return err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.Exec(status, jobID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Used when the server starts to mark the jobs that were interrupted
+func (*LeaderboardJobs) SetAllStatus(oldStatus string, newStatus string) error {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		UPDATE leaderboard_jobs
+		SET status = ?
+		WHERE status = ?
+	`); err != nil {
+		return err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.Exec(newStatus, oldStatus); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Used when the races of a format change, so that the jobs that were already calculated cannot be
+// applied
+func (*LeaderboardJobs) SetFormatStatus(format string, oldStatus string, newStatus string) error {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		UPDATE leaderboard_jobs
+		SET status = ?
+		WHERE format = ? AND status = ?
+	`); err != nil {
+		return err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.Exec(newStatus, format, oldStatus); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Write the results of a job to the staging tables
+// (this is done in one transaction because there can be tens of thousands of rows)
+func (*LeaderboardJobs) InsertStaging(
+	jobID int,
+	stats []LeaderboardStagedStats,
+	history []RatingHistoryEntry,
+) error {
+	var tx *sql.Tx
+	if v, err := db.Begin(); err != nil {
+		return err
+	} else {
+		tx = v
+	}
+	defer tx.Rollback() // nolint: errcheck
+
+	var statsStmt *sql.Stmt
+	if v, err := tx.Prepare(`
+		INSERT INTO leaderboard_staging_stats (
+			job_id,
+			user_id,
+			rating,
+			mu,
+			sigma,
+			volatility,
+			rating_change,
+			real_average,
+			num_forfeits,
+			forfeit_penalty,
+			lowest_time,
+			num_races
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`); err != nil {
+		return err
+	} else {
+		statsStmt = v
+	}
+	defer statsStmt.Close()
+
+	for _, row := range stats {
+		if _, err := statsStmt.Exec(
+			jobID,
+			row.UserID,
+			row.Rating,
+			row.Mu,
+			row.Sigma,
+			row.Volatility,
+			row.Change,
+			row.RealAverage,
+			row.NumForfeits,
+			row.ForfeitPenalty,
+			row.LowestTime,
+			row.NumRaces,
+		); err != nil {
+			return err
+		}
+	}
+
+	var historyStmt *sql.Stmt
+	if v, err := tx.Prepare(`
+		INSERT INTO leaderboard_staging_history (
+			job_id,
+			user_id,
+			race_id,
+			rating,
+			mu,
+			sigma,
+			rating_change
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`); err != nil {
+		return err
+	} else {
+		historyStmt = v
+	}
+	defer historyStmt.Close()
+
+	for _, entry := range history {
+		if _, err := historyStmt.Exec(
+			jobID,
+			entry.UserID,
+			entry.RaceID,
+			entry.Rating,
+			entry.Mu,
+			entry.Sigma,
+			entry.RatingChange,
+		); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Used when a job is discarded or after it has been applied
+func (*LeaderboardJobs) DeleteStaging(jobID int) error {
+	for _, table := range leaderboardStagingTables {
+		if _, err := db.Exec(`DELETE FROM `+table+` WHERE job_id = ?`, jobID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Compare the staged leaderboard with the live one for everyone who is on either of them
+func (*LeaderboardJobs) GetDiff(jobID int, format string) ([]LeaderboardJobDiffRow, error) {
+	diff := make([]LeaderboardJobDiffRow, 0)
+
+	ratingColumn := format + "_trueskill"
+	if format == "ranked_solo" {
+		ratingColumn = "ranked_solo_adjusted_average"
+	}
+
+	var rows *sql.Rows
+	if v, err := db.Query(`
+		SELECT
+			u.id,
+			u.username,
+			IF(u.`+format+`_num_races > 0, u.`+ratingColumn+`, 0),
+			IFNULL(lss.rating, 0),
+			u.`+format+`_num_races,
+			IFNULL(lss.num_races, 0)
+		FROM users u
+			LEFT JOIN leaderboard_staging_stats lss
+				ON lss.user_id = u.id AND lss.job_id = ?
+		WHERE
+			u.`+format+`_num_races > 0
+			OR lss.user_id IS NOT NULL
+	`, jobID); err != nil {
+		return diff, err
+	} else {
+		rows = v
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row LeaderboardJobDiffRow
+		if err := rows.Scan(
+			&row.UserID,
+			&row.Username,
+			&row.OldRating,
+			&row.NewRating,
+			&row.OldNumRaces,
+			&row.NewNumRaces,
+		); err != nil {
+			return diff, err
+		}
+		diff = append(diff, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return diff, err
+	}
+
+	return diff, nil
+}
+
+// Replace the live leaderboard and rating history for a format with the staged ones and mark the
+// job as applied
+// Everything is done in one transaction, so the leaderboard is never in a half-finished state
+// If a race for this format finished after the job started, nothing is changed and
+// "ErrLeaderboardJobStale" is returned
+func (*LeaderboardJobs) Apply(jobID int, format string) error {
+	var tx *sql.Tx
+	if v, err := db.Begin(); err != nil {
+		return err
+	} else {
+		tx = v
+	}
+	defer tx.Rollback() // nolint: errcheck
+
+	// Lock the job so that it cannot be applied twice or discarded in the meantime
+	var status string
+	if err := tx.QueryRow(`
+		SELECT status
+		FROM leaderboard_jobs
+		WHERE id = ?
+		FOR UPDATE
+	`, jobID).Scan(&status); err == sql.ErrNoRows {
+		return ErrLeaderboardJobNotReady
+	} else if err != nil {
+		return err
+	}
+	if status != "ready" {
+		return ErrLeaderboardJobNotReady
+	}
+
+	racesCondition := `races.format = "` + format + `" AND races.solo = 0`
+	if format == "ranked_solo" {
+		racesCondition = `races.ranked = 1 AND races.solo = 1`
+	}
+	var numNewRaces int
+	if err := tx.QueryRow(`
+		SELECT COUNT(races.id)
+		FROM races
+		WHERE
+			races.finished = 1
+			AND `+racesCondition+`
+			AND races.datetime_finished >= (SELECT datetime_created FROM leaderboard_jobs WHERE id = ?)
+	`, jobID).Scan(&numNewRaces); err != nil {
+		return err
+	}
+	if numNewRaces > 0 {
+		return ErrLeaderboardJobStale
+	}
+
+	// Reset everyone, since people can drop off of the leaderboard entirely
+	// (for ranked solo, the metadata is left alone since it holds the build of the next race)
+	var resetSQL string
+	var setSQL string
+	if format == "ranked_solo" {
+		resetSQL = `
+			UPDATE users
+			SET
+				ranked_solo_adjusted_average = 0,
+				ranked_solo_real_average = 0,
+				ranked_solo_num_forfeits = 0,
+				ranked_solo_forfeit_penalty = 0,
+				ranked_solo_lowest_time = 0,
+				ranked_solo_num_races = 0
+		`
+		setSQL = `
+			UPDATE users u
+				JOIN leaderboard_staging_stats lss ON lss.user_id = u.id
+			SET
+				u.ranked_solo_adjusted_average = ROUND(lss.rating),
+				u.ranked_solo_real_average = lss.real_average,
+				u.ranked_solo_num_forfeits = lss.num_forfeits,
+				u.ranked_solo_forfeit_penalty = lss.forfeit_penalty,
+				u.ranked_solo_lowest_time = lss.lowest_time,
+				u.ranked_solo_num_races = lss.num_races
+			WHERE lss.job_id = ?
+		`
+	} else {
+		resetSQL = `
+			UPDATE users
+			SET
+				` + format + `_trueskill = 0,
+				` + format + `_trueskill_mu = 25,
+				` + format + `_trueskill_sigma = 8.333,
+				` + format + `_trueskill_change = 0,
+				` + format + `_trueskill_volatility = 0.06,
+				` + format + `_num_races = 0
+		`
+		setSQL = `
+			UPDATE users u
+				JOIN leaderboard_staging_stats lss ON lss.user_id = u.id
+			SET
+				u.` + format + `_trueskill = lss.rating,
+				u.` + format + `_trueskill_mu = lss.mu,
+				u.` + format + `_trueskill_sigma = lss.sigma,
+				u.` + format + `_trueskill_change = lss.rating_change,
+				u.` + format + `_trueskill_volatility = lss.volatility,
+				u.` + format + `_num_races = lss.num_races
+			WHERE lss.job_id = ?
+		`
+	}
+	if _, err := tx.Exec(resetSQL); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(setSQL, jobID); err != nil {
+		return err
+	}
+
+	// Fix the "Date of Last Race" column
+	if _, err := tx.Exec(getSetLastRaceSQL(format)); err != nil {
+		return err
+	}
+
+	// Replace the rating history
+	if _, err := tx.Exec(`DELETE FROM rating_history WHERE format = ?`, format); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO rating_history (
+			user_id,
+			race_id,
+			format,
+			rating,
+			mu,
+			sigma,
+			rating_change,
+			datetime_created
+		)
+		SELECT
+			lsh.user_id,
+			lsh.race_id,
+			?,
+			lsh.rating,
+			lsh.mu,
+			lsh.sigma,
+			lsh.rating_change,
+			IFNULL(races.datetime_finished, NOW())
+		FROM leaderboard_staging_history lsh
+			JOIN races ON races.id = lsh.race_id
+		WHERE lsh.job_id = ?
+	`, format, jobID); err != nil {
+		return err
+	}
+
+	// The staged rows are no longer needed
+	if _, err := tx.Exec(`UPDATE leaderboard_jobs SET status = "applied" WHERE id = ?`, jobID); err != nil {
+		return err
+	}
+	for _, table := range leaderboardStagingTables {
+		if _, err := tx.Exec(`DELETE FROM `+table+` WHERE job_id = ?`, jobID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -22,13 +22,25 @@ type Models struct {
 	Achievements
 	BannedIPs
 	BannedUsers
+	Challenges
 	ChatLogPM
 	ChatLog
+	HallOfFameEntries
+	HallOfFameSubmissions
+	LeaderboardJobs
+	RaceCheckpoints
+	RaceFlags
 	RaceParticipantItems
 	RaceParticipantRooms
+	RaceParticipantSplits
 	RaceParticipants
 	Races
+	RatingHistory
+	Seasons
 	MutedUsers
+	Series
+	SeriesParticipants
+	SeriesRaces
 	Tournament
 	UserAchievements
 	Users
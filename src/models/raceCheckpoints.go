@@ -0,0 +1,90 @@
+package models
+
+import (
+	"database/sql"
+)
+
+type RaceCheckpoints struct{}
+
+// This mirrors the "race_checkpoints" table row
+type RaceCheckpoint struct {
+	RaceID int
+	State  string // The JSON-encoded in-memory race
+}
+
+// Create or overwrite the checkpoint for an ongoing race
+// Used in the "race.Checkpoint()" function
+func (*RaceCheckpoints) Set(raceID int, state string) error {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		INSERT INTO race_checkpoints (race_id, state)
+		VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE
+			state = VALUES(state),
+			datetime_updated = NOW()
+	`); err != nil {
+		return err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.Exec(raceID, state); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (*RaceCheckpoints) Delete(raceID int) error {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		DELETE FROM race_checkpoints
+		WHERE race_id = ?
+	`); err != nil {
+		return err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.Exec(raceID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Get every checkpoint for a race that has not finished yet
+// Used in the "raceRestoreAll()" function
+func (*RaceCheckpoints) GetAll() ([]RaceCheckpoint, error) {
+	checkpoints := make([]RaceCheckpoint, 0)
+
+	var rows *sql.Rows
+	if v, err := db.Query(`
+		SELECT race_checkpoints.race_id, race_checkpoints.state
+		FROM race_checkpoints
+			JOIN races ON race_checkpoints.race_id = races.id
+		WHERE races.finished = 0
+		ORDER BY race_checkpoints.race_id
+	`); err != nil {
+		return checkpoints, err
+	} else {
+		rows = v
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var checkpoint RaceCheckpoint
+		if err := rows.Scan(&checkpoint.RaceID, &checkpoint.State); err != nil {
+			return checkpoints, err
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+
+	if err := rows.Err(); err != nil {
+		return checkpoints, err
+	}
+
+	return checkpoints, nil
+}
@@ -0,0 +1,194 @@
+package models
+
+import (
+	"database/sql"
+)
+
+type RaceFlags struct{}
+
+type RaceFlag struct {
+	ID              int    `json:"id"`
+	RaceID          int    `json:"raceID"` // nolint:tagliatelle
+	UserID          int    `json:"userID"` // nolint:tagliatelle
+	Username        string `json:"username"`
+	Type            string `json:"type"`
+	Description     string `json:"description"`
+	HoldLeaderboard bool   `json:"holdLeaderboard"`
+	Resolved        bool   `json:"resolved"`
+	DatetimeCreated int64  `json:"datetimeCreated"` // Epoch timestamp in seconds
+}
+
+// Add something suspicious about a racer to the admin review queue
+func (*RaceFlags) Insert(raceID int, userID int, flagType string, description string, holdLeaderboard bool) error {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		INSERT INTO race_flags (
+			race_id,
+			user_id,
+			type,
+			description,
+			hold_leaderboard
+		)
+		VALUES (
+			?,
+			?,
+			?,
+			?,
+			?
+		)
+	`); err != nil {
+		return err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.Exec(
+		raceID,
+		userID,
+		flagType,
+		description,
+		holdLeaderboard,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// The second return value is false if the flag does not exist
+func (*RaceFlags) Get(flagID int) (RaceFlag, bool, error) {
+	var flag RaceFlag
+	if err := db.QueryRow(`
+		SELECT
+			rf.id,
+			rf.race_id,
+			rf.user_id,
+			u.username,
+			rf.type,
+			rf.description,
+			rf.hold_leaderboard,
+			rf.resolved,
+			UNIX_TIMESTAMP(rf.datetime_created)
+		FROM
+			race_flags rf
+		JOIN
+			users u
+				ON u.id = rf.user_id
+		WHERE
+			rf.id = ?
+	`, flagID).Scan(
+		&flag.ID,
+		&flag.RaceID,
+		&flag.UserID,
+		&flag.Username,
+		&flag.Type,
+		&flag.Description,
+		&flag.HoldLeaderboard,
+		&flag.Resolved,
+		&flag.DatetimeCreated,
+	); err == sql.ErrNoRows {
+		return flag, false, nil
+	} else if err != nil {
+		return flag, false, err
+	}
+
+	return flag, true, nil
+}
+
+// Get the review queue, oldest first
+func (*RaceFlags) GetAllUnresolved() ([]RaceFlag, error) {
+	flags := make([]RaceFlag, 0)
+
+	var rows *sql.Rows
+	if v, err := db.Query(`
+		SELECT
+			rf.id,
+			rf.race_id,
+			rf.user_id,
+			u.username,
+			rf.type,
+			rf.description,
+			rf.hold_leaderboard,
+			rf.resolved,
+			UNIX_TIMESTAMP(rf.datetime_created)
+		FROM
+			race_flags rf
+		JOIN
+			users u
+				ON u.id = rf.user_id
+		WHERE
+			rf.resolved = 0
+		ORDER BY
+			rf.id
+	`); err != nil {
+		return flags, err
+	} else {
+		rows = v
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var flag RaceFlag
+		if err := rows.Scan(
+			&flag.ID,
+			&flag.RaceID,
+			&flag.UserID,
+			&flag.Username,
+			&flag.Type,
+			&flag.Description,
+			&flag.HoldLeaderboard,
+			&flag.Resolved,
+			&flag.DatetimeCreated,
+		); err != nil {
+			return flags, err
+		}
+		flags = append(flags, flag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return flags, err
+	}
+
+	return flags, nil
+}
+
+func (*RaceFlags) Resolve(flagID int, adminID int) error {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		UPDATE race_flags
+		SET
+			resolved = 1,
+			resolved_by = ?,
+			datetime_resolved = NOW()
+		WHERE id = ?
+	`); err != nil {
+		return err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.Exec(adminID, flagID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Check to see if a race is still being kept out of the leaderboards
+func (*RaceFlags) RaceIsHeld(raceID int) (bool, error) {
+	var numFlags int
+	if err := db.QueryRow(`
+		SELECT COUNT(id)
+		FROM race_flags
+		WHERE
+			race_id = ?
+			AND hold_leaderboard = 1
+			AND resolved = 0
+	`, raceID).Scan(&numFlags); err != nil {
+		return false, err
+	}
+
+	return numFlags > 0, nil
+}
@@ -0,0 +1,129 @@
+package models
+
+import (
+	"database/sql"
+)
+
+type RaceParticipantSplits struct{}
+
+// Add the split to the list of floor splits for this person's race
+func (*RaceParticipantSplits) Insert(userID int, raceID int, floorNum int, stageType int, splitTime int64, datetimeArrived int64) error {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		INSERT INTO race_participant_splits (
+			race_participant_id,
+			floor_num,
+			stage_type,
+			split_time,
+			datetime_arrived
+		)
+		VALUES (
+			(SELECT id FROM race_participants WHERE user_id = ? AND race_id = ?),
+			?,
+			?,
+			?,
+			FROM_UNIXTIME(? / 1000)
+		)
+	`); err != nil {
+		return err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.Exec(
+		userID,
+		raceID,
+		floorNum,
+		stageType,
+		splitTime,
+		datetimeArrived,
+	); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Get the splits from the fastest finished race with the same format, character, goal, and whether
+// it was ranked and solo
+// The map is indexed by floor number and the values are the split times in milliseconds
+// (if they arrived on a floor more than once, e.g. from a reset, the last arrival is used)
+// (the map is empty if they have never finished a race like this with splits)
+func (*RaceParticipantSplits) GetPersonalBest(
+	userID int,
+	format string,
+	character string,
+	goal string,
+	ranked bool,
+	solo bool,
+) (map[int]int64, error) {
+	splits := make(map[int]int64)
+
+	// Convert some bools to ints
+	rankedInt := 0
+	if ranked {
+		rankedInt = 1
+	}
+	soloInt := 0
+	if solo {
+		soloInt = 1
+	}
+
+	var rows *sql.Rows
+	if v, err := db.Query(`
+		SELECT
+			floor_num,
+			MAX(split_time)
+		FROM
+			race_participant_splits
+		WHERE
+			race_participant_id = (
+				SELECT
+					rp.id
+				FROM
+					race_participants rp
+				JOIN
+					races r
+						ON r.id = rp.race_id
+				WHERE
+					rp.user_id = ?
+					AND rp.place > 0
+					AND r.finished = 1
+					AND r.format = ?
+					AND r.player_type = ?
+					AND r.goal = ?
+					AND r.ranked = ?
+					AND r.solo = ?
+					AND EXISTS (
+						SELECT id FROM race_participant_splits WHERE race_participant_id = rp.id
+					)
+				ORDER BY
+					rp.run_time
+				LIMIT
+					1
+			)
+		GROUP BY
+			floor_num
+	`, userID, format, character, goal, rankedInt, soloInt); err != nil {
+		return splits, err
+	} else {
+		rows = v
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var floorNum int
+		var splitTime int64
+		if err := rows.Scan(&floorNum, &splitTime); err != nil {
+			return splits, err
+		}
+		splits[floorNum] = splitTime
+	}
+
+	if err := rows.Err(); err != nil {
+		return splits, err
+	}
+
+	return splits, nil
+}
@@ -17,6 +17,8 @@ type Racer struct {
 	DatetimeFinished int64
 	RunTime          int64 /* in milliseconds */
 	Comment          string
+	Team             int
+	NumResets        int
 }
 
 func (*RaceParticipants) Insert(raceID int, racer *Racer) error {
@@ -31,7 +33,9 @@ func (*RaceParticipants) Insert(raceID int, racer *Racer) error {
 			place,
 			datetime_finished,
 			run_time,
-			comment
+			comment,
+			team,
+			num_resets
 		)
 		VALUES (
 			?,
@@ -42,6 +46,8 @@ func (*RaceParticipants) Insert(raceID int, racer *Racer) error {
 			?,
 			FROM_UNIXTIME(?),
 			?,
+			?,
+			?,
 			?
 		)
 	`); err != nil {
@@ -61,6 +67,8 @@ func (*RaceParticipants) Insert(raceID int, racer *Racer) error {
 		racer.DatetimeFinished,
 		racer.RunTime,
 		racer.Comment,
+		racer.Team,
+		racer.NumResets,
 	); err != nil {
 		return err
 	}
@@ -112,7 +120,11 @@ type RaceResult struct {
 
 // Get a list of the a player's times for ranked solo races
 // Used in the "leaderboardUpdateSoloUnseeded()" function
-func (*RaceParticipants) GetNRankedSoloRaceResults(userID int, n int) ([]RaceResult, error) {
+// Only races that finished at or before the given race are counted,
+// so that recalculating the leaderboard gives the same averages that people had at the time
+func (*RaceParticipants) GetNRankedSoloRaceResults(userID int, raceID int, n int) ([]RaceResult, error) {
+	seasonStart, seasonEnd := getRankedSoloSeason()
+
 	var timeList []RaceResult
 
 	var rows *sql.Rows
@@ -125,11 +137,13 @@ func (*RaceParticipants) GetNRankedSoloRaceResults(userID int, n int) ([]RaceRes
 			AND races.finished = 1
 			AND races.ranked = 1
 			AND races.solo = 1
-			AND races.datetime_finished > "`+SoloSeasonStartDatetime+`"
-			AND races.datetime_finished < "`+SoloSeasonEndDatetime+`"
+			AND races.datetime_finished > `+seasonStart+`
+			AND races.datetime_finished < `+seasonEnd+`
+			AND races.datetime_finished <= (SELECT datetime_finished FROM races WHERE id = ?)
+			AND races.id NOT IN (SELECT race_id FROM race_flags WHERE hold_leaderboard = 1 AND resolved = 0)
 		ORDER BY races.datetime_finished DESC
 		LIMIT ?
-	`, userID, n); err != nil {
+	`, userID, raceID, n); err != nil {
 		return timeList, err
 	} else {
 		rows = v
@@ -154,6 +168,65 @@ func (*RaceParticipants) GetNRankedSoloRaceResults(userID int, n int) ([]RaceRes
 	return timeList, nil
 }
 
+type RaceParticipantPlace struct {
+	UserID   int
+	Username string
+	Place    int // -1 is quit, -2 is disqualified
+}
+
+// Get the place of everyone in a race
+// Used in the "websocketAdminDisqualifyFinished()" function
+func (*RaceParticipants) GetPlaces(raceID int) ([]RaceParticipantPlace, error) {
+	places := make([]RaceParticipantPlace, 0)
+
+	var rows *sql.Rows
+	if v, err := db.Query(`
+		SELECT users.id, users.username, race_participants.place
+		FROM race_participants
+			JOIN users ON race_participants.user_id = users.id
+		WHERE race_participants.race_id = ?
+	`, raceID); err != nil {
+		return places, err
+	} else {
+		rows = v
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var place RaceParticipantPlace
+		if err := rows.Scan(&place.UserID, &place.Username, &place.Place); err != nil {
+			return places, err
+		}
+		places = append(places, place)
+	}
+
+	if err := rows.Err(); err != nil {
+		return places, err
+	}
+
+	return places, nil
+}
+
+func (*RaceParticipants) SetPlace(raceID int, userID int, place int) error {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		UPDATE race_participants
+		SET place = ?
+		WHERE race_id = ? AND user_id = ?
+	`); err != nil {
+		return err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.Exec(place, raceID, userID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 /*
 // Used in ?
 func (*RaceParticipants) SetComment(userID int, raceID int, comment string) error {
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"database/sql"
+)
+
+/*
+	These are more functions for querying the "race_participants" table,
+	but these functions are only used in "headToHead.go"
+*/
+
+// One finished multiplayer race that two people were both in
+type HeadToHeadRace struct {
+	RaceID   int
+	Format   string
+	Place1   int   // -1 is quit, -2 is disqualified
+	Place2   int   // -1 is quit, -2 is disqualified
+	RunTime1 int64 // In milliseconds
+	RunTime2 int64 // In milliseconds
+	Team1    int   // 0 for races without teams
+	Team2    int   // 0 for races without teams
+}
+
+// Get every finished multiplayer race that both users were in, oldest first
+func (*RaceParticipants) GetHeadToHead(userID1 int, userID2 int) ([]HeadToHeadRace, error) {
+	races := make([]HeadToHeadRace, 0)
+
+	var rows *sql.Rows
+	if v, err := db.Query(`
+		SELECT
+			races.id,
+			races.format,
+			rp1.place,
+			rp2.place,
+			rp1.run_time,
+			rp2.run_time,
+			rp1.team,
+			rp2.team
+		FROM race_participants rp1
+			JOIN race_participants rp2 ON rp2.race_id = rp1.race_id
+			JOIN races ON races.id = rp1.race_id
+		WHERE
+			rp1.user_id = ?
+			AND rp2.user_id = ?
+			AND races.finished = 1
+			AND races.solo = 0
+		ORDER BY races.datetime_finished, races.id
+	`, userID1, userID2); err != nil {
+		return races, err
+	} else {
+		rows = v
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var race HeadToHeadRace
+		if err := rows.Scan(
+			&race.RaceID,
+			&race.Format,
+			&race.Place1,
+			&race.Place2,
+			&race.RunTime1,
+			&race.RunTime2,
+			&race.Team1,
+			&race.Team2,
+		); err != nil {
+			return races, err
+		}
+		races = append(races, race)
+	}
+
+	if err := rows.Err(); err != nil {
+		return races, err
+	}
+
+	return races, nil
+}
@@ -19,6 +19,7 @@ type Race struct {
 	Difficulty    string
 	StartingBuild int
 	Seed          string
+	TeamSize      int
 	Captain       string
 	/* This is stored in the database as a user_id reference, but we convert it during the SELECT */
 	DatetimeCreated  int64
@@ -87,6 +88,7 @@ func (*Races) Finish(race *Race) error {
 			difficulty = ?,
 			starting_build = ?,
 			seed = ?,
+			team_size = ?,
 			captain = (SELECT id FROM users where username = ?),
 			datetime_started = FROM_UNIXTIME(? / 1000),
 			datetime_finished = NOW()
@@ -119,6 +121,7 @@ func (*Races) Finish(race *Race) error {
 		race.Difficulty,
 		race.StartingBuild,
 		race.Seed,
+		race.TeamSize,
 		race.Captain,
 		race.DatetimeStarted,
 		race.ID,
@@ -129,7 +132,44 @@ func (*Races) Finish(race *Race) error {
 	return nil
 }
 
+// Get the ruleset of a race that has already finished
+// (the second return value is false if the race does not exist or is not finished)
+func (*Races) GetFinished(raceID int) (Race, bool, error) {
+	race := Race{
+		ID: raceID,
+	}
+	if err := db.QueryRow(`
+		SELECT
+			name,
+			ranked,
+			solo,
+			format,
+			player_type,
+			goal,
+			team_size,
+			UNIX_TIMESTAMP(datetime_started) * 1000
+		FROM races
+		WHERE id = ? AND finished = 1
+	`, raceID).Scan(
+		&race.Name,
+		&race.Ranked,
+		&race.Solo,
+		&race.Format,
+		&race.Character,
+		&race.Goal,
+		&race.TeamSize,
+		&race.DatetimeStarted,
+	); err == sql.ErrNoRows {
+		return race, false, nil
+	} else if err != nil {
+		return race, false, err
+	}
+
+	return race, true, nil
+}
+
 // Clean up any unfinished races from the database
+// (races that have a checkpoint are left alone so that they can be restored)
 func (*Races) Cleanup() ([]int, error) {
 	leftoverRaces := make([]int, 0)
 
@@ -137,7 +177,9 @@ func (*Races) Cleanup() ([]int, error) {
 	if v, err := db.Query(`
 		SELECT id
 		FROM races
-		WHERE finished = 0
+		WHERE
+			finished = 0
+			AND id NOT IN (SELECT race_id FROM race_checkpoints)
 		ORDER BY id
 	`); err != nil {
 		return leftoverRaces, err
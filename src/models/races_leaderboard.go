@@ -10,6 +10,8 @@ import (
 */
 
 func (*Races) GetAllRacesForLeaderboard(format string) ([]RaceHistory, error) {
+	seasonStart, seasonEnd := getRankedSoloSeason()
+
 	allRaces := make([]RaceHistory, 0)
 
 	var SQLString string
@@ -23,15 +25,17 @@ func (*Races) GetAllRacesForLeaderboard(format string) ([]RaceHistory, error) {
 				finished = 1
 				AND ranked = 1
 				AND solo = 1
-				AND datetime_finished > "` + SoloSeasonStartDatetime + `"
-				AND datetime_finished < "` + SoloSeasonEndDatetime + `"
+				AND datetime_finished > ` + seasonStart + `
+				AND datetime_finished < ` + seasonEnd + `
+				AND id NOT IN (SELECT race_id FROM race_flags WHERE hold_leaderboard = 1 AND resolved = 0)
 			ORDER BY
 				id
 		`
 	} else {
 		SQLString = `
 			SELECT
-				id
+				id,
+				team_size
 			FROM
 				races
 			WHERE
@@ -39,6 +43,7 @@ func (*Races) GetAllRacesForLeaderboard(format string) ([]RaceHistory, error) {
 				AND finished = 1
 				AND solo = 0
 				AND datetime_finished > "` + RepentanceReleasedDatetime + `"
+				AND id NOT IN (SELECT race_id FROM race_flags WHERE hold_leaderboard = 1 AND resolved = 0)
 			ORDER BY
 				id
 		`
@@ -56,8 +61,14 @@ func (*Races) GetAllRacesForLeaderboard(format string) ([]RaceHistory, error) {
 		racers := make([]RaceHistoryParticipants, 0)
 
 		var race RaceHistory
-		if err := rows.Scan(&race.RaceID); err != nil {
-			return allRaces, err
+		if format == "ranked_solo" {
+			if err := rows.Scan(&race.RaceID); err != nil {
+				return allRaces, err
+			}
+		} else {
+			if err := rows.Scan(&race.RaceID, &race.RaceTeamSize); err != nil {
+				return allRaces, err
+			}
 		}
 		race.RaceParticipants = nil
 
@@ -66,7 +77,8 @@ func (*Races) GetAllRacesForLeaderboard(format string) ([]RaceHistory, error) {
 			SELECT
 				users.id,
 				users.username,
-				race_participants.place
+				race_participants.place,
+				race_participants.team
 			FROM
 				race_participants
 			JOIN
@@ -86,6 +98,7 @@ func (*Races) GetAllRacesForLeaderboard(format string) ([]RaceHistory, error) {
 				&racer.ID,
 				&racer.RacerName,
 				&racer.RacerPlace,
+				&racer.RacerTeam,
 			); err != nil {
 				return allRaces, err
 			}
@@ -108,6 +121,8 @@ func (*Races) GetAllRacesForLeaderboard(format string) ([]RaceHistory, error) {
 }
 
 func (*Races) GetRankedSoloRacesForUser(format string, userID int) ([]RaceHistory, error) {
+	seasonStart, seasonEnd := getRankedSoloSeason()
+
 	allRaces := make([]RaceHistory, 0)
 
 	var rows *sql.Rows
@@ -124,8 +139,9 @@ func (*Races) GetRankedSoloRacesForUser(format string, userID int) ([]RaceHistor
 			races.finished = 1
 			AND races.ranked = 1
 			AND races.solo = 1
-			AND races.datetime_finished > "`+SoloSeasonStartDatetime+`"
-			AND races.datetime_finished < "`+SoloSeasonEndDatetime+`"
+			AND races.datetime_finished > `+seasonStart+`
+			AND races.datetime_finished < `+seasonEnd+`
+			AND races.id NOT IN (SELECT race_id FROM race_flags WHERE hold_leaderboard = 1 AND resolved = 0)
 			AND users.id = ?
 		ORDER BY
 			id
@@ -0,0 +1,194 @@
+package models
+
+import (
+	"database/sql"
+)
+
+/*
+	These are more functions for querying the "races" table,
+	but these functions are only used for the race timeline API
+*/
+
+type RaceTimelineParticipant struct {
+	Name    string
+	Place   int
+	RunTime int64
+	Team    int
+}
+
+// RaceTimelineRow is one item, room, or split from one of the "race_participant_" tables
+type RaceTimelineRow struct {
+	Name      string
+	Type      string // "item", "room", or "floor"
+	ItemID    int    // Only for items
+	RoomID    string // Only for rooms
+	FloorNum  int
+	StageType int
+	Datetime  int64 // Epoch timestamp in milliseconds
+}
+
+func (*Races) GetTimelineParticipants(raceID int) ([]RaceTimelineParticipant, error) {
+	participants := make([]RaceTimelineParticipant, 0)
+
+	var rows *sql.Rows
+	if v, err := db.Query(`
+		SELECT
+			u.username,
+			rp.place,
+			rp.run_time,
+			rp.team
+		FROM
+			race_participants rp
+		JOIN
+			users u
+				ON u.id = rp.user_id
+		WHERE
+			rp.race_id = ?
+		ORDER BY
+			CASE WHEN rp.place < 0 THEN 1 ELSE 0 END,
+			rp.place,
+			rp.run_time
+	`, raceID); err != nil {
+		return participants, err
+	} else {
+		rows = v
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var participant RaceTimelineParticipant
+		if err := rows.Scan(
+			&participant.Name,
+			&participant.Place,
+			&participant.RunTime,
+			&participant.Team,
+		); err != nil {
+			return participants, err
+		}
+		participants = append(participants, participant)
+	}
+
+	if err := rows.Err(); err != nil {
+		return participants, err
+	}
+
+	return participants, nil
+}
+
+// Get every item, room, and split for every participant in the race, in chronological order
+// (the timestamps only have a precision of one second, so events in the same second are
+// ordered by floor first, then rooms, then items)
+func (*Races) GetTimelineRows(raceID int) ([]RaceTimelineRow, error) {
+	timelineRows := make([]RaceTimelineRow, 0)
+
+	var rows *sql.Rows
+	if v, err := db.Query(`
+		SELECT
+			name,
+			type,
+			item_id,
+			room_id,
+			floor_num,
+			stage_type,
+			datetime
+		FROM (
+			SELECT
+				u.username AS name,
+				"floor" AS type,
+				0 AS type_order,
+				0 AS item_id,
+				"" AS room_id,
+				rps.floor_num,
+				rps.stage_type,
+				UNIX_TIMESTAMP(rps.datetime_arrived) * 1000 AS datetime,
+				rps.id
+			FROM
+				race_participant_splits rps
+			JOIN
+				race_participants rp
+					ON rp.id = rps.race_participant_id
+			JOIN
+				users u
+					ON u.id = rp.user_id
+			WHERE
+				rp.race_id = ?
+
+			UNION ALL
+
+			SELECT
+				u.username,
+				"room",
+				1,
+				0,
+				rpr.room_id,
+				rpr.floor_num,
+				rpr.stage_type,
+				UNIX_TIMESTAMP(rpr.datetime_arrived) * 1000,
+				rpr.id
+			FROM
+				race_participant_rooms rpr
+			JOIN
+				race_participants rp
+					ON rp.id = rpr.race_participant_id
+			JOIN
+				users u
+					ON u.id = rp.user_id
+			WHERE
+				rp.race_id = ?
+
+			UNION ALL
+
+			SELECT
+				u.username,
+				"item",
+				2,
+				rpi.item_id,
+				"",
+				rpi.floor_num,
+				rpi.stage_type,
+				UNIX_TIMESTAMP(rpi.datetime_acquired) * 1000,
+				rpi.id
+			FROM
+				race_participant_items rpi
+			JOIN
+				race_participants rp
+					ON rp.id = rpi.race_participant_id
+			JOIN
+				users u
+					ON u.id = rp.user_id
+			WHERE
+				rp.race_id = ?
+		) AS timeline
+		ORDER BY
+			datetime,
+			type_order,
+			id
+	`, raceID, raceID, raceID); err != nil {
+		return timelineRows, err
+	} else {
+		rows = v
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var timelineRow RaceTimelineRow
+		if err := rows.Scan(
+			&timelineRow.Name,
+			&timelineRow.Type,
+			&timelineRow.ItemID,
+			&timelineRow.RoomID,
+			&timelineRow.FloorNum,
+			&timelineRow.StageType,
+			&timelineRow.Datetime,
+		); err != nil {
+			return timelineRows, err
+		}
+		timelineRows = append(timelineRows, timelineRow)
+	}
+
+	if err := rows.Err(); err != nil {
+		return timelineRows, err
+	}
+
+	return timelineRows, nil
+}
@@ -19,6 +19,7 @@ type RaceHistory struct {
 	RaceGoal         sql.NullString
 	RaceDateStart    sql.NullTime
 	RaceDateFinished sql.NullTime
+	RaceTeamSize     sql.NullInt64
 	RaceParticipants []RaceHistoryParticipants
 }
 
@@ -35,6 +36,15 @@ type RaceHistoryParticipants struct {
 	RacerStartingBuildID   int
 	RacerStartingBuildName string
 	RacerComment           sql.NullString
+	RacerTeam              sql.NullInt64
+	RacerNumResets         sql.NullInt64 // Only filled in by "GetRaceHistory"
+}
+
+// RaceHistorySplit gets the time that a racer arrived on a floor
+type RaceHistorySplit struct {
+	FloorNum  sql.NullInt64
+	StageType sql.NullInt64
+	SplitTime sql.NullInt64 // In milliseconds since the race started
 }
 
 // GetRacesHistory gets all data for all races
@@ -221,7 +231,8 @@ func (*Races) GetRaceHistory(raceID int) (RaceHistory, error) {
 				rp.run_time,
 				rp.starting_item,
 				r.starting_build,
-				rp.comment
+				rp.comment,
+				rp.num_resets
 			FROM
 				race_participants rp
 			LEFT JOIN
@@ -253,6 +264,7 @@ func (*Races) GetRaceHistory(raceID int) (RaceHistory, error) {
 				&racer.RacerStartingItem,
 				&racer.RacerStartingBuild,
 				&racer.RacerComment,
+				&racer.RacerNumResets,
 			); err != nil {
 				return race, err
 			}
@@ -273,8 +285,62 @@ func (*Races) GetRaceHistory(raceID int) (RaceHistory, error) {
 	return race, nil
 }
 
+// GetRaceSplitsHistory gets the floor splits for every racer in a single race
+// The map is indexed by racer name
+func (*Races) GetRaceSplitsHistory(raceID int) (map[string][]RaceHistorySplit, error) {
+	splits := make(map[string][]RaceHistorySplit)
+
+	var rows *sql.Rows
+	if v, err := db.Query(`
+		SELECT
+			u.username,
+			rps.floor_num,
+			rps.stage_type,
+			rps.split_time
+		FROM
+			race_participant_splits rps
+		JOIN
+			race_participants rp
+				ON rp.id = rps.race_participant_id
+		JOIN
+			users u
+				ON u.id = rp.user_id
+		WHERE
+			rp.race_id = ?
+		ORDER BY
+			rps.split_time
+	`, raceID); err != nil {
+		return splits, err
+	} else {
+		rows = v
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var username string
+		var split RaceHistorySplit
+		if err := rows.Scan(
+			&username,
+			&split.FloorNum,
+			&split.StageType,
+			&split.SplitTime,
+		); err != nil {
+			return splits, err
+		}
+		splits[username] = append(splits[username], split)
+	}
+
+	if err := rows.Err(); err != nil {
+		return splits, err
+	}
+
+	return splits, nil
+}
+
 // GetRaceProfileHistory gets the race data for the profile page
 func (*Races) GetSoloRankedRaceProfileHistory(user string, racesPerPage int) ([]RaceHistory, error) {
+	seasonStart, seasonEnd := getRankedSoloSeason()
+
 	raceHistory := make([]RaceHistory, 0)
 
 	var rows *sql.Rows
@@ -300,8 +366,8 @@ func (*Races) GetSoloRankedRaceProfileHistory(user string, racesPerPage int) ([]
 			AND r.ranked = 1
 			AND r.solo = 1
 			AND u.username = ?
-			AND r.datetime_finished > "`+SoloSeasonStartDatetime+`"
-			AND r.datetime_finished < "`+SoloSeasonEndDatetime+`"
+			AND r.datetime_finished > `+seasonStart+`
+			AND r.datetime_finished < `+seasonEnd+`
 	GROUP BY
 			id
 		ORDER BY
@@ -0,0 +1,192 @@
+package models
+
+import (
+	"database/sql"
+)
+
+type RatingHistory struct{}
+
+// One rating change from one race
+type RatingHistoryEntry struct {
+	UserID int
+	RaceID int
+	Rating float64
+	Mu     sql.NullFloat64 // Not used for ranked solo
+	Sigma  sql.NullFloat64 // Not used for ranked solo
+
+	// How much the rating moved in this race (not used for ranked solo)
+	RatingChange sql.NullFloat64
+}
+
+type RatingHistoryPoint struct {
+	RaceID          int      `json:"raceID"` // nolint:tagliatelle
+	Format          string   `json:"format"`
+	Rating          float64  `json:"rating"`
+	Mu              *float64 `json:"mu,omitempty"`
+	Sigma           *float64 `json:"sigma,omitempty"`
+	RatingChange    *float64 `json:"ratingChange,omitempty"`
+	DatetimeCreated int64    `json:"datetimeCreated"` // Epoch timestamp in milliseconds
+}
+
+// The timestamp is taken from when the race finished,
+// so that recalculating a leaderboard produces the same history
+// If the user already has an entry for this race, it is overwritten
+func (rh *RatingHistory) Insert(format string, entry RatingHistoryEntry) error {
+	return rh.InsertMany(format, []RatingHistoryEntry{entry})
+}
+
+// Used when recalculating a leaderboard
+func (*RatingHistory) InsertMany(format string, entries []RatingHistoryEntry) error {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		INSERT INTO rating_history (
+			user_id,
+			race_id,
+			format,
+			rating,
+			mu,
+			sigma,
+			rating_change,
+			datetime_created
+		)
+		SELECT
+			?,
+			id,
+			?,
+			?,
+			?,
+			?,
+			?,
+			IFNULL(datetime_finished, NOW())
+		FROM races
+		WHERE id = ?
+		ON DUPLICATE KEY UPDATE
+			rating = VALUES(rating),
+			mu = VALUES(mu),
+			sigma = VALUES(sigma),
+			rating_change = VALUES(rating_change)
+	`); err != nil {
+		return err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	for _, entry := range entries {
+		if _, err := stmt.Exec(
+			entry.UserID,
+			format,
+			entry.Rating,
+			entry.Mu,
+			entry.Sigma,
+			entry.RatingChange,
+			entry.RaceID,
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Used when recalculating a leaderboard from scratch
+func (*RatingHistory) DeleteFormat(format string) error {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		DELETE FROM rating_history
+		WHERE format = ?
+	`); err != nil {
+		return err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.Exec(format); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Used when recalculating the ranked solo leaderboard for a specific user
+func (*RatingHistory) DeleteUserFormat(userID int, format string) error {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		DELETE FROM rating_history
+		WHERE user_id = ? AND format = ?
+	`); err != nil {
+		return err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.Exec(userID, format); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Get every rating change for a user, oldest first
+// (if the format is blank, every format is returned)
+func (*RatingHistory) GetUser(userID int, format string) ([]RatingHistoryPoint, error) {
+	points := make([]RatingHistoryPoint, 0)
+
+	var rows *sql.Rows
+	if v, err := db.Query(`
+		SELECT
+			race_id,
+			format,
+			rating,
+			mu,
+			sigma,
+			rating_change,
+			UNIX_TIMESTAMP(datetime_created) * 1000
+		FROM rating_history
+		WHERE
+			user_id = ?
+			AND (? = "" OR format = ?)
+		ORDER BY datetime_created, race_id
+	`, userID, format, format); err != nil {
+		return points, err
+	} else {
+		rows = v
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var point RatingHistoryPoint
+		var mu sql.NullFloat64
+		var sigma sql.NullFloat64
+		var ratingChange sql.NullFloat64
+		if err := rows.Scan(
+			&point.RaceID,
+			&point.Format,
+			&point.Rating,
+			&mu,
+			&sigma,
+			&ratingChange,
+			&point.DatetimeCreated,
+		); err != nil {
+			return points, err
+		}
+		if mu.Valid {
+			point.Mu = &mu.Float64
+		}
+		if sigma.Valid {
+			point.Sigma = &sigma.Float64
+		}
+		if ratingChange.Valid {
+			point.RatingChange = &ratingChange.Float64
+		}
+		points = append(points, point)
+	}
+
+	if err := rows.Err(); err != nil {
+		return points, err
+	}
+
+	return points, nil
+}
@@ -0,0 +1,197 @@
+package models
+
+import (
+	"database/sql"
+	"strconv"
+	"sync"
+)
+
+type Seasons struct{}
+
+type Season struct {
+	ID            int
+	Name          string
+	Category      string
+	DatetimeStart int64 // Epoch timestamp in milliseconds (0 if there is no recorded start)
+	DatetimeEnd   int64 // Epoch timestamp in milliseconds (0 if there is no planned end)
+	Archived      bool
+}
+
+const (
+	// Used when there has never been a ranked solo season, so that no races count
+	seasonNoneDatetime = "FROM_UNIXTIME(1)"
+
+	// Used when the season does not have a planned end
+	seasonNoEndDatetime = "'9999-12-31 00:00:00'"
+)
+
+var (
+	// The period of time that counts for the ranked solo leaderboard
+	// (this is set by the season engine in "seasons.go")
+	rankedSoloSeasonStart = seasonNoneDatetime
+	rankedSoloSeasonEnd   = seasonNoneDatetime
+	rankedSoloSeasonMutex = sync.RWMutex{}
+)
+
+// Get every season, in the order that they should be displayed
+func (*Seasons) GetAll() ([]Season, error) {
+	seasons := make([]Season, 0)
+
+	var rows *sql.Rows
+	if v, err := db.Query(`
+		SELECT
+			id,
+			name,
+			category,
+			IFNULL(UNIX_TIMESTAMP(datetime_start) * 1000, 0),
+			IFNULL(UNIX_TIMESTAMP(datetime_end) * 1000, 0),
+			archived
+		FROM seasons
+		ORDER BY id
+	`); err != nil {
+		return seasons, err
+	} else {
+		rows = v
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var season Season
+		if err := rows.Scan(
+			&season.ID,
+			&season.Name,
+			&season.Category,
+			&season.DatetimeStart,
+			&season.DatetimeEnd,
+			&season.Archived,
+		); err != nil {
+			return seasons, err
+		}
+		seasons = append(seasons, season)
+	}
+
+	if err := rows.Err(); err != nil {
+		return seasons, err
+	}
+
+	return seasons, nil
+}
+
+// Open a new season that starts right now
+// (an end of 0 means that the season does not have a planned end)
+func (*Seasons) Insert(name string, category string, datetimeEnd int64) (int, error) {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		INSERT INTO seasons (
+			name,
+			category,
+			datetime_start,
+			datetime_end
+		)
+		VALUES (
+			?,
+			?,
+			NOW(),
+			IF(? = 0, NULL, FROM_UNIXTIME(? / 1000))
+		)
+	`); err != nil {
+		return 0, err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	var result sql.Result
+	if v, err := stmt.Exec(name, category, datetimeEnd, datetimeEnd); err != nil {
+		return 0, err
+	} else {
+		result = v
+	}
+
+	var seasonID int
+	if seasonID64, err := result.LastInsertId(); err != nil {
+		return 0, err
+	} else {
+		seasonID = int(seasonID64)
+	}
+
+	return seasonID, nil
+}
+
+// Copy the ranked solo leaderboard to the hall of fame, archive the season, and reset everyone's
+// ranked solo stats, all at once
+// (the entries from an earlier attempt are deleted first so that this can safely be retried)
+func (*Seasons) Close(seasonID int, entries []HallOfFameEntry) error {
+	var tx *sql.Tx
+	if v, err := db.Begin(); err != nil {
+		return err
+	} else {
+		tx = v
+	}
+	defer tx.Rollback() // nolint: errcheck
+
+	if _, err := tx.Exec(`
+		DELETE FROM hall_of_fame_entries
+		WHERE season_id = ?
+	`, seasonID); err != nil {
+		return err
+	}
+
+	if err := hallOfFameEntriesInsert(tx, seasonID, entries); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE seasons
+		SET archived = 1
+		WHERE id = ?
+	`, seasonID); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE users
+		SET
+			ranked_solo_adjusted_average = 0,
+			ranked_solo_real_average = 0,
+			ranked_solo_num_forfeits = 0,
+			ranked_solo_forfeit_penalty = 0,
+			ranked_solo_lowest_time = 0,
+			ranked_solo_num_races = 0,
+			ranked_solo_last_race = NULL,
+			ranked_solo_metadata = NULL
+	`); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Set the period of time that counts for the ranked solo leaderboard
+// (an end of 0 means that the season does not have a planned end)
+func SetRankedSoloSeason(datetimeStart int64, datetimeEnd int64) {
+	start := seasonGetDatetime(datetimeStart)
+	end := seasonNoEndDatetime
+	if datetimeEnd != 0 {
+		end = seasonGetDatetime(datetimeEnd)
+	}
+
+	rankedSoloSeasonMutex.Lock()
+	rankedSoloSeasonStart = start
+	rankedSoloSeasonEnd = end
+	rankedSoloSeasonMutex.Unlock()
+}
+
+// The returned strings are SQL expressions that are safe to put directly into a query
+func getRankedSoloSeason() (string, string) {
+	rankedSoloSeasonMutex.RLock()
+	defer rankedSoloSeasonMutex.RUnlock()
+
+	return rankedSoloSeasonStart, rankedSoloSeasonEnd
+}
+
+// The conversion is done by the database so that it uses the same time zone as the columns that
+// it is compared to
+func seasonGetDatetime(timestamp int64) string {
+	return "FROM_UNIXTIME(" + strconv.FormatInt(timestamp/1000, 10) + ")"
+}
@@ -0,0 +1,251 @@
+package models
+
+import (
+	"database/sql"
+)
+
+type Series struct{}
+
+// This mirrors the "series" table row
+// (it contains a subset of the information in the non-models Series struct)
+type SeriesRow struct {
+	ID               int
+	Name             string
+	BestOf           int
+	Ruleset          string // JSON-encoded
+	RotateCharacters bool
+	RotateBuilds     bool
+	Captain          string
+	/* This is stored in the database as a user_id reference, but we convert it during the SELECT */
+	NumRaces int
+	Scores   map[string]int // Indexed by username
+	UserIDs  map[string]int // Indexed by username
+}
+
+func (*Series) Insert(
+	name string,
+	bestOf int,
+	ruleset string,
+	rotateCharacters bool,
+	rotateBuilds bool,
+	captainID int,
+) (int, error) {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		INSERT INTO series (
+			name,
+			best_of,
+			ruleset,
+			rotate_characters,
+			rotate_builds,
+			captain
+		)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`); err != nil {
+		return 0, err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	var result sql.Result
+	if v, err := stmt.Exec(name, bestOf, ruleset, rotateCharacters, rotateBuilds, captainID); err != nil {
+		return 0, err
+	} else {
+		result = v
+	}
+
+	var seriesID int
+	if seriesID64, err := result.LastInsertId(); err != nil {
+		return 0, err
+	} else {
+		seriesID = int(seriesID64)
+	}
+
+	return seriesID, nil
+}
+
+// Remember the ruleset of the most recent race so that the next race can be created after a restart
+func (*Series) SetRuleset(seriesID int, ruleset string) error {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		UPDATE series
+		SET ruleset = ?
+		WHERE id = ?
+	`); err != nil {
+		return err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.Exec(ruleset, seriesID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (*Series) Finish(seriesID int, winnerID int) error {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		UPDATE series
+		SET
+			finished = 1,
+			winner = ?,
+			datetime_finished = NOW()
+		WHERE id = ?
+	`); err != nil {
+		return err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.Exec(winnerID, seriesID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Used when the first race of a series could not be created
+func (*Series) Delete(seriesID int) error {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		DELETE FROM series
+		WHERE id = ?
+	`); err != nil {
+		return err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.Exec(seriesID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Used when everyone leaves a series before it is won
+// (it is marked as finished with no winner so that the races that were already played are kept)
+func (*Series) Abandon(seriesID int) error {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		UPDATE series
+		SET
+			finished = 1,
+			winner = NULL,
+			datetime_finished = NOW()
+		WHERE id = ?
+	`); err != nil {
+		return err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.Exec(seriesID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Get every series that is still being played
+// Used in the "seriesRestoreAll()" function
+func (*Series) GetAllUnfinished() ([]SeriesRow, error) {
+	allSeries := make([]SeriesRow, 0)
+
+	var rows *sql.Rows
+	if v, err := db.Query(`
+		SELECT
+			series.id,
+			series.name,
+			series.best_of,
+			series.ruleset,
+			series.rotate_characters,
+			series.rotate_builds,
+			users.username,
+			(SELECT COUNT(id) FROM series_races WHERE series_id = series.id)
+		FROM
+			series
+		JOIN
+			users ON users.id = series.captain
+		WHERE
+			series.finished = 0
+		ORDER BY
+			series.id
+	`); err != nil {
+		return allSeries, err
+	} else {
+		rows = v
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var series SeriesRow
+		if err := rows.Scan(
+			&series.ID,
+			&series.Name,
+			&series.BestOf,
+			&series.Ruleset,
+			&series.RotateCharacters,
+			&series.RotateBuilds,
+			&series.Captain,
+			&series.NumRaces,
+		); err != nil {
+			return allSeries, err
+		}
+		allSeries = append(allSeries, series)
+	}
+
+	if err := rows.Err(); err != nil {
+		return allSeries, err
+	}
+
+	// Get the entrants of each series
+	for i := range allSeries {
+		allSeries[i].Scores = make(map[string]int)
+		allSeries[i].UserIDs = make(map[string]int)
+
+		var rows2 *sql.Rows
+		if v, err := db.Query(`
+			SELECT
+				users.id,
+				users.username,
+				series_participants.score
+			FROM
+				series_participants
+			JOIN
+				users ON users.id = series_participants.user_id
+			WHERE
+				series_participants.series_id = ?
+		`, allSeries[i].ID); err != nil {
+			return allSeries, err
+		} else {
+			rows2 = v
+		}
+
+		for rows2.Next() {
+			var userID, score int
+			var username string
+			if err := rows2.Scan(&userID, &username, &score); err != nil {
+				rows2.Close()
+				return allSeries, err
+			}
+			allSeries[i].UserIDs[username] = userID
+			allSeries[i].Scores[username] = score
+		}
+
+		err := rows2.Err()
+		rows2.Close()
+		if err != nil {
+			return allSeries, err
+		}
+	}
+
+	return allSeries, nil
+}
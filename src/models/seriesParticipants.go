@@ -0,0 +1,47 @@
+package models
+
+import (
+	"database/sql"
+)
+
+type SeriesParticipants struct{}
+
+func (*SeriesParticipants) Insert(seriesID int, userID int) error {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		INSERT INTO series_participants (series_id, user_id)
+		VALUES (?, ?)
+	`); err != nil {
+		return err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.Exec(seriesID, userID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Record that this user won a race in the series
+func (*SeriesParticipants) IncrementScore(seriesID int, userID int) error {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		UPDATE series_participants
+		SET score = score + 1
+		WHERE series_id = ? AND user_id = ?
+	`); err != nil {
+		return err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.Exec(seriesID, userID); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"database/sql"
+)
+
+type SeriesRaces struct{}
+
+func (*SeriesRaces) Insert(seriesID int, raceID int, raceNumber int) error {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(`
+		INSERT INTO series_races (series_id, race_id, race_number)
+		VALUES (?, ?, ?)
+	`); err != nil {
+		return err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.Exec(seriesID, raceID, raceNumber); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,209 @@
+package models
+
+import (
+	"database/sql"
+)
+
+/*
+	These are more functions for querying the "series" table,
+	but these functions are only used for the website
+*/
+
+// SeriesHistory gets the history of a best-of-N series
+type SeriesHistory struct {
+	SeriesID           sql.NullInt64
+	SeriesName         sql.NullString
+	SeriesBestOf       sql.NullInt64
+	SeriesFinished     sql.NullBool
+	SeriesWinner       sql.NullString
+	SeriesDateCreated  sql.NullTime
+	SeriesRaceNumber   int   // Only filled in by "GetSeriesHistoryForRace"
+	SeriesRaces        []int // The race IDs in the order that they were played
+	SeriesParticipants []SeriesHistoryParticipants
+}
+
+// SeriesHistoryParticipants gets the score for each racer in each series
+type SeriesHistoryParticipants struct {
+	RacerName  sql.NullString
+	RacerScore sql.NullInt64
+}
+
+// GetSeriesHistoryForRace gets the series that a race was a part of
+// (the second return value is false if the race was not part of a series)
+func (*Series) GetSeriesHistoryForRace(raceID int) (SeriesHistory, bool, error) {
+	var series SeriesHistory
+	if err := db.QueryRow(`
+		SELECT
+			s.id,
+			s.name,
+			s.best_of,
+			s.finished,
+			u.username,
+			s.datetime_created,
+			sr.race_number
+		FROM
+			series_races sr
+		JOIN
+			series s
+				ON s.id = sr.series_id
+		LEFT JOIN
+			users u
+				ON u.id = s.winner
+		WHERE
+			sr.race_id = ?
+	`, raceID).Scan(
+		&series.SeriesID,
+		&series.SeriesName,
+		&series.SeriesBestOf,
+		&series.SeriesFinished,
+		&series.SeriesWinner,
+		&series.SeriesDateCreated,
+		&series.SeriesRaceNumber,
+	); err == sql.ErrNoRows {
+		return series, false, nil
+	} else if err != nil {
+		return series, false, err
+	}
+
+	if err := getSeriesHistoryDetails(&series); err != nil {
+		return series, false, err
+	}
+
+	return series, true, nil
+}
+
+// GetSeriesProfileHistory gets the series data for the profile page
+func (*Series) GetSeriesProfileHistory(user string, seriesPerPage int) ([]SeriesHistory, error) {
+	seriesHistory := make([]SeriesHistory, 0)
+
+	var rows *sql.Rows
+	if v, err := db.Query(`
+		SELECT
+			s.id,
+			s.name,
+			s.best_of,
+			s.finished,
+			u2.username,
+			s.datetime_created
+		FROM
+			series s
+		JOIN
+			series_participants sp
+				ON sp.series_id = s.id
+		JOIN
+			users u
+				ON u.id = sp.user_id
+		LEFT JOIN
+			users u2
+				ON u2.id = s.winner
+		WHERE
+			u.username = ?
+		ORDER BY
+			s.datetime_created DESC
+		LIMIT
+			?
+	`, user, seriesPerPage); err != nil {
+		return seriesHistory, err
+	} else {
+		rows = v
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var series SeriesHistory
+		if err := rows.Scan(
+			&series.SeriesID,
+			&series.SeriesName,
+			&series.SeriesBestOf,
+			&series.SeriesFinished,
+			&series.SeriesWinner,
+			&series.SeriesDateCreated,
+		); err != nil {
+			return seriesHistory, err
+		}
+		seriesHistory = append(seriesHistory, series)
+	}
+
+	if err := rows.Err(); err != nil {
+		return seriesHistory, err
+	}
+
+	for i := range seriesHistory {
+		if err := getSeriesHistoryDetails(&seriesHistory[i]); err != nil {
+			return seriesHistory, err
+		}
+	}
+
+	return seriesHistory, nil
+}
+
+// Fill in the scores and the finished races for a series
+func getSeriesHistoryDetails(series *SeriesHistory) error {
+	series.SeriesParticipants = make([]SeriesHistoryParticipants, 0)
+	series.SeriesRaces = make([]int, 0)
+
+	var rows *sql.Rows
+	if v, err := db.Query(`
+		SELECT
+			u.username,
+			sp.score
+		FROM
+			series_participants sp
+		JOIN
+			users u
+				ON u.id = sp.user_id
+		WHERE
+			sp.series_id = ?
+		ORDER BY
+			sp.score DESC,
+			u.username
+	`, series.SeriesID); err != nil {
+		return err
+	} else {
+		rows = v
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var participant SeriesHistoryParticipants
+		if err := rows.Scan(&participant.RacerName, &participant.RacerScore); err != nil {
+			return err
+		}
+		series.SeriesParticipants = append(series.SeriesParticipants, participant)
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	var rows2 *sql.Rows
+	if v, err := db.Query(`
+		SELECT
+			sr.race_id
+		FROM
+			series_races sr
+		JOIN
+			races r
+				ON r.id = sr.race_id
+		WHERE
+			sr.series_id = ?
+			AND r.finished = 1
+		ORDER BY
+			sr.race_number
+	`, series.SeriesID); err != nil {
+		return err
+	} else {
+		rows2 = v
+	}
+	defer rows2.Close()
+
+	for rows2.Next() {
+		var raceID int
+		if err := rows2.Scan(&raceID); err != nil {
+			return err
+		}
+		series.SeriesRaces = append(series.SeriesRaces, raceID)
+	}
+
+	return rows2.Err()
+}
@@ -12,20 +12,9 @@ import (
 )
 
 const (
-	SoloSeason1StartDatetime = "2017-10-17 23:00:00"
-	SoloSeason1EndDatetime   = "2018-03-17 00:00:00"
-	SoloSeason2StartDatetime = "2018-03-18 23:00:00"
-	SoloSeason2EndDatetime   = "2018-10-26 00:00:00"
-
 	// This is not actually when the Repentance DLC was released,
 	// but rather when the Repentance version of Racing+ was released
 	RepentanceReleasedDatetime = "2021-05-21 00:00:00"
-
-	SoloSeason3StartDatetime = "2021-12-03 00:00:00"
-	SoloSeason3EndDatetime   = "2030-00-00 00:00:00"
-
-	SoloSeasonStartDatetime = SoloSeason3StartDatetime
-	SoloSeasonEndDatetime   = SoloSeason3EndDatetime
 )
 
 type StatsUnseeded struct {
@@ -38,13 +27,16 @@ type StatsUnseeded struct {
 	LastRace        sql.NullTime
 }
 
+// The mu and sigma are in the units of whichever rating engine the format uses
+// (for Glicko-2, they are the rating and the rating deviation)
 type StatsTrueSkill struct {
-	TrueSkill float64
-	Mu        float64
-	Sigma     float64
-	Change    float64
-	NumRaces  int
-	LastRace  sql.NullTime
+	TrueSkill  float64
+	Mu         float64
+	Sigma      float64
+	Volatility float64 // Only used by the Glicko-2 rating engine
+	Change     float64
+	NumRaces   int
+	LastRace   sql.NullTime
 }
 
 func (*Users) GetTrueSkill(userID int, format string) (StatsTrueSkill, error) {
@@ -55,6 +47,7 @@ func (*Users) GetTrueSkill(userID int, format string) (StatsTrueSkill, error) {
 			`+format+`_trueskill_mu,
 			`+format+`_trueskill_sigma,
 			`+format+`_trueskill_change,
+			`+format+`_trueskill_volatility,
 			`+format+`_num_races,
 			`+format+`_last_race
 		FROM
@@ -66,6 +59,7 @@ func (*Users) GetTrueSkill(userID int, format string) (StatsTrueSkill, error) {
 		&stats.Mu,
 		&stats.Sigma,
 		&stats.Change,
+		&stats.Volatility,
 		&stats.NumRaces,
 		&stats.LastRace,
 	); err != nil {
@@ -75,6 +69,64 @@ func (*Users) GetTrueSkill(userID int, format string) (StatsTrueSkill, error) {
 	return stats, nil
 }
 
+// Get the current stats and the usernames of everyone who has played at least one race of this
+// format (both are indexed by user ID)
+// Used in the "leaderboardCompareTrueSkill()" function
+func (*Users) GetAllTrueSkill(format string) (map[int]StatsTrueSkill, map[int]string, error) {
+	allStats := make(map[int]StatsTrueSkill)
+	usernames := make(map[int]string)
+
+	var rows *sql.Rows
+	if v, err := db.Query(`
+		SELECT
+			id,
+			username,
+			` + format + `_trueskill,
+			` + format + `_trueskill_mu,
+			` + format + `_trueskill_sigma,
+			` + format + `_trueskill_change,
+			` + format + `_trueskill_volatility,
+			` + format + `_num_races,
+			` + format + `_last_race
+		FROM
+			users
+		WHERE
+			` + format + `_num_races > 0
+	`); err != nil {
+		return allStats, usernames, err
+	} else {
+		rows = v
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var userID int
+		var username string
+		var stats StatsTrueSkill
+		if err := rows.Scan(
+			&userID,
+			&username,
+			&stats.TrueSkill,
+			&stats.Mu,
+			&stats.Sigma,
+			&stats.Change,
+			&stats.Volatility,
+			&stats.NumRaces,
+			&stats.LastRace,
+		); err != nil {
+			return allStats, usernames, err
+		}
+		allStats[userID] = stats
+		usernames[userID] = username
+	}
+
+	if err := rows.Err(); err != nil {
+		return allStats, usernames, err
+	}
+
+	return allStats, usernames, nil
+}
+
 func (*Users) SetTrueSkill(userID int, stats StatsTrueSkill, format string) error {
 	var stmt *sql.Stmt
 	if v, err := db.Prepare(`
@@ -84,6 +136,7 @@ func (*Users) SetTrueSkill(userID int, stats StatsTrueSkill, format string) erro
 			` + format + `_trueskill_mu = ?,
 			` + format + `_trueskill_sigma = ?,
 			` + format + `_trueskill_change = ?,
+			` + format + `_trueskill_volatility = ?,
 			` + format + `_num_races = ?,
 			` + format + `_last_race = NOW()
 		WHERE id = ?
@@ -99,6 +152,7 @@ func (*Users) SetTrueSkill(userID int, stats StatsTrueSkill, format string) erro
 		stats.Mu,
 		stats.Sigma,
 		stats.Change,
+		stats.Volatility,
 		stats.NumRaces,
 		userID,
 	); err != nil {
@@ -110,6 +164,25 @@ func (*Users) SetTrueSkill(userID int, stats StatsTrueSkill, format string) erro
 
 // Only used in the "leaderboardRecalculate" functions
 func (*Users) SetLastRace(format string) error {
+	var stmt *sql.Stmt
+	if v, err := db.Prepare(getSetLastRaceSQL(format)); err != nil {
+		return err
+	} else {
+		stmt = v
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.Exec(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Also used when a staged leaderboard is applied
+func getSetLastRaceSQL(format string) string {
+	seasonStart, seasonEnd := getRankedSoloSeason()
+
 	var SQLString string
 	if format == "ranked_solo" {
 		SQLString = `
@@ -123,8 +196,8 @@ func (*Users) SetLastRace(format string) error {
 					AND races.finished = 1
 					AND races.ranked = 1
 					AND races.solo = 1
-					AND races.datetime_finished > "` + SoloSeasonStartDatetime + `"
-					AND races.datetime_finished < "` + SoloSeasonEndDatetime + `"
+					AND races.datetime_finished > ` + seasonStart + `
+					AND races.datetime_finished < ` + seasonEnd + `
 				ORDER BY races.datetime_finished DESC
 				LIMIT 1
 			)
@@ -148,19 +221,7 @@ func (*Users) SetLastRace(format string) error {
 		`
 	}
 
-	var stmt *sql.Stmt
-	if v, err := db.Prepare(SQLString); err != nil {
-		return err
-	} else {
-		stmt = v
-	}
-	defer stmt.Close()
-
-	if _, err := stmt.Exec(); err != nil {
-		return err
-	}
-
-	return nil
+	return SQLString
 }
 
 func (*Users) ResetTrueSkill(format string) error {
@@ -168,9 +229,11 @@ func (*Users) ResetTrueSkill(format string) error {
 	if v, err := db.Prepare(`
 		UPDATE users
 		SET
-			` + format + `_trueskill = 25,
+			` + format + `_trueskill = 0,
+			` + format + `_trueskill_mu = 25,
 			` + format + `_trueskill_sigma = 8.333,
 			` + format + `_trueskill_change = 0,
+			` + format + `_trueskill_volatility = 0.06,
 			` + format + `_num_races = 0,
 			` + format + `_last_race = NULL
 	`); err != nil {
@@ -195,6 +258,8 @@ func (*Users) SetStatsRankedSolo(
 	lowestTime int64,
 	startingBuild int,
 ) error {
+	seasonStart, seasonEnd := getRankedSoloSeason()
+
 	adjustedAverage := realAverage + forfeitPenalty
 
 	// 1800000 is 30 minutes (1000 * 60 * 30)
@@ -213,8 +278,8 @@ func (*Users) SetStatsRankedSolo(
 					AND races.finished = 1
 					AND races.ranked = 1
 					AND races.solo = 1
-					AND races.datetime_finished > "` + SoloSeasonStartDatetime + `"
-					AND races.datetime_finished < "` + SoloSeasonEndDatetime + `"
+					AND races.datetime_finished > ` + seasonStart + `
+					AND races.datetime_finished < ` + seasonEnd + `
 			),
 			ranked_solo_num_forfeits = ?,
 			ranked_solo_forfeit_penalty = ?,
@@ -2,27 +2,49 @@ package server
 
 import (
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/Zamiell/isaac-racing-server/models"
 )
 
+const (
+	// How long everyone waits between the race starting and the run starting
+	RaceCountdown     = 10 * time.Second
+	RaceCountdownSolo = 3 * time.Second
+
+	// Someone who reconnects during the countdown is given a new countdown of this length,
+	// so their run can start up to this much later than everyone else's
+	RaceCountdownRejoin = 10 * time.Second
+)
+
 /*
 	Data structures
 */
 
 // Used to track the current races in memory
 type Race struct {
-	ID              int
-	Name            string
-	Status          RaceStatus
-	Ruleset         Ruleset
-	Captain         string
-	Password        string
-	SoundPlayed     bool
-	DatetimeCreated int64
-	DatetimeStarted int64
-	Racers          map[string]*Racer // Indexed by racer name
+	ID                int
+	Name              string
+	Status            RaceStatus
+	Ruleset           Ruleset
+	Captain           string
+	Password          string
+	SoundPlayed       bool
+	DatetimeCreated   int64
+	DatetimeStarted   int64
+	Racers            map[string]*Racer // Indexed by racer name
+	Spectators        map[string]bool   `json:"-"` // Indexed by username
+	SeriesID          int               // 0 if the race is not part of a series
+	Locked            bool              // Set by the captain to stop anyone else from joining
+	Kicked            map[string]bool   // Indexed by username; kicked users cannot rejoin
+	DatetimeScheduled int64             // 0 if the race starts when everyone is ready; epoch timestamp in milliseconds
+	ChallengeID       int               // 0 if the race is not a daily or weekly challenge
+
+	// Used by the race goroutine (in "raceActor.go")
+	commands chan func()
+	done     chan struct{}
+	stopOnce *sync.Once
 }
 
 type Ruleset struct {
@@ -36,14 +58,103 @@ type Ruleset struct {
 	StartingBuildRandom bool       `json:"startingBuildRandom"`
 	Seed                string     `json:"seed"`
 	Difficulty          string     `json:"difficulty"`
+	TeamSize            int        `json:"teamSize"` // 0 for races without teams
 }
 
 /*
 	Race object methods
 */
 
+// Get the names of everyone who should receive live updates about this race
+// (this is all of the racers and all of the spectators)
+func (race *Race) GetSubscribers() []string {
+	subscribers := make([]string, 0, len(race.Racers)+len(race.Spectators))
+	for racerName := range race.Racers {
+		subscribers = append(subscribers, racerName)
+	}
+	for spectatorName := range race.Spectators {
+		subscribers = append(subscribers, spectatorName)
+	}
+
+	return subscribers
+}
+
+// Disconnect all of the spectators from the spectator channel
+// (this has to happen before the race is deleted, since the "raceUnspectate" command ignores races
+// that do not exist)
+func (race *Race) RemoveSpectators() {
+	for spectatorName := range race.Spectators {
+		d := &IncomingWebsocketData{
+			Command: "race.RemoveSpectators",
+			Room:    "_race_" + strconv.Itoa(race.ID) + "_spectators",
+			v: &models.SessionValues{
+				Username: spectatorName,
+			},
+		}
+		websocketRoomLeaveSub(nil, d)
+	}
+	race.Spectators = make(map[string]bool)
+}
+
+// Get a subset of the race information to show in the lobby to a particular user
+// Used in the "raceCreated" and "raceList" commands
+func (race *Race) GetCreatedMessage(username string) *RaceCreatedMessage {
+	msg := &RaceCreatedMessage{
+		ID:                  race.ID,
+		Name:                race.Name,
+		Status:              race.Status,
+		Ruleset:             race.Ruleset,
+		Captain:             race.Captain,
+		IsPasswordProtected: len(race.Password) > 0,
+		IsLocked:            race.Locked,
+		DatetimeCreated:     race.DatetimeCreated,
+		DatetimeStarted:     race.DatetimeStarted,
+		DatetimeScheduled:   race.DatetimeScheduled,
+		ChallengeID:         race.ChallengeID,
+	}
+
+	// Nobody else should be able to see the seed of a challenge before they play it
+	if race.ChallengeID != 0 && username != race.Captain {
+		msg.Ruleset.Seed = "-"
+	}
+
+	racers := make([]string, 0)
+	teams := make(map[string]int)
+	for racerName, racer := range race.Racers {
+		racers = append(racers, racerName)
+		if race.IsTeamRace() {
+			teams[racerName] = racer.Team
+		}
+	}
+	msg.Racers = racers
+	msg.Teams = teams
+
+	if series, ok := seriesGet(race.SeriesID); ok {
+		msg.Series = series.GetMessage()
+	}
+
+	return msg
+}
+
+// Send everyone a notification that a new race has been created
+func (race *Race) SendCreated() {
+	msg := race.GetCreatedMessage("")
+	for _, s := range websocketGetAllSessions() {
+		if v, ok := websocketGetSessionValue(s, "username"); ok && v.(string) == race.Captain {
+			websocketEmit(s, "raceCreated", race.GetCreatedMessage(race.Captain))
+			continue
+		}
+		websocketEmit(s, "raceCreated", msg)
+	}
+}
+
 // Get the place that someone would be if they finished the race right now
 func (race *Race) GetCurrentPlace() int {
+	// In team races, every team that has finished takes up exactly one place
+	if race.IsTeamRace() {
+		return race.GetNumFinishedTeams() + 1
+	}
+
 	currentPlace := 0
 	for _, racer := range race.Racers {
 		if racer.Place > currentPlace {
@@ -55,6 +166,10 @@ func (race *Race) GetCurrentPlace() int {
 }
 
 func (race *Race) GetLastPlace() int {
+	if race.IsTeamRace() {
+		return race.GetNumActiveTeams()
+	}
+
 	lastPlace := len(race.Racers)
 	for _, racer := range race.Racers {
 		if racer.Status == RacerStatusQuit || racer.Status == RacerStatusDisqualified {
@@ -73,6 +188,17 @@ func (race *Race) CheckStart() {
 		return
 	}
 
+	// Check to see if the teams are incomplete
+	if race.IsTeamRace() && !race.TeamsAreFull() {
+		return
+	}
+
+	// Scheduled races start at the scheduled time, even if everyone is ready before then
+	// (in "raceSchedule.go")
+	if race.DatetimeScheduled != 0 {
+		return
+	}
+
 	// Check if everyone is ready
 	for _, racer := range race.Racers {
 		if racer.Status != RacerStatusReady {
@@ -86,7 +212,7 @@ func (race *Race) CheckStart() {
 func (race *Race) SetStatus(status RaceStatus) {
 	race.Status = status
 
-	for _, s := range websocketSessions {
+	for _, s := range websocketGetAllSessions() {
 		type RaceSetStatusMessage struct {
 			ID     int        `json:"id"`
 			Status RaceStatus `json:"status"`
@@ -98,13 +224,35 @@ func (race *Race) SetStatus(status RaceStatus) {
 	}
 }
 
+func (race *Race) SetCaptain(username string) {
+	race.Captain = username
+
+	for _, s := range websocketGetAllSessions() {
+		type RaceSetCaptainMessage struct {
+			ID      int    `json:"id"`
+			Captain string `json:"captain"`
+		}
+		websocketEmit(s, "raceSetCaptain", &RaceSetCaptainMessage{
+			ID:      race.ID,
+			Captain: race.Captain,
+		})
+	}
+}
+
 func (race *Race) SetRacerStatus(username string, status RacerStatus) {
 	racer := race.Racers[username]
 	racer.Status = status
+	race.SendRacerStatus(username, status)
+}
 
-	for racerName := range race.Racers {
+// Let everyone know about a racer's status without changing it
+// (this is also used to show that a racer is disconnected)
+func (race *Race) SendRacerStatus(username string, status RacerStatus) {
+	racer := race.Racers[username]
+
+	for _, subscriberName := range race.GetSubscribers() {
 		// Not all racers may be online during a race
-		if s, ok := websocketSessions[racerName]; ok {
+		if s, ok := websocketGetSession(subscriberName); ok {
 			type RacerSetStatusMessage struct {
 				ID      int         `json:"id"`
 				Name    string      `json:"name"`
@@ -150,9 +298,7 @@ func (race *Race) SetAllPlaceMid() {
 			continue
 		}
 
-		racerOnRepentanceFloor := isRepentanceStageType(racer.StageType)
-
-		if racer.FloorNum == 1 && racer.CharacterNum == 1 && !racerOnRepentanceFloor && !racer.BackwardsPath {
+		if racer.IsOnFirstFloor() {
 			// Mid-race places are not calculated until racers get to the second floor
 			racer.PlaceMid = lastPlace
 			if debug {
@@ -252,6 +398,11 @@ func (race *Race) SetAllPlaceMid() {
 		}
 	}
 
+	// Teammates share the place of whoever is furthest ahead on their team
+	if race.IsTeamRace() {
+		race.setAllPlaceMidTeams(currentPlace, lastPlace)
+	}
+
 	for _, racer := range race.Racers {
 		if racer.PlaceMidOld != racer.PlaceMid {
 			race.SendAllPlaceMid(racer.Name, racer.PlaceMid)
@@ -287,9 +438,9 @@ func isRepentanceStageType(stageType int) bool {
 }
 
 func (race *Race) SendAllPlaceMid(username string, placeMid int) {
-	for racerName := range race.Racers {
+	for _, subscriberName := range race.GetSubscribers() {
 		// Not all racers may be online during a race
-		if s, ok := websocketSessions[racerName]; ok {
+		if s, ok := websocketGetSession(subscriberName); ok {
 			type RacerSetPlaceMidMessage struct {
 				ID       int    `json:"id"`
 				Name     string `json:"name"`
@@ -304,29 +455,41 @@ func (race *Race) SendAllPlaceMid(username string, placeMid int) {
 	}
 }
 
-// Called from the "CheckStart" function
-func (race *Race) Start() {
-	var secondsToWait int
+func (race *Race) GetCountdown() time.Duration {
 	if race.Ruleset.Solo {
-		secondsToWait = 3
-	} else {
-		secondsToWait = 10
+		return RaceCountdownSolo
 	}
 
+	return RaceCountdown
+}
+
+// Called from the "CheckStart" function
+func (race *Race) Start() {
+	secondsToWait := int(race.GetCountdown().Seconds())
+
 	// Log the race starting
 	logger.Info("Race "+strconv.Itoa(race.ID)+" starting in", secondsToWait, "seconds.")
 
+	// The entrants of a series are locked in when its first race starts
+	if series, ok := seriesGet(race.SeriesID); ok {
+		series.SetEntrants(race)
+	}
+
 	// Change the status for this race to "starting"
-	race.SetStatus("starting")
+	race.SetStatus(RaceStatusStarting)
+	race.Checkpoint()
 
 	// Send everyone in the race a message specifying exactly when it will start
 	for racerName := range race.Racers {
 		// A racer might go offline the moment before it starts, so check just in case
-		if s, ok := websocketSessions[racerName]; ok {
+		if s, ok := websocketGetSession(racerName); ok {
 			websocketEmit(s, "raceStart", &RaceStartMessage{
 				ID:            race.ID,
 				SecondsToWait: secondsToWait,
 			})
+
+			// Get a fresh latency measurement for validating their run time later on
+			websocketSendPing(s)
 		}
 	}
 
@@ -339,18 +502,13 @@ func (race *Race) Start() {
 
 func (race *Race) Start2() {
 	// Sleep 3 or 10 seconds
-	var sleepSeconds int
-	if race.Ruleset.Solo {
-		sleepSeconds = 3
-	} else {
-		sleepSeconds = 10
-	}
-	time.Sleep(time.Duration(sleepSeconds) * time.Second)
+	time.Sleep(race.GetCountdown())
 
-	// Lock the command mutex for the duration of the function to ensure synchronous execution
-	commandMutex.Lock()
-	defer commandMutex.Unlock()
+	// The rest of the function must be executed by the race goroutine
+	race.Send(race.Start2Sub)
+}
 
+func (race *Race) Start2Sub() {
 	// Log the race starting
 	logger.Info("Race", race.ID, "started with", len(race.Racers), "participants.")
 
@@ -362,6 +520,7 @@ func (race *Race) Start2() {
 		racer.Status = RacerStatusRacing
 		racer.PlaceMid = numRacers // Make everyone tied for last place
 	}
+	race.Checkpoint()
 
 	// Return for now and do more things later on when it is time to check to see if the race has
 	// been going for too long
@@ -369,22 +528,17 @@ func (race *Race) Start2() {
 }
 
 func (race *Race) Start3() {
-	if race.Ruleset.Format == RaceFormatCustom {
-		// We need to make the timeout longer to accommodate multi-character speedrun races
-		time.Sleep(4 * time.Hour)
-	} else {
-		time.Sleep(30 * time.Minute)
-	}
-
-	// Lock the command mutex for the duration of the function to ensure synchronous execution
-	commandMutex.Lock()
-	defer commandMutex.Unlock()
+	// Races that were restored from a checkpoint have already been going for a while,
+	// so only sleep for the time that is left
+	elapsed := time.Duration(getTimestamp()-race.DatetimeStarted) * time.Millisecond
+	time.Sleep(race.GetTimeLimit() - elapsed)
+
+	// The rest of the function must be executed by the race goroutine
+	// (this does nothing if the race has already finished, since the goroutine will be stopped)
+	race.Send(race.Start3Sub)
+}
 
-	// Find out if the race is finished
-	// (we remove finished races from the "races" map)
-	if _, ok := races[race.ID]; !ok {
-		return
-	}
+func (race *Race) Start3Sub() {
 
 	// Force the remaining racers to quit
 	for _, racer := range race.Racers {
@@ -405,6 +559,15 @@ func (race *Race) Start3() {
 	}
 }
 
+func (race *Race) GetTimeLimit() time.Duration {
+	if race.Ruleset.Format == RaceFormatCustom {
+		// We need to make the timeout longer to accommodate multi-character speedrun races
+		return 4 * time.Hour
+	}
+
+	return 30 * time.Minute
+}
+
 func (race *Race) CheckFinish() {
 	for _, racer := range race.Racers {
 		if racer.Status == RacerStatusRacing {
@@ -420,10 +583,19 @@ func (race *Race) Finish() {
 	logger.Info("Race " + strconv.Itoa(race.ID) + " finished.")
 
 	// Let everyone know it ended
-	race.SetStatus("finished")
+	race.SetStatus(RaceStatusFinished)
+
+	// Remove it from the map and stop processing any more commands for it
+	// (the rest of this function will still be executed)
+	race.RemoveSpectators()
+	racesDelete(race.ID)
+	race.Stop()
+	for _, racer := range race.Racers {
+		racerRacesRemove(racer.Name, race.ID)
+	}
 
-	// Remove it from the map
-	delete(races, race.ID)
+	// Allow the racers to ask for a rematch for a little while (in "raceRematch.go")
+	race.AddRematchInfo()
 
 	// Write it to the database
 	databaseRace := &models.Race{
@@ -437,6 +609,7 @@ func (race *Race) Finish() {
 		Difficulty:      race.Ruleset.Difficulty,
 		StartingBuild:   race.Ruleset.StartingBuild,
 		Seed:            race.Ruleset.Seed,
+		TeamSize:        race.Ruleset.TeamSize,
 		Captain:         race.Captain,
 		DatetimeStarted: race.DatetimeStarted,
 	}
@@ -445,6 +618,11 @@ func (race *Race) Finish() {
 		return
 	}
 
+	// The race is now safely stored, so we no longer need to be able to restore it
+	if err := db.RaceCheckpoints.Delete(race.ID); err != nil {
+		logger.Error("Failed to delete the checkpoint for race #"+strconv.Itoa(race.ID)+":", err)
+	}
+
 	for _, racer := range race.Racers {
 		databaseRacer := &models.Racer{
 			ID:               racer.ID,
@@ -455,6 +633,8 @@ func (race *Race) Finish() {
 			DatetimeFinished: racer.DatetimeFinished,
 			RunTime:          racer.RunTime,
 			Comment:          racer.Comment,
+			Team:             racer.Team,
+			NumResets:        racer.NumResets,
 		}
 		if err := db.RaceParticipants.Insert(race.ID, databaseRacer); err != nil {
 			logger.Error("Failed to write the RaceParticipants row for \""+race.Name+"\" to the database:", err)
@@ -488,9 +668,20 @@ func (race *Race) Finish() {
 				return
 			}
 		}
+
+		if !race.WriteSplits(racer) {
+			return
+		}
 	}
 
-	if race.Ruleset.Solo {
+	// This runs on the goroutine for the race, so it can happen at the same time as another race
+	// finishing or a leaderboard being recalculated
+	leaderboardWriteMutex.Lock()
+	if race.IsLeaderboardHeld() {
+		// An admin has to look at this race before it counts
+		// (resolving the flag will recalculate the leaderboard)
+		logger.Info("Race " + strconv.Itoa(race.ID) + " was flagged, so it will not count for the leaderboards until it is reviewed.")
+	} else if race.Ruleset.Solo {
 		if race.Ruleset.Ranked {
 			leaderboardUpdateRankedSolo(race)
 		}
@@ -502,4 +693,9 @@ func (race *Race) Finish() {
 			leaderboardUpdateTrueSkill(race)
 		}
 	}
+	leaderboardWriteMutex.Unlock()
+
+	if race.SeriesID != 0 {
+		seriesRaceFinished(race)
+	}
 }
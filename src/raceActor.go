@@ -0,0 +1,176 @@
+package server
+
+import (
+	"sort"
+	"sync"
+)
+
+/*
+	Every race owns a goroutine that executes all of the commands for that race one at a time
+	(this means that a slow database query in one race does not block any other race or the lobby)
+
+	The "races" map itself is lobby state and is guarded separately by "racesMutex"
+	Which races each person is in is also tracked separately (in "racerRaces") so that the lobby and
+	the matchmaker can check it without having to wait on every race goroutine
+*/
+
+var (
+	racesMutex = new(sync.RWMutex)
+
+	// Indexed by username, then by race ID; only contains races that have not finished yet
+	racerRaces      = make(map[string]map[int]bool)
+	racerRacesMutex = new(sync.Mutex)
+)
+
+// Start the goroutine that processes commands for this race
+// (called after the race is created or restored from a checkpoint)
+func (race *Race) Run() {
+	race.commands = make(chan func())
+	race.done = make(chan struct{})
+	race.stopOnce = new(sync.Once)
+
+	go func() {
+		for {
+			select {
+			case f := <-race.commands:
+				f()
+			case <-race.done:
+				return
+			}
+		}
+	}()
+}
+
+// Queue a function to be executed by the race goroutine
+// (this returns false if the race has already been stopped)
+// This must never be called from the race goroutine itself or else it will deadlock
+func (race *Race) Send(f func()) bool {
+	select {
+	case race.commands <- f:
+		return true
+	case <-race.done:
+		return false
+	}
+}
+
+// Queue a function to be executed by the race goroutine and wait for it to complete
+// (this returns false if the race has already been stopped)
+// This must never be called from the race goroutine itself or else it will deadlock
+func (race *Race) Call(f func()) bool {
+	finished := make(chan struct{})
+	if !race.Send(func() {
+		f()
+		close(finished)
+	}) {
+		return false
+	}
+	<-finished
+
+	return true
+}
+
+// Stop the race goroutine after the current command finishes
+// (called when the race is finished or deleted)
+func (race *Race) Stop() {
+	race.stopOnce.Do(func() {
+		close(race.done)
+	})
+}
+
+/*
+	"races" map subroutines
+*/
+
+func racesGet(raceID int) (*Race, bool) {
+	racesMutex.RLock()
+	defer racesMutex.RUnlock()
+
+	race, ok := races[raceID]
+	return race, ok
+}
+
+// Get all of the current races, sorted by ID
+func racesGetAll() []*Race {
+	racesMutex.RLock()
+	defer racesMutex.RUnlock()
+
+	// https://stackoverflow.com/questions/18342784/how-to-iterate-through-a-map-in-golang-in-order/18342865
+	raceList := make([]*Race, 0, len(races))
+	for _, race := range races {
+		raceList = append(raceList, race)
+	}
+	sort.Slice(raceList, func(i, j int) bool {
+		return raceList[i].ID < raceList[j].ID
+	})
+
+	return raceList
+}
+
+func racesAdd(race *Race) {
+	racesMutex.Lock()
+	defer racesMutex.Unlock()
+
+	races[race.ID] = race
+}
+
+func racesDelete(raceID int) {
+	racesMutex.Lock()
+	defer racesMutex.Unlock()
+
+	delete(races, raceID)
+}
+
+func racesCount() int {
+	racesMutex.RLock()
+	defer racesMutex.RUnlock()
+
+	return len(races)
+}
+
+/*
+	Racer index helpers
+*/
+
+// Called from the race goroutine when someone joins a race
+// If "exclusive" is true, this returns false (and does nothing) if they are already in another race
+func racerRacesAdd(username string, raceID int, exclusive bool) bool {
+	racerRacesMutex.Lock()
+	defer racerRacesMutex.Unlock()
+
+	userRaces, ok := racerRaces[username]
+	if !ok {
+		userRaces = make(map[int]bool)
+		racerRaces[username] = userRaces
+	}
+	if exclusive {
+		for otherRaceID := range userRaces {
+			if otherRaceID != raceID {
+				return false
+			}
+		}
+	}
+	userRaces[raceID] = true
+
+	return true
+}
+
+// Called from the race goroutine when someone leaves a race or the race finishes
+func racerRacesRemove(username string, raceID int) {
+	racerRacesMutex.Lock()
+	defer racerRacesMutex.Unlock()
+
+	if userRaces, ok := racerRaces[username]; ok {
+		delete(userRaces, raceID)
+		if len(userRaces) == 0 {
+			delete(racerRaces, username)
+		}
+	}
+}
+
+// Check to see if someone is in a race that has not finished yet
+func racerIsInRace(username string) bool {
+	racerRacesMutex.Lock()
+	defer racerRacesMutex.Unlock()
+
+	return len(racerRaces[username]) > 0
+}
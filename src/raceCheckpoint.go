@@ -0,0 +1,102 @@
+package server
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+/*
+	Ongoing races only live in the "races" map, so we periodically write them to the database
+	in order to survive a server restart or crash
+*/
+
+// Write the current state of the race to the database
+// (called after every state change that we would not want to lose, e.g. a new floor or item)
+func (race *Race) Checkpoint() {
+	// Races that have not started yet are not worth saving; racers can just create them again
+	// (but scheduled races have people signed up for them ahead of time)
+	if race.Status == RaceStatusOpen && race.DatetimeScheduled == 0 {
+		return
+	}
+
+	var state string
+	if v, err := json.Marshal(race); err != nil {
+		logger.Error("Failed to marshal race #"+strconv.Itoa(race.ID)+" for the checkpoint:", err)
+		return
+	} else {
+		state = string(v)
+	}
+
+	if err := db.RaceCheckpoints.Set(race.ID, state); err != nil {
+		logger.Error("Database error while setting the checkpoint for race #"+strconv.Itoa(race.ID)+":", err)
+		return
+	}
+}
+
+// Load all of the races that were ongoing when the server was last shut down
+// (called from the "Init" function, before anyone is able to connect)
+func raceRestoreAll() {
+	checkpoints, err := db.RaceCheckpoints.GetAll()
+	if err != nil {
+		logger.Fatal("Failed to get the race checkpoints:", err)
+		return
+	}
+
+	for _, checkpoint := range checkpoints {
+		race := &Race{}
+		if err := json.Unmarshal([]byte(checkpoint.State), race); err != nil {
+			logger.Error("Failed to unmarshal the checkpoint for race #"+strconv.Itoa(checkpoint.RaceID)+":", err)
+
+			// Don't leave an orphaned race in the database
+			if err := db.Races.Delete(checkpoint.RaceID); err != nil {
+				logger.Error("Database error when deleting race ID "+strconv.Itoa(checkpoint.RaceID)+":", err)
+			}
+			continue
+		}
+		race.ID = checkpoint.RaceID
+		if race.Racers == nil {
+			race.Racers = make(map[string]*Racer)
+		}
+		race.Spectators = make(map[string]bool) // Spectators are not saved in the checkpoint
+		if race.Kicked == nil {
+			race.Kicked = make(map[string]bool)
+		}
+
+		// Nobody is connected when the server first starts, so nobody can be ready for a scheduled race
+		// (this is done before the race goroutine starts so that it does not race with it)
+		if race.Status == RaceStatusOpen {
+			for _, racer := range race.Racers {
+				racer.Status = RacerStatusNotReady
+			}
+		}
+
+		race.Run()
+		racesAdd(race)
+		for _, racer := range race.Racers {
+			racerRacesAdd(racer.Name, race.ID, false)
+		}
+
+		// Nobody is connected when the server first starts, so everyone who was racing gets
+		// the same grace period as if they had just disconnected
+		if race.Status == RaceStatusInProgress {
+			race.Call(func() {
+				for _, racer := range race.Racers {
+					if racer.Status == RacerStatusRacing {
+						race.RacerDisconnected(racer)
+					}
+				}
+			})
+		}
+
+		// Re-arm the timers that were lost when the server went down
+		if race.Status == RaceStatusOpen && race.DatetimeScheduled != 0 {
+			go race.ScheduledStart(race.DatetimeScheduled)
+		} else if race.Status == RaceStatusStarting {
+			go race.Start2()
+		} else if race.Status == RaceStatusInProgress {
+			go race.Start3()
+		}
+
+		logger.Info("Restored race", race.ID, "with", len(race.Racers), "participants (with a status of \""+race.Status+"\").")
+	}
+}
@@ -0,0 +1,78 @@
+package server
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/Zamiell/isaac-racing-server/models"
+)
+
+/*
+	Racers that disconnect in the middle of a race are given some time to come back
+	If they do not reconnect in time, they are forced to quit so that they do not hold up
+	everyone else
+*/
+
+const (
+	DefaultDisconnectGracePeriod = 2 * time.Minute
+)
+
+var (
+	disconnectGracePeriod = DefaultDisconnectGracePeriod
+)
+
+func raceDisconnectInit() {
+	// The grace period can be changed with an environment variable (in seconds)
+	gracePeriodString := os.Getenv("DISCONNECT_GRACE_PERIOD")
+	if len(gracePeriodString) == 0 {
+		return
+	}
+
+	if v, err := strconv.Atoi(gracePeriodString); err != nil || v < 0 {
+		logger.Error("The \"DISCONNECT_GRACE_PERIOD\" environment variable is not a valid number of seconds: " + gracePeriodString)
+	} else {
+		disconnectGracePeriod = time.Duration(v) * time.Second
+	}
+}
+
+// Called from the "websocketHandleDisconnect" function (and when restoring races after a restart)
+func (race *Race) RacerDisconnected(racer *Racer) {
+	racer.DatetimeDisconnected = getTimestamp()
+	race.SendRacerStatus(racer.Name, RacerStatusDisconnected)
+	race.Checkpoint()
+
+	go race.DisconnectTimeout(racer.Name, racer.DatetimeDisconnected)
+}
+
+// Called from the "websocketHandleConnectRejoin" function
+func (race *Race) RacerReconnected(racer *Racer) {
+	racer.DatetimeDisconnected = 0
+	race.SendRacerStatus(racer.Name, racer.Status)
+	race.Checkpoint()
+}
+
+func (race *Race) DisconnectTimeout(username string, datetimeDisconnected int64) {
+	time.Sleep(disconnectGracePeriod)
+
+	// The rest of the function must be executed by the race goroutine
+	// (this does nothing if the race has already finished, since the goroutine will be stopped)
+	race.Send(func() {
+		// Check to see if they came back (or disconnected again, which will have its own timer)
+		racer, ok := race.Racers[username]
+		if !ok || racer.DatetimeDisconnected != datetimeDisconnected {
+			return
+		}
+
+		logger.Info("Forcing racer \"" + username + "\" to quit since they did not reconnect to race " + strconv.Itoa(race.ID) + " in time.")
+
+		d := &IncomingWebsocketData{
+			Command: "race.DisconnectTimeout",
+			ID:      race.ID,
+			v: &models.SessionValues{
+				Username: username,
+			},
+		}
+		websocketRaceQuit(nil, d)
+	})
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"strconv"
+)
+
+// Disqualify someone from a race that is still in memory
+// Called from the "websocketAdminDisqualify" and "websocketAdminBan" functions
+func (race *Race) Disqualify(racer *Racer, reason string) {
+	newPlaces := getPlacesAfterDisqualification(race.GetPlaces(), racer.Name)
+
+	racer.Place = -2
+	racer.PlaceMid = -1
+	if racer.Status == RacerStatusRacing {
+		racer.DatetimeFinished = getTimestamp()
+		racer.RunTime = racer.DatetimeFinished - race.DatetimeStarted
+	}
+	race.SetRacerStatus(racer.Name, RacerStatusDisqualified)
+
+	// Move up everyone who finished behind them
+	for racerName, place := range newPlaces {
+		racer2 := race.Racers[racerName]
+		if racer2.Place != place {
+			racer2.Place = place
+			race.SendRacerStatus(racer2.Name, racer2.Status)
+		}
+	}
+
+	race.SetAllPlaceMid()
+	twitchRacerQuit(race, racer)
+	raceSendDisqualifiedMessage(race.ID, racer.Name, reason)
+	race.Checkpoint()
+	race.CheckFinish()
+}
+
+// Get the place of every racer, indexed by racer name
+func (race *Race) GetPlaces() map[string]int {
+	places := make(map[string]int)
+	for racerName, racer := range race.Racers {
+		places[racerName] = racer.Place
+	}
+
+	return places
+}
+
+// Get the new places for everyone else in a race after someone is disqualified
+// (the returned map does not include the person who was disqualified)
+func getPlacesAfterDisqualification(places map[string]int, disqualifiedName string) map[string]int {
+	oldPlace := places[disqualifiedName]
+
+	// If a teammate shares the place, then the team keeps it and nobody moves up
+	placeIsShared := false
+	for racerName, place := range places {
+		if racerName != disqualifiedName && place == oldPlace {
+			placeIsShared = true
+			break
+		}
+	}
+
+	newPlaces := make(map[string]int)
+	for racerName, place := range places {
+		if racerName == disqualifiedName {
+			continue
+		}
+
+		if oldPlace > 0 && !placeIsShared && place > oldPlace {
+			place--
+		}
+		newPlaces[racerName] = place
+	}
+
+	return newPlaces
+}
+
+func raceSendDisqualifiedMessage(raceID int, racerName string, reason string) {
+	message := raceGetDisqualifiedMessage(raceID, racerName, reason)
+	chatRoomsSendServerMessage("_race_"+strconv.Itoa(raceID), message)
+}
+
+// Nobody is in the chat room of a race that has already finished,
+// so everyone who was in the race gets a private message instead (if they are online)
+func raceSendDisqualifiedFinishedMessage(raceID int, racerNames []string, racerName string, reason string) {
+	message := raceGetDisqualifiedMessage(raceID, racerName, reason)
+	for _, name := range racerNames {
+		if s, ok := websocketGetSession(name); ok {
+			type PrivateMessageMessage struct {
+				Name    string `json:"name"`
+				Message string `json:"message"`
+			}
+			websocketEmit(s, "privateMessage", &PrivateMessageMessage{
+				"SERVER",
+				message,
+			})
+		}
+	}
+}
+
+func raceGetDisqualifiedMessage(raceID int, racerName string, reason string) string {
+	message := "\"" + racerName + "\" has been disqualified from race " + strconv.Itoa(raceID) + "."
+	if reason != "" {
+		message += " Reason: " + reason
+	}
+	return message
+}
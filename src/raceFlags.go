@@ -0,0 +1,107 @@
+package server
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Zamiell/isaac-racing-server/models"
+)
+
+/*
+	Anything suspicious that happens in a race is flagged for an admin to review
+	Some types of flags also keep the race out of the leaderboards until an admin resolves them
+*/
+
+var (
+	// Indexed by flag type
+	// This can be changed with an environment variable (a comma separated list of flag types)
+	raceFlagTypesThatHold = map[RaceFlagType]bool{
+		RaceFlagTypeFloor: true,
+		RaceFlagTypeSeed:  true,
+	}
+)
+
+func raceFlagsInit() {
+	holdString := os.Getenv("RACE_FLAGS_HOLD_LEADERBOARD")
+	if len(holdString) == 0 {
+		return
+	}
+
+	raceFlagTypesThatHold = make(map[RaceFlagType]bool)
+	for _, flagType := range strings.Split(holdString, ",") {
+		flagType = strings.TrimSpace(flagType)
+		if len(flagType) == 0 || flagType == "none" {
+			continue
+		}
+
+		switch RaceFlagType(flagType) {
+		case RaceFlagTypeRunTime, RaceFlagTypeItem, RaceFlagTypeFloor, RaceFlagTypeSeed:
+			raceFlagTypesThatHold[RaceFlagType(flagType)] = true
+		default:
+			logger.Error("The \"RACE_FLAGS_HOLD_LEADERBOARD\" environment variable has an unknown flag type: " + flagType)
+		}
+	}
+}
+
+// Add a flag to the admin review queue
+// (each type of flag is only raised once per racer per race so that the queue is not spammed)
+func (race *Race) Flag(racer *Racer, flagType RaceFlagType, description string) {
+	if racer.Flags == nil {
+		racer.Flags = make(map[RaceFlagType]bool)
+	}
+	if racer.Flags[flagType] {
+		return
+	}
+	racer.Flags[flagType] = true
+
+	logger.Warning("Flagged user \"" + racer.Name + "\" in race " + strconv.Itoa(race.ID) + " (" + string(flagType) + "): " + description)
+	if err := db.RaceFlags.Insert(
+		race.ID,
+		racer.ID,
+		string(flagType),
+		description,
+		raceFlagTypesThatHold[flagType],
+	); err != nil {
+		logger.Error("Database error while inserting the race flag:", err)
+	}
+}
+
+// Check to see if any of the flags raised during this race keep it out of the leaderboards
+// (called from the "race.Finish" function)
+func (race *Race) IsLeaderboardHeld() bool {
+	for _, racer := range race.Racers {
+		for flagType := range racer.Flags {
+			if raceFlagTypesThatHold[flagType] {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// The results of a finished race changed (or it was let back into the leaderboards),
+// so the leaderboard for its format has to be recalculated
+// (recalculating TrueSkill goes through every race, so it is done with a leaderboard job that is
+// applied automatically)
+// Any leaderboard jobs for the format that were already started by an administrator are now out of
+// date
+func leaderboardRecalculateFinishedRace(race models.Race, userID int) {
+	if race.Solo {
+		if race.Ranked {
+			go func() {
+				leaderboardRecalculateRankedSoloSpecificUser(userID)
+				leaderboardJobsInvalidate(LeaderboardJobFormatRankedSolo, false)
+			}()
+		}
+	} else {
+		format := RaceFormat(race.Format)
+		if format == RaceFormatUnseeded ||
+			format == RaceFormatSeeded ||
+			format == RaceFormatDiversity {
+
+			go leaderboardJobsInvalidate(string(format), true)
+		}
+	}
+}
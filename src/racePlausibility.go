@@ -0,0 +1,187 @@
+package server
+
+import (
+	"strconv"
+)
+
+/*
+	Checks for things that cannot happen in a real run
+	These do not reject anything (since the game can always surprise us);
+	instead, the racer is flagged for an administrator to review
+*/
+
+var (
+	// Items that can take a racer somewhere other than the next floor
+	plausibilitySkipItems = map[int]bool{
+		84:  true, // We Need To Go Deeper! (can be used to skip the boss)
+		127: true, // Forget Me Now (rerolls the floor, which can change the stage type)
+		324: true, // Undefined (can teleport to the I AM ERROR room)
+		419: true, // Teleport 2.0 (can teleport to the I AM ERROR room)
+		422: true, // Glowing Hour Glass (can rewind to the previous floor)
+	}
+)
+
+// Custom items are hardcoded in this range and are not in the "items.json" file
+func plausibilityItemIsKnown(itemID int) bool {
+	if itemID >= 3000 && itemID <= 3999 {
+		return true
+	}
+
+	// If the items failed to load, then we cannot check anything
+	if len(allItems) == 0 {
+		return true
+	}
+
+	_, ok := allItems[strconv.Itoa(itemID)]
+	return ok
+}
+
+func (racer *Racer) HasSkipItem() bool {
+	for _, item := range racer.Items {
+		if plausibilitySkipItems[item.ID] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Check to see if the goal of a race lets racers go to a floor
+// (racers are sent down the path that leads to the goal, so they never see the other paths)
+func plausibilityGoalAllowsFloor(goal RaceGoal, floorNum int, stageType int) bool {
+	// The 12th sacrifice in a Sacrifice Room can teleport the player to the Dark Room in any race
+	if floorNum == 11 && stageType == 0 {
+		return true
+	}
+
+	switch goal {
+	case RaceGoalBlueBaby:
+		// Cathedral and The Chest
+		return floorNum <= 9 || ((floorNum == 10 || floorNum == 11) && stageType == 1)
+
+	case RaceGoalTheLamb:
+		// Sheol and the Dark Room
+		return floorNum <= 9 || ((floorNum == 10 || floorNum == 11) && stageType == 0)
+
+	case RaceGoalMegaSatan:
+		return floorNum <= 11 || floorNum == 14
+
+	case RaceGoalHush:
+		return floorNum <= 9
+
+	case RaceGoalDelirium:
+		return floorNum <= 9 || floorNum == 12
+
+	case RaceGoalMother:
+		return floorNum <= 8
+
+	case RaceGoalBeast:
+		return floorNum <= 6 || floorNum == 13
+
+	case RaceGoalBossRush:
+		return floorNum <= 6
+	}
+
+	return true
+}
+
+// The backwards path starts after getting Dad's Note in Mausoleum II or Gehenna II
+// This is worked out from the floors that they have been to instead of trusting the client
+func (racer *Racer) IsOnBackwardsPath() bool {
+	for i := len(racer.Splits) - 1; i >= 0; i-- {
+		split := racer.Splits[i]
+		if split.FloorNum == 6 && isRepentanceStageType(split.StageType) {
+			return true
+		}
+
+		// Going back to the first floor means that they reset,
+		// unless they walked up to it from the second floor on the backwards path
+		if split.FloorNum == 1 && (i == 0 || racer.Splits[i-1].FloorNum != 2) {
+			return false
+		}
+	}
+
+	return false
+}
+
+// Check to see if a racer could have gone from the floor that they are on to a new floor
+// (floor 13 is Home and floor 14 is a fake floor that we use to represent Mega Satan)
+func FloorTransitionIsPlausible(goal RaceGoal, racer *Racer, newFloorNum int, newStageType int) bool {
+	oldFloorNum := racer.FloorNum
+	oldStageType := racer.StageType
+
+	// Repentance stage types only exist on the first few chapters
+	if isRepentanceStageType(newStageType) && newFloorNum > 8 {
+		return false
+	}
+
+	// Staying on the same floor is always fine (e.g. switching to the backwards path)
+	if newFloorNum == oldFloorNum {
+		return true
+	}
+
+	// Resetting or using something like the R Key always goes back to the first floor
+	if newFloorNum == 1 {
+		return true
+	}
+
+	if !plausibilityGoalAllowsFloor(goal, newFloorNum, newStageType) {
+		return false
+	}
+
+	// The backwards path only exists in races to The Beast
+	backwardsPath := goal == RaceGoalBeast && racer.IsOnBackwardsPath()
+
+	// Home can only be reached by going all the way back up to the top
+	if newFloorNum == 13 {
+		return backwardsPath && oldFloorNum <= 2
+	}
+
+	// Mega Satan is reached from The Chest or the Dark Room,
+	// and the Void can be reached from the Chest, the Dark Room, or Mega Satan
+	if newFloorNum == 14 {
+		return oldFloorNum == 11
+	}
+	if oldFloorNum == 13 || oldFloorNum == 14 {
+		return newFloorNum == 11 || newFloorNum == 12
+	}
+
+	oldAdjustedFloorNum := getAdjustedFloorNum(&Racer{
+		FloorNum:  oldFloorNum,
+		StageType: oldStageType,
+	})
+	newAdjustedFloorNum := getAdjustedFloorNum(&Racer{
+		FloorNum:  newFloorNum,
+		StageType: newStageType,
+	})
+	difference := newAdjustedFloorNum - oldAdjustedFloorNum
+
+	// On the backwards path, racers go back up one floor at a time
+	// (we allow two because of the Repentance floor numbering)
+	if backwardsPath {
+		return difference >= -2 && difference <= 0
+	}
+
+	// Going back up without the backwards path is not possible
+	if difference < 0 {
+		return racer.HasSkipItem()
+	}
+
+	// Normally racers go down one floor at a time,
+	// but XL floors and the Repentance floors can make it look like two
+	if difference <= 2 {
+		return true
+	}
+
+	// The Void is reached from a portal after Hush or later
+	if newFloorNum == 12 && oldAdjustedFloorNum >= 9 {
+		return true
+	}
+
+	// The 12th sacrifice in a Sacrifice Room teleports the player to the Dark Room
+	if newFloorNum == 11 && newStageType == 0 {
+		return true
+	}
+
+	return racer.HasSkipItem()
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"sync"
+	"time"
+)
+
+/*
+	Races are deleted from the races map when they finish, so we remember a few things about
+	them for a little while in case the racers want a rematch
+*/
+
+const (
+	RematchWindow = 5 * time.Minute
+)
+
+type RematchInfo struct {
+	Name          string
+	Ruleset       Ruleset
+	Password      string
+	Teams         map[string]int // Indexed by racer name (this is 0 for races without teams)
+	RematchRaceID int            // 0 until someone asks for a rematch (guarded by "rematchesMutex")
+}
+
+var (
+	rematches      = make(map[int]*RematchInfo) // Indexed by the ID of the race that finished
+	rematchesMutex = new(sync.Mutex)
+)
+
+// Called from the "race.Finish" function
+func (race *Race) AddRematchInfo() {
+	// Ranked solo races have a different starting build for each person, races in a series
+	// create the next race on their own, and challenges only have one attempt,
+	// so none of them can have rematches
+	if (race.Ruleset.Ranked && race.Ruleset.Solo) || race.SeriesID != 0 || race.ChallengeID != 0 {
+		return
+	}
+
+	teams := make(map[string]int)
+	for racerName, racer := range race.Racers {
+		teams[racerName] = racer.Team
+	}
+
+	rematchesMutex.Lock()
+	rematches[race.ID] = &RematchInfo{
+		Name:     race.Name,
+		Ruleset:  race.Ruleset,
+		Password: race.Password,
+		Teams:    teams,
+	}
+	rematchesMutex.Unlock()
+
+	raceID := race.ID
+	time.AfterFunc(RematchWindow, func() {
+		rematchesMutex.Lock()
+		delete(rematches, raceID)
+		rematchesMutex.Unlock()
+	})
+}
+
+// Get the rematch information for a race that finished recently
+// (the second return value is false if the race finished too long ago)
+func rematchGet(raceID int) (*RematchInfo, bool) {
+	rematchesMutex.Lock()
+	defer rematchesMutex.Unlock()
+
+	rematchInfo, ok := rematches[raceID]
+	return rematchInfo, ok
+}
+
+// The second return value is false if nobody has asked for a rematch yet
+func rematchGetRaceID(rematchInfo *RematchInfo) (int, bool) {
+	rematchesMutex.Lock()
+	defer rematchesMutex.Unlock()
+
+	return rematchInfo.RematchRaceID, rematchInfo.RematchRaceID != 0
+}
+
+func rematchSetRaceID(rematchInfo *RematchInfo, rematchRaceID int) {
+	rematchesMutex.Lock()
+	defer rematchesMutex.Unlock()
+
+	rematchInfo.RematchRaceID = rematchRaceID
+}
+
+// Called when a race is deleted before it starts, so that the racers can ask for a new rematch
+// (called from the "websocketRaceLeave" function)
+func rematchClearRaceID(rematchRaceID int) {
+	rematchesMutex.Lock()
+	defer rematchesMutex.Unlock()
+
+	for _, rematchInfo := range rematches {
+		if rematchInfo.RematchRaceID == rematchRaceID {
+			rematchInfo.RematchRaceID = 0
+		}
+	}
+}
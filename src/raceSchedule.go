@@ -0,0 +1,128 @@
+package server
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	Scheduled races start at a specific time instead of when everyone is ready
+	(e.g. community weekly races and tournament matches)
+	Anyone who is not ready at the scheduled time is dropped from the race
+*/
+
+const (
+	RaceScheduleMinimum  = time.Minute
+	RaceScheduleMaximum  = 30 * 24 * time.Hour
+	RaceScheduleReminder = 5 * time.Minute
+)
+
+func raceValidateSchedule(s *melody.Session, d *IncomingWebsocketData) bool {
+	now := getTimestamp()
+
+	if d.Ruleset.Solo {
+		websocketWarning(s, d.Command, "Solo races cannot be scheduled.")
+		return false
+	}
+
+	if d.DatetimeScheduled < now+RaceScheduleMinimum.Milliseconds() {
+		websocketWarning(s, d.Command, "Scheduled races must start at least "+RaceScheduleMinimum.String()+" from now.")
+		return false
+	}
+
+	if d.DatetimeScheduled > now+RaceScheduleMaximum.Milliseconds() {
+		websocketWarning(s, d.Command, "Scheduled races cannot start more than 30 days from now.")
+		return false
+	}
+
+	return true
+}
+
+// Wait for the scheduled time, reminding everyone a few minutes beforehand
+// (this is run in a new goroutine when the race is created or restored)
+func (race *Race) ScheduledStart(datetimeScheduled int64) {
+	reminderTime := datetimeScheduled - RaceScheduleReminder.Milliseconds()
+	if now := getTimestamp(); now < reminderTime {
+		time.Sleep(time.Duration(reminderTime-now) * time.Millisecond)
+		if !race.Send(func() {
+			race.SendScheduledReminder(datetimeScheduled)
+		}) {
+			// The race was deleted in the meantime
+			return
+		}
+	}
+
+	if now := getTimestamp(); now < datetimeScheduled {
+		time.Sleep(time.Duration(datetimeScheduled-now) * time.Millisecond)
+	}
+
+	// The rest of the function must be executed by the race goroutine
+	race.Send(func() {
+		race.ScheduledStartSub(datetimeScheduled)
+	})
+}
+
+func (race *Race) SendScheduledReminder(datetimeScheduled int64) {
+	if race.Status != RaceStatusOpen || race.DatetimeScheduled != datetimeScheduled {
+		return
+	}
+
+	racerNames := make([]string, 0)
+	for racerName := range race.Racers {
+		racerNames = append(racerNames, racerName)
+
+		// Not all entrants may be online before the race
+		if s, ok := websocketGetSession(racerName); ok {
+			type RaceScheduledReminderMessage struct {
+				ID                int    `json:"id"`
+				Name              string `json:"name"`
+				DatetimeScheduled int64  `json:"datetimeScheduled"`
+			}
+			websocketEmit(s, "raceScheduledReminder", &RaceScheduledReminderMessage{
+				ID:                race.ID,
+				Name:              race.Name,
+				DatetimeScheduled: race.DatetimeScheduled,
+			})
+		}
+	}
+	sort.Strings(racerNames)
+
+	discordSend(discordLobbyChannelID, "The race \""+race.Name+"\" starts in "+strconv.Itoa(int(RaceScheduleReminder.Minutes()))+" minutes! Make sure that you are ready. Entrants: "+strings.Join(racerNames, ", "))
+}
+
+func (race *Race) ScheduledStartSub(datetimeScheduled int64) {
+	if race.Status != RaceStatusOpen || race.DatetimeScheduled != datetimeScheduled {
+		return
+	}
+
+	// Drop everyone who is not ready
+	// (if everyone is dropped, the race will be deleted)
+	for racerName, racer := range race.Racers {
+		if racer.Status != RacerStatusReady {
+			logger.Info("Dropping \"" + racerName + "\" from scheduled race " + strconv.Itoa(race.ID) + " since they were not ready.")
+			race.RemoveRacer(racerName)
+		}
+	}
+	if len(race.Racers) == 0 {
+		logger.Info("Scheduled race " + strconv.Itoa(race.ID) + " was deleted since nobody was ready.")
+		return
+	}
+
+	// Check to see if there are enough people left to race
+	if len(race.Racers) == 1 || (race.IsTeamRace() && !race.TeamsAreFull()) {
+		logger.Info("Scheduled race " + strconv.Itoa(race.ID) + " was cancelled since there were not enough people ready.")
+		for racerName := range race.Racers {
+			if s, ok := websocketGetSession(racerName); ok {
+				websocketWarning(s, "raceScheduled", "The race \""+race.Name+"\" was cancelled since there were not enough people ready.")
+			}
+			race.RemoveRacer(racerName)
+		}
+		return
+	}
+
+	race.Start()
+}
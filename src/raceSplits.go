@@ -0,0 +1,108 @@
+package server
+
+/*
+	Every time a racer arrives on a new floor, we record a split (the time since the race started)
+	In seeded races and ranked solo races, everyone is playing the same kind of run every time,
+	so we also compare each split against the racer's personal best
+*/
+
+type Split struct {
+	FloorNum        int
+	StageType       int
+	SplitTime       int64 // In milliseconds since the race started
+	DatetimeArrived int64 // Epoch timestamp in milliseconds
+}
+
+// Only seeded races and ranked solo races are consistent enough to compare splits between races
+func (race *Race) HasPersonalBestSplits() bool {
+	return race.Ruleset.Format == RaceFormatSeeded ||
+		(race.Ruleset.Ranked && race.Ruleset.Solo)
+}
+
+// Record a split for a racer who just arrived on a new floor
+// (called from the "websocketRaceFloor" function)
+func (race *Race) AddSplit(racer *Racer) {
+	split := &Split{
+		FloorNum:        racer.FloorNum,
+		StageType:       racer.StageType,
+		SplitTime:       racer.DatetimeArrivedFloor - race.DatetimeStarted,
+		DatetimeArrived: racer.DatetimeArrivedFloor,
+	}
+	racer.Splits = append(racer.Splits, split)
+
+	if !race.HasPersonalBestSplits() {
+		return
+	}
+
+	// The personal best is looked up the first time that it is needed
+	if racer.PersonalBestSplits == nil {
+		if v, err := db.RaceParticipantSplits.GetPersonalBest(
+			racer.ID,
+			string(race.Ruleset.Format),
+			race.Ruleset.Character,
+			string(race.Ruleset.Goal),
+			race.Ruleset.Ranked,
+			race.Ruleset.Solo,
+		); err != nil {
+			logger.Error("Database error while getting the personal best splits for \""+racer.Name+"\":", err)
+			racer.PersonalBestSplits = make(map[int]int64)
+		} else {
+			racer.PersonalBestSplits = v
+		}
+	}
+
+	race.SendAllSplit(racer, split)
+}
+
+func (race *Race) SendAllSplit(racer *Racer, split *Split) {
+	// The personal best split is 0 if they have never been to this floor in a finished race like this
+	personalBestSplitTime := racer.PersonalBestSplits[split.FloorNum]
+	delta := int64(0)
+	if personalBestSplitTime > 0 {
+		delta = split.SplitTime - personalBestSplitTime
+	}
+
+	for _, subscriberName := range race.GetSubscribers() {
+		// Not all racers may be online during a race
+		if s, ok := websocketGetSession(subscriberName); ok {
+			type RacerSplitMessage struct {
+				ID                    int    `json:"id"`
+				Name                  string `json:"name"`
+				FloorNum              int    `json:"floorNum"`
+				StageType             int    `json:"stageType"`
+				SplitTime             int64  `json:"splitTime"`
+				PersonalBestSplitTime int64  `json:"personalBestSplitTime"`
+				Delta                 int64  `json:"delta"` // Negative if they are ahead of their personal best
+			}
+			websocketEmit(s, "racerSplit", &RacerSplitMessage{
+				ID:                    race.ID,
+				Name:                  racer.Name,
+				FloorNum:              split.FloorNum,
+				StageType:             split.StageType,
+				SplitTime:             split.SplitTime,
+				PersonalBestSplitTime: personalBestSplitTime,
+				Delta:                 delta,
+			})
+		}
+	}
+}
+
+// Write all of the splits for a racer to the database
+// (called from the "race.Finish" function)
+func (race *Race) WriteSplits(racer *Racer) bool {
+	for _, split := range racer.Splits {
+		if err := db.RaceParticipantSplits.Insert(
+			racer.ID,
+			race.ID,
+			split.FloorNum,
+			split.StageType,
+			split.SplitTime,
+			split.DatetimeArrived,
+		); err != nil {
+			logger.Error("Failed to write the RaceParticipantSplits row for \""+race.Name+"\" to the database:", err)
+			return false
+		}
+	}
+
+	return true
+}
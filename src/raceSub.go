@@ -1,11 +1,18 @@
 package server
 
 import (
+	"math/rand"
 	"strconv"
 
 	melody "gopkg.in/olahol/melody.v1"
 )
 
+const (
+	// How many times a random character or build is picked before giving up
+	// (some combinations are not allowed, so a pick can fail)
+	RaceRerollMaxAttempts = 100
+)
+
 /*
 	Race validation subroutines
 */
@@ -91,6 +98,11 @@ func raceValidateRulesetSolo(s *melody.Session, d *IncomingWebsocketData) bool {
 	ruleset := d.Ruleset
 
 	// Validate ranked solo games
+	if ruleset.TeamSize != 0 {
+		websocketWarning(s, d.Command, "Solo races cannot have teams.")
+		return false
+	}
+
 	if ruleset.Ranked {
 		return raceValidateRulesetRankedSolo(s, d)
 	}
@@ -101,6 +113,11 @@ func raceValidateRulesetSolo(s *melody.Session, d *IncomingWebsocketData) bool {
 func raceValidateRulesetRankedSolo(s *melody.Session, d *IncomingWebsocketData) bool {
 	ruleset := d.Ruleset
 
+	if _, ok := seasonGetCurrentRankedSolo(); !ok {
+		websocketWarning(s, d.Command, "There is no ranked solo season going on right now.")
+		return false
+	}
+
 	if ruleset.Format != RaceFormatSeeded {
 		websocketWarning(s, d.Command, "Ranked solo races must be seeded.")
 		return false
@@ -133,5 +150,104 @@ func raceValidateRulesetMultiplayer(s *melody.Session, d *IncomingWebsocketData)
 		return false
 	}
 
+	// Validate the team size
+	if ruleset.TeamSize != 0 && (ruleset.TeamSize < 2 || ruleset.TeamSize > RaceTeamSizeMax) {
+		websocketWarning(s, d.Command, "Team races must have between 2 and "+strconv.Itoa(RaceTeamSizeMax)+" players on each team.")
+		return false
+	}
+
 	return true
 }
+
+// The run time reported by the mod starts when the client gets the "raceStart" message and ends
+// before the server gets the "raceFinish" message, so it should be about one round trip shorter
+// than the time that the server measured
+// (the start delay is from "RunTimeGetStartDelay")
+func RunTimeIsValid(clientRunTime int64, serverRunTime int64, latency int64, startDelay int64) bool {
+	tolerance := RunTimeTolerance.Milliseconds()
+	return clientRunTime >= serverRunTime-startDelay-latency-tolerance &&
+		clientRunTime <= serverRunTime+tolerance
+}
+
+// Someone who reconnected during the countdown was given a new countdown, so their run started
+// after everyone else's (in milliseconds)
+// The rejoin time is 0 if they did not reconnect during the countdown
+func RunTimeGetStartDelay(datetimeStarted int64, datetimeRejoined int64) int64 {
+	if datetimeRejoined == 0 {
+		return 0
+	}
+
+	startDelay := datetimeRejoined + RaceCountdownRejoin.Milliseconds() - datetimeStarted
+	if startDelay < 0 {
+		return 0
+	}
+
+	return startDelay
+}
+
+/*
+	Race creation subroutines
+*/
+
+// Get a copy of a ruleset for a new race based on an old one
+// (random characters and builds are picked again, and a new seed is made if necessary)
+// Used when creating the next race in a series and when creating a rematch
+// (the original ruleset is returned if a legal character or build could not be found)
+func raceRerollRuleset(ruleset Ruleset, rerollCharacter bool, rerollBuild bool) Ruleset {
+	originalRuleset := ruleset
+
+	if rerollCharacter || ruleset.CharacterRandom {
+		previousCharacter := ruleset.Character
+		found := false
+		for i := 0; i < RaceRerollMaxAttempts; i++ {
+			ruleset.Character = characters[rand.Intn(len(characters))] // nolint: gosec
+			if ruleset.Character != previousCharacter && raceIsLegalCombination(ruleset) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			logger.Error("Failed to find a legal character after " + strconv.Itoa(RaceRerollMaxAttempts) + " attempts, so the ruleset was not rerolled.")
+			return originalRuleset
+		}
+	}
+
+	if ruleset.Format == RaceFormatSeeded && (rerollBuild || ruleset.StartingBuildRandom) {
+		previousBuild := ruleset.StartingBuild
+		found := false
+		for i := 0; i < RaceRerollMaxAttempts; i++ {
+			// We don't want to select index 0
+			ruleset.StartingBuild = rand.Intn(len(allBuilds)-1) + 1 // nolint: gosec
+			if ruleset.StartingBuild != previousBuild && raceIsLegalCombination(ruleset) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			logger.Error("Failed to find a legal starting build after " + strconv.Itoa(RaceRerollMaxAttempts) + " attempts, so the ruleset was not rerolled.")
+			return originalRuleset
+		}
+	}
+
+	ruleset.Seed = "-"
+	if ruleset.Format == RaceFormatSeeded {
+		ruleset.Seed = isaacGetRandomSeed()
+	} else if ruleset.Format == RaceFormatDiversity {
+		ruleset.Seed = diversityGetSeed(ruleset)
+	}
+
+	return ruleset
+}
+
+// Check for the same character and build restrictions as the "raceValidateRuleset" function
+func raceIsLegalCombination(ruleset Ruleset) bool {
+	if ruleset.Format != RaceFormatSeeded {
+		return true
+	}
+
+	if ruleset.Character == "Tainted Lazarus" {
+		return false
+	}
+
+	return !stringInSlice(ruleset.Character, buildExceptions[ruleset.StartingBuild])
+}
@@ -0,0 +1,139 @@
+package server
+
+/*
+	Team races are always two teams of "Ruleset.TeamSize" players each
+	A team's result is the result of its best member; the moment that one member
+	finishes, the rest of their team is finished alongside them
+*/
+
+const (
+	RaceTeamSizeMax = 3
+	RaceNumTeams    = 2
+)
+
+func (race *Race) IsTeamRace() bool {
+	return race.Ruleset.TeamSize > 0
+}
+
+func (race *Race) GetTeamRacers(team int) []*Racer {
+	teamRacers := make([]*Racer, 0)
+	for _, racer := range race.Racers {
+		if racer.Team == team {
+			teamRacers = append(teamRacers, racer)
+		}
+	}
+
+	return teamRacers
+}
+
+// Check to see if every team has the required number of players
+// (called from the "CheckStart" function)
+func (race *Race) TeamsAreFull() bool {
+	for team := 1; team <= RaceNumTeams; team++ {
+		if len(race.GetTeamRacers(team)) != race.Ruleset.TeamSize {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Get the number of teams that have at least one member who finished
+func (race *Race) GetNumFinishedTeams() int {
+	numFinishedTeams := 0
+	for team := 1; team <= RaceNumTeams; team++ {
+		for _, racer := range race.GetTeamRacers(team) {
+			if racer.Status == RacerStatusFinished {
+				numFinishedTeams++
+				break
+			}
+		}
+	}
+
+	return numFinishedTeams
+}
+
+// Get the number of teams that still have at least one member who did not quit or get disqualified
+func (race *Race) GetNumActiveTeams() int {
+	numActiveTeams := 0
+	for team := 1; team <= RaceNumTeams; team++ {
+		for _, racer := range race.GetTeamRacers(team) {
+			if racer.Status != RacerStatusQuit && racer.Status != RacerStatusDisqualified {
+				numActiveTeams++
+				break
+			}
+		}
+	}
+
+	return numActiveTeams
+}
+
+// Get the place that a team finished in, using its best member
+// (forfeits are 999 and disqualifications are 1000 to simplify comparisons)
+func (race *Race) GetTeamPlace(team int) int {
+	teamPlace := 1000
+	for _, racer := range race.GetTeamRacers(team) {
+		place := racer.Place
+		if place == -1 {
+			place = 999
+		} else if place == -2 {
+			place = 1000
+		}
+		if place > 0 && place < teamPlace {
+			teamPlace = place
+		}
+	}
+
+	return teamPlace
+}
+
+// Finish everyone on a team who is still racing alongside the member who just finished
+// (called from the "websocketRaceFinish" function)
+func (race *Race) FinishTeammates(racer *Racer) {
+	for _, teammate := range race.GetTeamRacers(racer.Team) {
+		if teammate.ID == racer.ID || teammate.Status != RacerStatusRacing {
+			continue
+		}
+
+		teammate.Place = racer.Place
+		teammate.PlaceMid = -1
+		teammate.RunTime = racer.RunTime
+		teammate.DatetimeFinished = racer.DatetimeFinished
+		race.SetRacerStatus(teammate.Name, RacerStatusFinished)
+	}
+}
+
+// Give every member of a team the mid-race place of its best member
+// (called from the "SetAllPlaceMid" function after the individual places are calculated)
+func (race *Race) setAllPlaceMidTeams(currentPlace int, lastPlace int) {
+	// Find the best individual mid-race place for each team that is still racing
+	bestPlaceMid := make(map[int]int)
+	for team := 1; team <= RaceNumTeams; team++ {
+		for _, racer := range race.GetTeamRacers(team) {
+			if racer.Status != RacerStatusRacing || racer.IsOnFirstFloor() {
+				continue
+			}
+			if best, ok := bestPlaceMid[team]; !ok || racer.PlaceMid < best {
+				bestPlaceMid[team] = racer.PlaceMid
+			}
+		}
+	}
+
+	for team := 1; team <= RaceNumTeams; team++ {
+		teamPlaceMid := lastPlace
+		if best, ok := bestPlaceMid[team]; ok {
+			teamPlaceMid = currentPlace
+			for team2, best2 := range bestPlaceMid {
+				if team2 != team && best2 < best {
+					teamPlaceMid++
+				}
+			}
+		}
+
+		for _, racer := range race.GetTeamRacers(team) {
+			if racer.Status == RacerStatusRacing {
+				racer.PlaceMid = teamPlaceMid
+			}
+		}
+	}
+}
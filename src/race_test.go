@@ -1,9 +1,12 @@
 package server_test
 
 import (
+	"math"
 	"testing"
+	"time"
 
 	server "github.com/Zamiell/isaac-racing-server"
+	"github.com/Zamiell/isaac-racing-server/models"
 )
 
 const (
@@ -333,3 +336,457 @@ func getRaceWith3Racers() *server.Race {
 		Racers: racers,
 	}
 }
+
+func TestRaceTeams(t *testing.T) {
+	t.Parallel()
+
+	const racer4Name = "Dan"
+
+	// Alice and Cathy are on the same floor, but Alice got there first
+	race := getRaceWith3Racers()
+	race.Ruleset.TeamSize = 2
+	race.Racers[Racer1Name].FloorNum = 3
+	race.Racers[Racer1Name].Team = 1
+	race.Racers[Racer2Name].FloorNum = 2
+	race.Racers[Racer2Name].Team = 1
+	race.Racers[Racer3Name].FloorNum = 3
+	race.Racers[Racer3Name].DatetimeArrivedFloor = Racer1ArrivedFloor + 50
+	race.Racers[Racer3Name].Team = 2
+	race.Racers[racer4Name] = &server.Racer{
+		ID:                   4,
+		Name:                 racer4Name,
+		Status:               server.RacerStatusRacing,
+		FloorNum:             1,
+		DatetimeArrivedFloor: Racer1ArrivedFloor,
+		PlaceMid:             -1,
+		Team:                 2,
+	}
+	race.SetAllPlaceMid()
+
+	// Everyone should have the place of the best member of their team
+	expectedPlaceMids := map[string]int{
+		Racer1Name: 1,
+		Racer2Name: 1,
+		Racer3Name: 2,
+		racer4Name: 2,
+	}
+	for racerName, expectedPlaceMid := range expectedPlaceMids {
+		placeMid := race.Racers[racerName].PlaceMid
+		if placeMid != expectedPlaceMid {
+			t.Errorf(
+				"Race Teams failed: %s should be in place %d, but was place: %d",
+				racerName,
+				expectedPlaceMid,
+				placeMid,
+			)
+		}
+	}
+}
+
+func TestRunTimeIsValid(t *testing.T) {
+	t.Parallel()
+
+	serverRunTime := int64(600000)
+	latency := int64(200)
+
+	// A normal finish is about one round trip shorter than the time that the server measured
+	if !server.RunTimeIsValid(serverRunTime-latency, serverRunTime, latency, 0) {
+		t.Error("A run time that is one round trip shorter should be valid.")
+	}
+	if !server.RunTimeIsValid(serverRunTime, serverRunTime, latency, 0) {
+		t.Error("A run time that matches the server should be valid.")
+	}
+
+	// Reporting a much faster time is the main thing that we want to catch
+	if server.RunTimeIsValid(serverRunTime-60000, serverRunTime, latency, 0) {
+		t.Error("A run time that is a minute shorter should not be valid.")
+	}
+
+	// The client cannot have been racing for longer than the server has
+	if server.RunTimeIsValid(serverRunTime+60000, serverRunTime, latency, 0) {
+		t.Error("A run time that is a minute longer should not be valid.")
+	}
+}
+
+func TestRunTimeIsValidRejoin(t *testing.T) {
+	t.Parallel()
+
+	datetimeStarted := int64(1600000000000)
+	serverRunTime := int64(600000)
+	latency := int64(200)
+
+	// Reconnecting right before the countdown ends means that their new countdown ends almost a
+	// full countdown after everyone else's
+	datetimeRejoined := datetimeStarted - 500
+	startDelay := server.RunTimeGetStartDelay(datetimeStarted, datetimeRejoined)
+	expectedStartDelay := server.RaceCountdownRejoin.Milliseconds() - 500
+	if startDelay != expectedStartDelay {
+		t.Errorf("The start delay was %d ms, but it should have been %d ms.", startDelay, expectedStartDelay)
+	}
+
+	clientRunTime := serverRunTime - startDelay - latency
+	if server.RunTimeIsValid(clientRunTime, serverRunTime, latency, 0) {
+		t.Error("A run time that is shorter by the rejoin countdown should not be valid without the start delay.")
+	}
+	if !server.RunTimeIsValid(clientRunTime, serverRunTime, latency, startDelay) {
+		t.Error("A run time that is shorter by the rejoin countdown should be valid with the start delay.")
+	}
+
+	// The start delay does not excuse a run time that is much shorter than that
+	if server.RunTimeIsValid(clientRunTime-60000, serverRunTime, latency, startDelay) {
+		t.Error("A run time that is a minute shorter than the rejoin allows should not be valid.")
+	}
+
+	// Not reconnecting during the countdown does not delay the start
+	if startDelay := server.RunTimeGetStartDelay(datetimeStarted, 0); startDelay != 0 {
+		t.Errorf("The start delay was %d ms for someone who did not reconnect, but it should have been 0.", startDelay)
+	}
+}
+
+func TestRaceSeedIsValid(t *testing.T) {
+	t.Parallel()
+
+	race := &server.Race{}
+	race.Ruleset.Format = server.RaceFormatSeeded
+	race.Ruleset.Seed = "ABCD EFGH"
+	racer := &server.Racer{CharacterNum: 1}
+
+	if !server.RaceSeedIsValid(race, racer, "abcdefgh") {
+		t.Error("The seed of the race should be valid in any format.")
+	}
+
+	// Being on a different seed is rejected (and flagged)
+	if server.RaceSeedIsValid(race, racer, "ABCD EFGJ") {
+		t.Error("A different seed should not be valid in a seeded race.")
+	}
+
+	// Later characters in multi-character races are on different seeds
+	racer.CharacterNum = 2
+	if !server.RaceSeedIsValid(race, racer, "ABCD EFGJ") {
+		t.Error("A different seed should be valid for the second character.")
+	}
+
+	race.Ruleset.Format = server.RaceFormatUnseeded
+	racer.CharacterNum = 1
+	if !server.RaceSeedIsValid(race, racer, "ABCD EFGJ") {
+		t.Error("Any seed should be valid in an unseeded race.")
+	}
+}
+
+func TestFloorTransitionIsPlausible(t *testing.T) {
+	t.Parallel()
+
+	// Make a racer who has been to the given floors (the last one is the floor that they are on)
+	getRacer := func(floors ...[2]int) *server.Racer {
+		racer := &server.Racer{
+			Splits: make([]*server.Split, 0),
+		}
+		for _, floor := range floors {
+			racer.FloorNum = floor[0]
+			racer.StageType = floor[1]
+			racer.Splits = append(racer.Splits, &server.Split{
+				FloorNum:  floor[0],
+				StageType: floor[1],
+			})
+		}
+		return racer
+	}
+	undefined := &server.Item{ID: 324}
+
+	// Going down one floor, staying on the same floor, or resetting is always fine
+	if !server.FloorTransitionIsPlausible(server.RaceGoalBlueBaby, getRacer([2]int{2, 0}), 3, 0) {
+		t.Error("Going from floor 2 to floor 3 should be plausible.")
+	}
+	if !server.FloorTransitionIsPlausible(server.RaceGoalBlueBaby, getRacer([2]int{4, 0}), 4, 1) {
+		t.Error("Staying on floor 4 should be plausible.")
+	}
+	if !server.FloorTransitionIsPlausible(server.RaceGoalBlueBaby, getRacer([2]int{7, 0}), 1, 0) {
+		t.Error("Resetting to floor 1 should be plausible.")
+	}
+
+	// Skipping most of the game is not
+	if server.FloorTransitionIsPlausible(server.RaceGoalHush, getRacer([2]int{2, 0}), 9, 0) {
+		t.Error("Going from floor 2 to floor 9 should not be plausible.")
+	}
+	racer := getRacer([2]int{2, 0})
+	racer.Items = []*server.Item{undefined}
+	if !server.FloorTransitionIsPlausible(server.RaceGoalHush, racer, 9, 0) {
+		t.Error("Going from floor 2 to floor 9 with a skip item should be plausible.")
+	}
+
+	// Going back up is only possible on the backwards path,
+	// which is worked out from where they have been instead of what the client says
+	if server.FloorTransitionIsPlausible(server.RaceGoalBlueBaby, getRacer([2]int{6, 0}), 5, 0) {
+		t.Error("Going from floor 6 to floor 5 should not be plausible.")
+	}
+	if server.FloorTransitionIsPlausible(server.RaceGoalBeast, getRacer([2]int{5, 0}, [2]int{6, 0}), 5, 0) {
+		t.Error("Going from floor 6 to floor 5 without going to Mausoleum II should not be plausible.")
+	}
+	mausoleum := getRacer([2]int{2, 0}, [2]int{3, 0}, [2]int{4, 0}, [2]int{5, 4}, [2]int{6, 4})
+	if !server.FloorTransitionIsPlausible(server.RaceGoalBeast, mausoleum, 5, 0) {
+		t.Error("Going from Mausoleum II to floor 5 on the backwards path should be plausible.")
+	}
+	if server.FloorTransitionIsPlausible(server.RaceGoalMother, mausoleum, 5, 0) {
+		t.Error("Going back up from Mausoleum II should not be plausible in a race to Mother.")
+	}
+
+	// Home is only reachable at the end of the backwards path
+	ascent := getRacer([2]int{5, 4}, [2]int{6, 4}, [2]int{5, 0}, [2]int{4, 0}, [2]int{3, 0}, [2]int{2, 0}, [2]int{1, 0})
+	if !server.FloorTransitionIsPlausible(server.RaceGoalBeast, ascent, 13, 0) {
+		t.Error("Going from floor 1 to Home on the backwards path should be plausible.")
+	}
+	if server.FloorTransitionIsPlausible(server.RaceGoalBeast, getRacer([2]int{6, 4}, [2]int{1, 0}), 13, 0) {
+		t.Error("Going to Home after resetting should not be plausible.")
+	}
+	if server.FloorTransitionIsPlausible(server.RaceGoalBlueBaby, ascent, 13, 0) {
+		t.Error("Going to Home should not be plausible in a race to Blue Baby.")
+	}
+
+	// Each goal only has one path
+	if !server.FloorTransitionIsPlausible(server.RaceGoalBlueBaby, getRacer([2]int{9, 0}), 10, 1) {
+		t.Error("Going to the Cathedral should be plausible in a race to Blue Baby.")
+	}
+	if server.FloorTransitionIsPlausible(server.RaceGoalBlueBaby, getRacer([2]int{9, 0}), 10, 0) {
+		t.Error("Going to Sheol should not be plausible in a race to Blue Baby.")
+	}
+	if !server.FloorTransitionIsPlausible(server.RaceGoalTheLamb, getRacer([2]int{9, 0}), 10, 0) {
+		t.Error("Going to Sheol should be plausible in a race to The Lamb.")
+	}
+	if server.FloorTransitionIsPlausible(server.RaceGoalTheLamb, getRacer([2]int{9, 0}), 10, 1) {
+		t.Error("Going to the Cathedral should not be plausible in a race to The Lamb.")
+	}
+	if server.FloorTransitionIsPlausible(server.RaceGoalHush, getRacer([2]int{9, 0}), 10, 1) {
+		t.Error("Going past the Blue Womb should not be plausible in a race to Hush.")
+	}
+	if !server.FloorTransitionIsPlausible(server.RaceGoalDelirium, getRacer([2]int{9, 0}), 12, 0) {
+		t.Error("Going from the Blue Womb to the Void should be plausible in a race to Delirium.")
+	}
+	if server.FloorTransitionIsPlausible(server.RaceGoalBlueBaby, getRacer([2]int{9, 0}), 12, 0) {
+		t.Error("Going to the Void should not be plausible in a race to Blue Baby.")
+	}
+	if !server.FloorTransitionIsPlausible(server.RaceGoalMother, getRacer([2]int{7, 4}), 8, 4) {
+		t.Error("Going from Corpse I to Corpse II should be plausible in a race to Mother.")
+	}
+	if server.FloorTransitionIsPlausible(server.RaceGoalBossRush, getRacer([2]int{6, 0}), 7, 0) {
+		t.Error("Going past Depths II should not be plausible in a race to the Boss Rush.")
+	}
+
+	// A Sacrifice Room can always teleport the player to the Dark Room
+	if !server.FloorTransitionIsPlausible(server.RaceGoalBlueBaby, getRacer([2]int{3, 0}), 11, 0) {
+		t.Error("Going from floor 3 to the Dark Room should be plausible.")
+	}
+	if server.FloorTransitionIsPlausible(server.RaceGoalBlueBaby, getRacer([2]int{3, 0}), 11, 1) {
+		t.Error("Going from floor 3 to The Chest should not be plausible.")
+	}
+
+	// Mega Satan is only reachable from the last floor
+	if !server.FloorTransitionIsPlausible(server.RaceGoalMegaSatan, getRacer([2]int{11, 0}), 14, 0) {
+		t.Error("Going from floor 11 to Mega Satan should be plausible.")
+	}
+	if server.FloorTransitionIsPlausible(server.RaceGoalMegaSatan, getRacer([2]int{3, 0}), 14, 0) {
+		t.Error("Going from floor 3 to Mega Satan should not be plausible.")
+	}
+	if server.FloorTransitionIsPlausible(server.RaceGoalBlueBaby, getRacer([2]int{11, 1}), 14, 0) {
+		t.Error("Going to Mega Satan should not be plausible in a race to Blue Baby.")
+	}
+}
+
+func TestChallengeGetPeriod(t *testing.T) {
+	t.Parallel()
+
+	// 2021-06-10 was a Thursday
+	now := time.Date(2021, time.June, 10, 15, 30, 0, 0, time.UTC)
+
+	start, end := server.ChallengeGetPeriod(server.ChallengeTypeDaily, now)
+	if !start.Equal(time.Date(2021, time.June, 10, 0, 0, 0, 0, time.UTC)) ||
+		!end.Equal(time.Date(2021, time.June, 11, 0, 0, 0, 0, time.UTC)) {
+
+		t.Error("The daily challenge should run from midnight to midnight, but it ran from", start, "to", end)
+	}
+
+	start, end = server.ChallengeGetPeriod(server.ChallengeTypeWeekly, now)
+	if !start.Equal(time.Date(2021, time.June, 7, 0, 0, 0, 0, time.UTC)) ||
+		!end.Equal(time.Date(2021, time.June, 14, 0, 0, 0, 0, time.UTC)) {
+
+		t.Error("The weekly challenge should run from Monday to Monday, but it ran from", start, "to", end)
+	}
+
+	// Sunday is the last day of the week, not the first
+	sunday := time.Date(2021, time.June, 13, 23, 0, 0, 0, time.UTC)
+	if start, _ = server.ChallengeGetPeriod(server.ChallengeTypeWeekly, sunday); start.Day() != 7 {
+		t.Error("The weekly challenge on Sunday should have started on June 7, but it started on", start)
+	}
+
+	// Playing three days in a row, skipping a day, and then playing twice more is a streak of 3
+	day := func(d int) time.Time {
+		return time.Date(2021, time.June, d, 0, 0, 0, 0, time.UTC)
+	}
+	starts := []time.Time{day(1), day(2), day(3), day(5), day(6)}
+	if streak := server.ChallengeGetLongestStreak(server.ChallengeTypeDaily, starts); streak != 3 {
+		t.Error("The longest daily streak should be 3, but it was", streak)
+	}
+}
+
+func TestMatchmakingFindMatches(t *testing.T) {
+	t.Parallel()
+
+	alice := &server.QueueEntry{Username: Racer1Name, Mu: 25, Sigma: 1}
+	bob := &server.QueueEntry{Username: Racer2Name, Mu: 26, Sigma: 1}
+	cathy := &server.QueueEntry{Username: Racer3Name, Mu: 40, Sigma: 1}
+	entries := []*server.QueueEntry{alice, bob, cathy}
+
+	// Right away, only the players with similar ratings should be matched
+	matches := server.MatchmakingFindMatches(entries, 0)
+	if len(matches) != 1 || len(matches[0]) != 2 {
+		t.Fatal("There should be one match with two players, but there were", len(matches), "matches.")
+	}
+	for _, entry := range matches[0] {
+		if entry == cathy {
+			t.Error(Racer3Name + " should not have been matched with a much lower rating.")
+		}
+	}
+
+	// After waiting for a while, the search window should be wide enough for everyone
+	tenMinutes := int64(10 * time.Minute / time.Millisecond)
+	matches = server.MatchmakingFindMatches([]*server.QueueEntry{alice, cathy}, tenMinutes)
+	if len(matches) != 1 || len(matches[0]) != 2 {
+		t.Error("Players with different ratings should be matched after waiting for 10 minutes.")
+	}
+}
+
+func TestTrueSkillAdjustFreeForAll(t *testing.T) {
+	t.Parallel()
+
+	mus := []float64{25, 25, 25}
+	sigmas := []float64{8.333, 8.333, 8.333}
+
+	// The winner should go up and the last place should go down
+	newMus, _ := server.TrueSkillAdjustFreeForAll(mus, sigmas, []int{1, 2, 3})
+	if newMus[0] <= 25 {
+		t.Error("The winner's mu should have increased, but it was", newMus[0])
+	}
+	if newMus[2] >= 25 {
+		t.Error("The last place's mu should have decreased, but it was", newMus[2])
+	}
+
+	// Two people who quit are tied with each other
+	// (the update is iterative, so they will only be approximately equal)
+	newMus, _ = server.TrueSkillAdjustFreeForAll(mus, sigmas, []int{1, 999, 999})
+	if math.Abs(newMus[1]-newMus[2]) > 0.01 {
+		t.Error("Tied racers should have the same mu, but they had", newMus[1], "and", newMus[2])
+	}
+	if newMus[1] >= 25 {
+		t.Error("The racers who quit should have lost mu, but they had", newMus[1])
+	}
+}
+
+func TestHallOfFameGetProofSite(t *testing.T) {
+	t.Parallel()
+
+	validProofs := map[string]string{
+		"https://www.twitch.tv/videos/123456789":     "Twitch",
+		"https://youtu.be/dQw4w9WgXcQ":               "YouTube",
+		"https://m.youtube.com/watch?v=dQw4w9WgXcQ":  "YouTube",
+		"http://www.youtube.com/watch?v=dQw4w9WgXcQ": "YouTube",
+	}
+	for proof, expected := range validProofs {
+		if site, ok := server.HallOfFameGetProofSite(proof); !ok || site != expected {
+			t.Error("The proof \""+proof+"\" should be from", expected, "but it was", site)
+		}
+	}
+
+	invalidProofs := []string{
+		"",
+		"twitch.tv/videos/123456789",
+		"https://www.twitch.tv.example.com/videos/123456789",
+		"javascript:alert(1)",
+	}
+	for _, proof := range invalidProofs {
+		if _, ok := server.HallOfFameGetProofSite(proof); ok {
+			t.Error("The proof \"" + proof + "\" should not be valid.")
+		}
+	}
+}
+
+func TestLeaderboardPaginate(t *testing.T) {
+	t.Parallel()
+
+	if start, end, totalPages := server.LeaderboardPaginate(120, 3, 50); start != 100 || end != 120 || totalPages != 3 {
+		t.Error("The last page should have the leftover rows, but it was", start, end, totalPages)
+	}
+	if start, end, totalPages := server.LeaderboardPaginate(100, 2, 50); start != 50 || end != 100 || totalPages != 2 {
+		t.Error("An exact multiple should not have an extra page, but it was", start, end, totalPages)
+	}
+	if start, end, _ := server.LeaderboardPaginate(10, 5, 50); start != end {
+		t.Error("A page past the end should be empty, but it was", start, end)
+	}
+	if start, end, _ := server.LeaderboardPaginate(10, math.MaxInt64, 500); start != 10 || end != 10 {
+		t.Error("A huge page number should be empty instead of overflowing, but it was", start, end)
+	}
+}
+
+func TestGlicko2AdjustFreeForAll(t *testing.T) {
+	t.Parallel()
+
+	// The example from "Example of the Glicko-2 system" by Mark Glickman,
+	// where the player beats the first opponent and loses to the other two
+	player := &models.StatsTrueSkill{Mu: 1500, Sigma: 200, Volatility: 0.06}
+	stats := []*models.StatsTrueSkill{
+		{Mu: 1700, Sigma: 300, Volatility: 0.06},
+		{Mu: 1550, Sigma: 100, Volatility: 0.06},
+		player,
+		{Mu: 1400, Sigma: 30, Volatility: 0.06},
+	}
+	server.Glicko2Engine{}.AdjustFreeForAll(stats, []int{1, 2, 3, 4})
+
+	if math.Abs(player.Mu-1464.06) > 0.01 {
+		t.Error("The rating should have been 1464.06, but it was", player.Mu)
+	}
+	if math.Abs(player.Sigma-151.52) > 0.01 {
+		t.Error("The rating deviation should have been 151.52, but it was", player.Sigma)
+	}
+	if math.Abs(player.Volatility-0.05999) > 0.00001 {
+		t.Error("The volatility should have been 0.05999, but it was", player.Volatility)
+	}
+}
+
+func TestHeadToHeadCalculate(t *testing.T) {
+	t.Parallel()
+
+	races := []models.HeadToHeadRace{
+		// A win where they both finished
+		{RaceID: 1, Format: "seeded", Place1: 1, Place2: 2, RunTime1: 600000, RunTime2: 630000},
+		// A loss where the first player quit
+		{RaceID: 2, Format: "seeded", Place1: -1, Place2: 3, RunTime1: 0, RunTime2: 700000},
+		// A tie where neither of them finished
+		{RaceID: 3, Format: "seeded", Place1: -1, Place2: -2},
+		// Teammates are not racing each other
+		{RaceID: 4, Format: "seeded", Place1: 1, Place2: 1, Team1: 1, Team2: 1},
+		{RaceID: 5, Format: "unseeded", Place1: 2, Place2: 1, RunTime1: 900000, RunTime2: 850000, Team1: 1, Team2: 2},
+	}
+	ratingChanges1 := map[int]float64{1: 2, 2: -1.5, 4: 5}
+	ratingChanges2 := map[int]float64{1: -1, 2: 0.5}
+	formats := server.HeadToHeadCalculate(races, ratingChanges1, ratingChanges2)
+
+	seeded, ok := formats["seeded"]
+	if !ok {
+		t.Fatal("There should be stats for seeded.")
+	}
+	if seeded.NumRaces != 3 || seeded.Wins != 1 || seeded.Losses != 1 || seeded.Ties != 1 {
+		t.Error("The seeded record should have been 1-1-1 in 3 races, but it was", seeded.Wins, seeded.Losses, seeded.Ties, seeded.NumRaces)
+	}
+	if seeded.NumGapRaces != 1 || seeded.AverageGap != -30000 {
+		t.Error("The seeded gap should have been -30000 from 1 race, but it was", seeded.AverageGap, seeded.NumGapRaces)
+	}
+	if seeded.RatingSwing != 1 {
+		t.Error("The seeded rating swing should have been 1, but it was", seeded.RatingSwing)
+	}
+
+	unseeded, ok := formats["unseeded"]
+	if !ok {
+		t.Fatal("There should be stats for unseeded.")
+	}
+	if unseeded.Losses != 1 || unseeded.AverageGap != 50000 {
+		t.Error("The unseeded race should have been a loss by 50000, but it was", unseeded.Losses, unseeded.AverageGap)
+	}
+}
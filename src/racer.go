@@ -14,6 +14,8 @@ type Racer struct {
 	DatetimeJoined       int64
 	Status               RacerStatus
 	Seed                 string
+	SeedCharacterNum     int // The character that they were on when they got to their current seed
+	NumResets            int // The number of times that they changed seeds (not counting new characters)
 	FloorNum             int
 	StageType            int
 	BackwardsPath        bool
@@ -21,13 +23,19 @@ type Racer struct {
 	Items                []*Item
 	StartingItem         int
 	Rooms                []*Room
-	CharacterNum         int // Only used in multi-character races
+	Splits               []*Split
+	PersonalBestSplits   map[int]int64 // Indexed by floor number; nil until the first split is recorded
+	CharacterNum         int           // Only used in multi-character races
 	Place                int
 	PlaceMid             int // -1 if quit or finished
 	PlaceMidOld          int
 	DatetimeFinished     int64
 	RunTime              int64 // In milliseconds
 	Comment              string
+	Team                 int                   // 0 for races without teams
+	DatetimeDisconnected int64                 // 0 if they are connected; epoch timestamp in milliseconds
+	DatetimeRejoined     int64                 // 0 unless they reconnected during the countdown; epoch timestamp in milliseconds
+	Flags                map[RaceFlagType]bool // The types of flags that were raised for this racer
 }
 
 type Item struct {
@@ -44,11 +52,19 @@ type Room struct {
 	DatetimeArrived int64
 }
 
+// Mid-race places are not calculated until racers get to the second floor
+func (racer *Racer) IsOnFirstFloor() bool {
+	return racer.FloorNum == 1 &&
+		racer.CharacterNum == 1 &&
+		!isRepentanceStageType(racer.StageType) &&
+		!racer.BackwardsPath
+}
+
 // Prepare some data about all of the ongoing racers to send to a user who just joined the race
 // (or just reconnected after a disconnect)
 // (we only want to send the client a subset of the total information in
 // order to conserve bandwidth)
-// Called from "websocketHandleConnect" and "websocketRaceJoin"
+// Called from "websocketHandleConnect", "websocketRaceJoin", and "websocketRaceSpectate"
 func racerListMessage(s *melody.Session, race *Race) {
 	type RacerMessage struct {
 		Name                 string      `json:"name"`
@@ -65,13 +81,19 @@ func racerListMessage(s *melody.Session, race *Race) {
 		DatetimeFinished     int64       `json:"datetimeFinished"`
 		RunTime              int64       `json:"runTime"` // In milliseconds, reported by the mod
 		Comment              string      `json:"comment"`
+		Team                 int         `json:"team"`
 	}
 	racers := make([]RacerMessage, 0)
 	for _, racer := range race.Racers {
+		status := racer.Status
+		if racer.DatetimeDisconnected != 0 && racer.Status == RacerStatusRacing {
+			status = RacerStatusDisconnected
+		}
+
 		racers = append(racers, RacerMessage{
 			Name:                 racer.Name,
 			DatetimeJoined:       racer.DatetimeJoined,
-			Status:               racer.Status,
+			Status:               status,
 			FloorNum:             racer.FloorNum,
 			StageType:            racer.StageType,
 			DatetimeArrivedFloor: racer.DatetimeArrivedFloor,
@@ -83,6 +105,7 @@ func racerListMessage(s *melody.Session, race *Race) {
 			DatetimeFinished:     racer.DatetimeFinished,
 			RunTime:              racer.RunTime,
 			Comment:              racer.Comment,
+			Team:                 racer.Team,
 		})
 	}
 
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/Zamiell/isaac-racing-server/models"
+)
+
+/*
+	The math that turns race results into ratings is done by a rating engine
+	Each format can use a different engine, which can be changed with an environment variable
+	(a comma separated list of "format:engine" pairs, e.g. "seeded:glicko2")
+	The stored ratings of a format are only valid for the engine that produced them,
+	so the format must be recalculated with the "adminLeaderboardJobStart" command after a change
+	(and the job applied with the "adminLeaderboardJobApply" command)
+*/
+
+type RatingEngine interface {
+	Name() string
+
+	// The stats of someone who has not played any races yet
+	DefaultStats() models.StatsTrueSkill
+
+	// The stats must be sorted by place; stats with the same place are tied
+	// (the stats are changed in place)
+	AdjustFreeForAll(stats []*models.StatsTrueSkill, places []int)
+
+	// The stats of every player on both teams are changed in place
+	AdjustTeams(winners []*models.StatsTrueSkill, losers []*models.StatsTrueSkill, draw bool)
+
+	// The number that is shown on the leaderboard
+	GetRating(stats *models.StatsTrueSkill) float64
+
+	// The number of rating points that are equivalent to one TrueSkill point
+	// (this is used so that the matchmaking windows work with any engine)
+	Scale() float64
+}
+
+const (
+	RatingEngineTrueSkill = "trueskill"
+	RatingEngineGlicko2   = "glicko2"
+)
+
+var (
+	// Indexed by engine name
+	ratingEngines = map[string]RatingEngine{
+		RatingEngineTrueSkill: TrueSkillEngine{},
+		RatingEngineGlicko2:   Glicko2Engine{},
+	}
+
+	// Indexed by format; formats that are not in the map use TrueSkill
+	ratingEngineFormats      = make(map[RaceFormat]RatingEngine)
+	ratingEngineFormatsMutex = sync.RWMutex{}
+)
+
+func ratingEngineInit() {
+	enginesString := os.Getenv("RATING_ENGINES")
+	if len(enginesString) == 0 {
+		return
+	}
+
+	ratingEngineFormatsMutex.Lock()
+	defer ratingEngineFormatsMutex.Unlock()
+
+	for _, pair := range strings.Split(enginesString, ",") {
+		pair = strings.TrimSpace(pair)
+		if len(pair) == 0 {
+			continue
+		}
+
+		parts := strings.Split(pair, ":")
+		if len(parts) != 2 {
+			logger.Error("The \"RATING_ENGINES\" environment variable has an invalid entry: " + pair)
+			continue
+		}
+		format := RaceFormat(strings.TrimSpace(parts[0]))
+		if format != RaceFormatUnseeded && format != RaceFormatSeeded && format != RaceFormatDiversity {
+			logger.Error("The \"RATING_ENGINES\" environment variable has an unknown format: " + string(format))
+			continue
+		}
+		engine, ok := RatingEngineGet(strings.TrimSpace(parts[1]))
+		if !ok {
+			logger.Error("The \"RATING_ENGINES\" environment variable has an unknown rating engine: " + parts[1])
+			continue
+		}
+
+		ratingEngineFormats[format] = engine
+		logger.Info("Using the " + engine.Name() + " rating engine for " + string(format) + " races.")
+	}
+}
+
+// The second return value is false if there is no engine with that name
+func RatingEngineGet(name string) (RatingEngine, bool) {
+	engine, ok := ratingEngines[name]
+	return engine, ok
+}
+
+// Get the engine that is currently used to rate a format
+func ratingEngineGetFormat(format RaceFormat) RatingEngine {
+	ratingEngineFormatsMutex.RLock()
+	defer ratingEngineFormatsMutex.RUnlock()
+
+	if engine, ok := ratingEngineFormats[format]; ok {
+		return engine
+	}
+
+	return ratingEngines[RatingEngineTrueSkill]
+}
+
+// Get someone's stats for a format from the database
+// (the "users" table defaults are for TrueSkill, so people who have not played any races yet get
+// the defaults of the engine instead)
+func ratingEngineGetStats(engine RatingEngine, userID int, format RaceFormat) (models.StatsTrueSkill, error) {
+	stats, err := db.Users.GetTrueSkill(userID, string(format))
+	if err != nil {
+		return stats, err
+	}
+
+	if stats.NumRaces == 0 {
+		defaultStats := engine.DefaultStats()
+		stats.Mu = defaultStats.Mu
+		stats.Sigma = defaultStats.Sigma
+		stats.Volatility = defaultStats.Volatility
+	}
+
+	return stats, nil
+}
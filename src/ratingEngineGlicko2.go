@@ -0,0 +1,212 @@
+package server
+
+import (
+	"math"
+
+	"github.com/Zamiell/isaac-racing-server/models"
+)
+
+/*
+	Glicko-2, as described in "Example of the Glicko-2 system" by Mark Glickman:
+	http://www.glicko.net/glicko/glicko2.pdf
+	Each race is one rating period where every racer has played a game against every other racer
+	(team races are every racer against every racer on the other team)
+*/
+
+const (
+	glicko2Scale             = 173.7178 // Converts between the Glicko and the Glicko-2 scales
+	glicko2DefaultRating     = 1500
+	glicko2DefaultDeviation  = 350
+	glicko2DefaultVolatility = 0.06
+	glicko2Tau               = 0.5      // Constrains how much the volatility can change
+	glicko2Epsilon           = 0.000001 // The convergence tolerance of the volatility iteration
+)
+
+type Glicko2Engine struct{}
+
+// A game against one opponent in a rating period
+type glicko2Game struct {
+	Opponent models.StatsTrueSkill
+	Score    float64 // 1 for a win, 0.5 for a draw, and 0 for a loss
+}
+
+func (Glicko2Engine) Name() string {
+	return RatingEngineGlicko2
+}
+
+func (Glicko2Engine) DefaultStats() models.StatsTrueSkill {
+	return models.StatsTrueSkill{
+		Mu:         glicko2DefaultRating,
+		Sigma:      glicko2DefaultDeviation,
+		Volatility: glicko2DefaultVolatility,
+	}
+}
+
+func (Glicko2Engine) AdjustFreeForAll(stats []*models.StatsTrueSkill, places []int) {
+	games := make([][]glicko2Game, len(stats))
+	for i := range stats {
+		for j := range stats {
+			if i == j {
+				continue
+			}
+
+			score := 0.5
+			if places[i] < places[j] {
+				score = 1
+			} else if places[i] > places[j] {
+				score = 0
+			}
+			games[i] = append(games[i], glicko2Game{
+				Opponent: *stats[j],
+				Score:    score,
+			})
+		}
+	}
+
+	glicko2AdjustAll(stats, games)
+}
+
+func (Glicko2Engine) AdjustTeams(winners []*models.StatsTrueSkill, losers []*models.StatsTrueSkill, draw bool) {
+	winnerScore := 1.0
+	if draw {
+		winnerScore = 0.5
+	}
+
+	allStats := make([]*models.StatsTrueSkill, 0, len(winners)+len(losers))
+	games := make([][]glicko2Game, 0, len(winners)+len(losers))
+	for _, winnerStats := range winners {
+		winnerGames := make([]glicko2Game, 0, len(losers))
+		for _, loserStats := range losers {
+			winnerGames = append(winnerGames, glicko2Game{
+				Opponent: *loserStats,
+				Score:    winnerScore,
+			})
+		}
+		allStats = append(allStats, winnerStats)
+		games = append(games, winnerGames)
+	}
+	for _, loserStats := range losers {
+		loserGames := make([]glicko2Game, 0, len(winners))
+		for _, winnerStats := range winners {
+			loserGames = append(loserGames, glicko2Game{
+				Opponent: *winnerStats,
+				Score:    1 - winnerScore,
+			})
+		}
+		allStats = append(allStats, loserStats)
+		games = append(games, loserGames)
+	}
+
+	glicko2AdjustAll(allStats, games)
+}
+
+// Like TrueSkill, the leaderboard uses a conservative estimate of someone's skill
+func (Glicko2Engine) GetRating(stats *models.StatsTrueSkill) float64 {
+	return stats.Mu - 2*stats.Sigma
+}
+
+// The ratio between the starting deviations of the two engines
+func (Glicko2Engine) Scale() float64 {
+	return glicko2DefaultDeviation / trueSkillDefaultSigma
+}
+
+/*
+	Subroutines
+*/
+
+// Everyone is rated against the stats that their opponents had before the race,
+// so the new stats are only written after all of them are calculated
+func glicko2AdjustAll(stats []*models.StatsTrueSkill, games [][]glicko2Game) {
+	newStats := make([]models.StatsTrueSkill, len(stats))
+	for i, playerStats := range stats {
+		newStats[i] = glicko2Adjust(*playerStats, games[i])
+	}
+	for i, playerStats := range stats {
+		playerStats.Mu = newStats[i].Mu
+		playerStats.Sigma = newStats[i].Sigma
+		playerStats.Volatility = newStats[i].Volatility
+	}
+}
+
+// Steps 2 through 8 of the Glickman paper
+func glicko2Adjust(stats models.StatsTrueSkill, games []glicko2Game) models.StatsTrueSkill {
+	mu := (stats.Mu - glicko2DefaultRating) / glicko2Scale
+	phi := stats.Sigma / glicko2Scale
+	sigma := stats.Volatility
+	if sigma <= 0 {
+		sigma = glicko2DefaultVolatility
+	}
+
+	// Someone who did not play only has their deviation increase
+	if len(games) == 0 {
+		stats.Sigma = math.Sqrt(phi*phi+sigma*sigma) * glicko2Scale
+		stats.Volatility = sigma
+		return stats
+	}
+
+	vInverse := 0.0
+	improvement := 0.0
+	for _, game := range games {
+		opponentMu := (game.Opponent.Mu - glicko2DefaultRating) / glicko2Scale
+		opponentPhi := game.Opponent.Sigma / glicko2Scale
+		g := glicko2G(opponentPhi)
+		e := 1 / (1 + math.Exp(-g*(mu-opponentMu)))
+		vInverse += g * g * e * (1 - e)
+		improvement += g * (game.Score - e)
+	}
+	v := 1 / vInverse
+	delta := v * improvement
+
+	newSigma := glicko2GetVolatility(phi, sigma, delta, v)
+	phiStar := math.Sqrt(phi*phi + newSigma*newSigma)
+	newPhi := 1 / math.Sqrt(1/(phiStar*phiStar)+1/v)
+	newMu := mu + newPhi*newPhi*improvement
+
+	stats.Mu = newMu*glicko2Scale + glicko2DefaultRating
+	stats.Sigma = newPhi * glicko2Scale
+	stats.Volatility = newSigma
+	return stats
+}
+
+func glicko2G(phi float64) float64 {
+	return 1 / math.Sqrt(1+3*phi*phi/(math.Pi*math.Pi))
+}
+
+// Step 5 of the Glickman paper (the Illinois algorithm)
+func glicko2GetVolatility(phi float64, sigma float64, delta float64, v float64) float64 {
+	a := math.Log(sigma * sigma)
+	f := func(x float64) float64 {
+		ex := math.Exp(x)
+		d := phi*phi + v + ex
+		return ex*(delta*delta-phi*phi-v-ex)/(2*d*d) - (x-a)/(glicko2Tau*glicko2Tau)
+	}
+
+	x0 := a
+	var x1 float64
+	if delta*delta > phi*phi+v {
+		x1 = math.Log(delta*delta - phi*phi - v)
+	} else {
+		k := 1.0
+		for f(a-k*glicko2Tau) < 0 {
+			k++
+		}
+		x1 = a - k*glicko2Tau
+	}
+
+	f0 := f(x0)
+	f1 := f(x1)
+	for math.Abs(x1-x0) > glicko2Epsilon {
+		x2 := x0 + (x0-x1)*f0/(f1-f0)
+		f2 := f(x2)
+		if f2*f1 <= 0 {
+			x0 = x1
+			f0 = f1
+		} else {
+			f0 /= 2
+		}
+		x1 = x2
+		f1 = f2
+	}
+
+	return math.Exp(x0 / 2)
+}
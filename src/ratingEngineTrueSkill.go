@@ -0,0 +1,88 @@
+package server
+
+import (
+	"github.com/Zamiell/isaac-racing-server/models"
+	trueskill "github.com/mafredri/go-trueskill"
+)
+
+const (
+	// These match the default values of the "users" table
+	trueSkillDefaultMu    = 25
+	trueSkillDefaultSigma = 8.333
+)
+
+// Free-for-all races use the "go-trueskill" library and team races use the update in
+// "leaderboardTrueSkillTeams.go"
+type TrueSkillEngine struct{}
+
+func (TrueSkillEngine) Name() string {
+	return RatingEngineTrueSkill
+}
+
+func (TrueSkillEngine) DefaultStats() models.StatsTrueSkill {
+	return models.StatsTrueSkill{
+		Mu:    trueSkillDefaultMu,
+		Sigma: trueSkillDefaultSigma,
+	}
+}
+
+func (TrueSkillEngine) AdjustFreeForAll(stats []*models.StatsTrueSkill, places []int) {
+	mus := make([]float64, 0, len(stats))
+	sigmas := make([]float64, 0, len(stats))
+	for _, playerStats := range stats {
+		mus = append(mus, playerStats.Mu)
+		sigmas = append(sigmas, playerStats.Sigma)
+	}
+
+	newMus, newSigmas := TrueSkillAdjustFreeForAll(mus, sigmas, places)
+	for i, playerStats := range stats {
+		playerStats.Mu = newMus[i]
+		playerStats.Sigma = newSigmas[i]
+	}
+}
+
+func (TrueSkillEngine) AdjustTeams(winners []*models.StatsTrueSkill, losers []*models.StatsTrueSkill, draw bool) {
+	leaderboardAdjustTrueSkillTeams(winners, losers, draw)
+}
+
+func (TrueSkillEngine) GetRating(stats *models.StatsTrueSkill) float64 {
+	// Based on code from:
+	// https://godoc.org/github.com/mafredri/go-trueskill
+
+	ts := trueskill.New()
+	player := trueskill.NewPlayer(stats.Mu, stats.Sigma)
+	return ts.TrueSkill(player)
+}
+
+func (TrueSkillEngine) Scale() float64 {
+	return 1
+}
+
+// Do a single free-for-all TrueSkill update
+// (the players must be sorted by place; players with the same place are tied)
+func TrueSkillAdjustFreeForAll(mus []float64, sigmas []float64, places []int) ([]float64, []float64) {
+	// Based on code from:
+	// https://godoc.org/github.com/mafredri/go-trueskill
+	ts := trueskill.New()
+	players := make([]trueskill.Player, 0, len(mus))
+	for i := range mus {
+		players = append(players, trueskill.NewPlayer(mus[i], sigmas[i]))
+	}
+
+	// Each entry represents whether a player tied with the player after them
+	draws := make([]bool, len(players)-1)
+	for i := range draws {
+		draws[i] = places[i] == places[i+1]
+	}
+
+	newPlayers, _ := ts.AdjustSkillsWithDraws(players, draws)
+
+	newMus := make([]float64, 0, len(newPlayers))
+	newSigmas := make([]float64, 0, len(newPlayers))
+	for _, player := range newPlayers {
+		newMus = append(newMus, player.Mu())
+		newSigmas = append(newSigmas, player.Sigma())
+	}
+
+	return newMus, newSigmas
+}
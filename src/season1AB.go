@@ -1,205 +0,0 @@
-package server
-
-var season1R9AB = []HallOfFameEntry{
-	{
-		Rank:        1,
-		Racer:       "Dea1h",
-		ProfileName: "Dea1h",
-		Time:        5039,
-		Date:        "2017-07-13",
-		Proof:       "https://www.twitch.tv/videos/158833908",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        2,
-		Racer:       "Cyber_1",
-		ProfileName: "Cyber_1",
-		Time:        5462,
-		Date:        "2017-04-23",
-		Proof:       "https://www.twitch.tv/videos/137587747",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        3,
-		Racer:       "CrafterLynx",
-		ProfileName: "CrafterLynx",
-		Time:        5620,
-		Date:        "2017-09-19",
-		Proof:       "https://www.twitch.tv/videos/175962871",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        4,
-		Racer:       "Zamiel",
-		ProfileName: "Zamiel",
-		Time:        5629,
-		Date:        "2017-04-12",
-		Proof:       "https://www.twitch.tv/videos/135084542",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        5,
-		Racer:       "ReidMercury__",
-		ProfileName: "ReidMercury__",
-		Time:        5655,
-		Date:        "2017-09-17",
-		Proof:       "https://www.twitch.tv/videos/175491970",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        6,
-		Racer:       "ceehe",
-		ProfileName: "ceehe",
-		Time:        5776,
-		Date:        "2017-10-18",
-		Proof:       "https://www.twitch.tv/videos/180734439",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        7,
-		Racer:       "leo_ze_tron",
-		ProfileName: "leo_ze_tron",
-		Time:        5925,
-		Date:        "2017-08-17",
-		Proof:       "https://www.twitch.tv/videos/167785993",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        8,
-		Racer:       "SlashSP",
-		ProfileName: "SlashSP",
-		Time:        5938,
-		Date:        "2017-09-22",
-		Proof:       "https://www.twitch.tv/videos/176523741",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        9,
-		Racer:       "Shigan",
-		ProfileName: "Shigan",
-		Time:        5984,
-		Date:        "2017-05-14",
-		Proof:       "https://www.twitch.tv/videos/143486007",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        10,
-		Racer:       "thereisnofuture",
-		ProfileName: "thereisnofuture",
-		Time:        5999,
-		Date:        "2017-04-14",
-		Proof:       "https://www.twitch.tv/videos/135612266",
-		Site:        "Twitch",
-	},
-}
-
-var season1R14AB = []HallOfFameEntry{
-	{
-		Rank:        1,
-		Racer:       "Dea1h",
-		ProfileName: "Dea1h",
-		Time:        8909,
-		Date:        "2017-09-24",
-		Proof:       "https://www.twitch.tv/videos/177220345",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        2,
-		Racer:       "CrafterLynx",
-		ProfileName: "CrafterLynx",
-		Time:        9061,
-		Date:        "2017-09-09",
-		Proof:       "https://www.twitch.tv/videos/175961290",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        3,
-		Racer:       "ceehe",
-		ProfileName: "ceehe",
-		Time:        9782,
-		Date:        "2017-10-08",
-		Proof:       "https://www.twitch.tv/videos/213334990",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        4,
-		Racer:       "Yama",
-		ProfileName: "Yama",
-		Time:        9837,
-		Date:        "2017-09-15",
-		Proof:       "https://www.twitch.tv/videos/174831927",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        5,
-		Racer:       "SlashSP",
-		ProfileName: "SlashSP",
-		Time:        9960,
-		Date:        "2017-09-22",
-		Proof:       "https://www.twitch.tv/videos/176523011",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        6,
-		Racer:       "Shigan",
-		ProfileName: "Shigan",
-		Time:        10188,
-		Date:        "2017-05-14",
-		Proof:       "https://www.twitch.tv/videos/143486007",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        7,
-		Racer:       "ReidMercury__",
-		ProfileName: "ReidMercury__",
-		Time:        10431,
-		Date:        "2017-08-18",
-		Proof:       "https://www.twitch.tv/videos/167959080",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        8,
-		Racer:       "Zamiel",
-		ProfileName: "Zamiel",
-		Time:        10616,
-		Date:        "2017-04-12",
-		Proof:       "https://www.twitch.tv/videos/135084542",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        9,
-		Racer:       "MrPopoche1",
-		ProfileName: "",
-		Time:        10665,
-		Date:        "2017-06-06",
-		Proof:       "https://www.twitch.tv/videos/149949436",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        10,
-		Racer:       "SergeBenamou31",
-		ProfileName: "",
-		Time:        11364,
-		Date:        "2017-08-31",
-		Proof:       "https://www.twitch.tv/videos/171259098",
-		Site:        "Twitch",
-	},
-}
@@ -1,103 +0,0 @@
-package server
-
-var season1RankedSolo = []HallOfFameEntryOnline{
-	{
-		Rank:              1,
-		Racer:             "bmzloop",
-		ProfileName:       "Loopy",
-		AdjustedAverage:   761,
-		UnadjustedAverage: 761,
-		ForfeitPenalty:    0,
-		NumForfeits:       0,
-	},
-
-	{
-		Rank:              2,
-		Racer:             "Dea1h",
-		ProfileName:       "Dea1h",
-		AdjustedAverage:   792,
-		UnadjustedAverage: 734,
-		ForfeitPenalty:    58,
-		NumForfeits:       4,
-	},
-
-	{
-		Rank:              3,
-		Racer:             "Yama",
-		ProfileName:       "Yama",
-		AdjustedAverage:   842,
-		UnadjustedAverage: 779,
-		ForfeitPenalty:    62,
-		NumForfeits:       4,
-	},
-
-	{
-		Rank:              4,
-		Racer:             "Zamiel",
-		ProfileName:       "Zamiel",
-		AdjustedAverage:   844,
-		UnadjustedAverage: 811,
-		ForfeitPenalty:    32,
-		NumForfeits:       2,
-	},
-
-	{
-		Rank:              5,
-		Racer:             "910dan",
-		ProfileName:       "910dan",
-		AdjustedAverage:   879,
-		UnadjustedAverage: 879,
-		ForfeitPenalty:    0,
-		NumForfeits:       0,
-	},
-
-	{
-		Rank:              6,
-		Racer:             "SlashSP",
-		ProfileName:       "SlashSP",
-		AdjustedAverage:   911,
-		UnadjustedAverage: 799,
-		ForfeitPenalty:    111,
-		NumForfeits:       7,
-	},
-
-	{
-		Rank:              7,
-		Racer:             "Cyber_1",
-		ProfileName:       "Cyber_1",
-		AdjustedAverage:   913,
-		UnadjustedAverage: 773,
-		ForfeitPenalty:    139,
-		NumForfeits:       9,
-	},
-
-	{
-		Rank:              8,
-		Racer:             "AdRyDN",
-		ProfileName:       "Adrydn",
-		AdjustedAverage:   928,
-		UnadjustedAverage: 814,
-		ForfeitPenalty:    113,
-		NumForfeits:       7,
-	},
-
-	{
-		Rank:              9,
-		Racer:             "Ou_J",
-		ProfileName:       "OhJay",
-		AdjustedAverage:   993,
-		UnadjustedAverage: 871,
-		ForfeitPenalty:    122,
-		NumForfeits:       7,
-	},
-
-	{
-		Rank:              10,
-		Racer:             "Krakenos",
-		ProfileName:       "Krakenos",
-		AdjustedAverage:   1003,
-		UnadjustedAverage: 896,
-		ForfeitPenalty:    107,
-		NumForfeits:       6,
-	},
-}
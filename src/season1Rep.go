@@ -1,103 +0,0 @@
-package server
-
-var season1R7Rep = []HallOfFameEntry{
-	{
-		Rank:        1,
-		Racer:       "Finalkids",
-		ProfileName: "Finalkids",
-		Time:        3669,
-		Date:        "2022-01-09",
-		Proof:       "https://www.youtube.com/watch?v=s-ktyzlmgUk",
-		Site:        "YouTube",
-	},
-
-	{
-		Rank:        2,
-		Racer:       "mgln",
-		ProfileName: "mgln",
-		Time:        3687,
-		Date:        "2022-02-05",
-		Proof:       "https://www.twitch.tv/videos/1288027564",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        3,
-		Racer:       "Greg",
-		ProfileName: "Greg570",
-		Time:        3829,
-		Date:        "2021-09-25",
-		Proof:       "https://www.youtube.com/watch?v=V75hHJKsIQw",
-		Site:        "YouTube",
-	},
-
-	{
-		Rank:        4,
-		Racer:       "Cyber_1",
-		ProfileName: "Cyber_1",
-		Time:        3896,
-		Date:        "2022-02-02",
-		Proof:       "https://www.twitch.tv/videos/1286578327",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        5,
-		Racer:       "Adrayon",
-		ProfileName: "Adrayon",
-		Time:        3949,
-		Date:        "2022-03-06",
-		Proof:       "https://www.twitch.tv/videos/1416760218",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        6,
-		Racer:       "Gamonymous",
-		ProfileName: "Gamonymous",
-		Time:        3950,
-		Date:        "2021-09-25",
-		Proof:       "https://www.youtube.com/watch?v=bzMP1WYPtkY",
-		Site:        "YouTube",
-	},
-
-	{
-		Rank:        7,
-		Racer:       "KiraKeepKool",
-		ProfileName: "KiraKeepKool",
-		Time:        3954,
-		Date:        "2022-03-08",
-		Proof:       "https://www.youtube.com/watch?v=j0uPmUSLxeU&t=625s",
-		Site:        "YouTube",
-	},
-
-	{
-		Rank:        8,
-		Racer:       "leo_ze_tron",
-		ProfileName: "leo_ze_tron",
-		Time:        3963,
-		Date:        "2022-01-04",
-		Proof:       "https://www.twitch.tv/videos/1252903653",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        9,
-		Racer:       "YuCaesar",
-		ProfileName: "YuCaesar",
-		Time:        4048,
-		Date:        "2022-02-24",
-		Proof:       "https://www.twitch.tv/videos/1306657459",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        10,
-		Racer:       "sisuka",
-		ProfileName: "sisuka",
-		Time:        4078,
-		Date:        "2022-02-11",
-		Proof:       "https://www.twitch.tv/videos/1293502381",
-		Site:        "Twitch",
-	},
-}
@@ -1,103 +0,0 @@
-package server
-
-var season2R7AB = []HallOfFameEntry{
-	{
-		Rank:        1,
-		Racer:       "Dea1h",
-		ProfileName: "Dea1h",
-		Time:        3673,
-		Date:        "2017-07-20",
-		Proof:       "https://www.twitch.tv/videos/160709749",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        2,
-		Racer:       "Shigan",
-		ProfileName: "Shigan",
-		Time:        4033,
-		Date:        "2017-07-19",
-		Proof:       "https://www.twitch.tv/videos/160356773",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        3,
-		Racer:       "ceehe",
-		ProfileName: "ceehe",
-		Time:        4158,
-		Date:        "2017-08-10",
-		Proof:       "https://www.twitch.tv/videos/165878075",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        4,
-		Racer:       "Zamiel",
-		ProfileName: "Zamiel",
-		Time:        4476,
-		Date:        "2017-06-07",
-		Proof:       "https://www.twitch.tv/videos/150106693",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        5,
-		Racer:       "CrafterLynx",
-		ProfileName: "CrafterLynx",
-		Time:        4479,
-		Date:        "2017-06-06",
-		Proof:       "https://www.twitch.tv/videos/148982150",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        6,
-		Racer:       "SlashSP",
-		ProfileName: "SlashSP",
-		Time:        4548,
-		Date:        "2017-07-24",
-		Proof:       "https://www.twitch.tv/videos/161482668",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        7,
-		Racer:       "thereisnofuture",
-		ProfileName: "thereisnofuture",
-		Time:        4593,
-		Date:        "2017-09-26",
-		Proof:       "https://www.twitch.tv/videos/177693217",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        8,
-		Racer:       "AdRyDN",
-		ProfileName: "Adrydn",
-		Time:        4619,
-		Date:        "2017-06-14",
-		Proof:       "https://www.twitch.tv/videos/149545223",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        9,
-		Racer:       "ReidMercury__",
-		ProfileName: "ReidMercury__",
-		Time:        4625,
-		Date:        "2017-11-10",
-		Proof:       "https://www.twitch.tv/videos/200127524",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        10,
-		Racer:       "thalen22",
-		ProfileName: "thalen22",
-		Time:        4628,
-		Date:        "2017-07-31",
-		Proof:       "https://www.twitch.tv/videos/163297005",
-		Site:        "Twitch",
-	},
-}
@@ -1,103 +0,0 @@
-package server
-
-var season2RankedSolo = []HallOfFameEntryOnline{
-	{
-		Rank:              1,
-		Racer:             "Cyber_1",
-		ProfileName:       "Cyber_1",
-		AdjustedAverage:   697,
-		UnadjustedAverage: 683,
-		ForfeitPenalty:    13,
-		NumForfeits:       2,
-	},
-
-	{
-		Rank:              2,
-		Racer:             "antizoubilamakA",
-		ProfileName:       "CRAZYEIGHTSFAN69",
-		AdjustedAverage:   718,
-		UnadjustedAverage: 691,
-		ForfeitPenalty:    27,
-		NumForfeits:       4,
-	},
-
-	{
-		Rank:              3,
-		Racer:             "Zamiel",
-		ProfileName:       "Zamiel",
-		AdjustedAverage:   732,
-		UnadjustedAverage: 718,
-		ForfeitPenalty:    14,
-		NumForfeits:       2,
-	},
-
-	{
-		Rank:              4,
-		Racer:             "thereisnofuture",
-		ProfileName:       "thereisnofuture",
-		AdjustedAverage:   799,
-		UnadjustedAverage: 739,
-		ForfeitPenalty:    59,
-		NumForfeits:       8,
-	},
-
-	{
-		Rank:              5,
-		Racer:             "cold90a",
-		ProfileName:       "cold90a",
-		AdjustedAverage:   881,
-		UnadjustedAverage: 786,
-		ForfeitPenalty:    94,
-		NumForfeits:       12,
-	},
-
-	{
-		Rank:              6,
-		Racer:             "elgirs",
-		ProfileName:       "elgirs",
-		AdjustedAverage:   940,
-		UnadjustedAverage: 804,
-		ForfeitPenalty:    136,
-		NumForfeits:       17,
-	},
-
-	{
-		Rank:              7,
-		Racer:             "ILLARIO",
-		ProfileName:       "ILLARIO",
-		AdjustedAverage:   947,
-		UnadjustedAverage: 853,
-		ForfeitPenalty:    93,
-		NumForfeits:       11,
-	},
-
-	{
-		Rank:              8,
-		Racer:             "Noro",
-		ProfileName:       "Noro",
-		AdjustedAverage:   1021,
-		UnadjustedAverage: 954,
-		ForfeitPenalty:    66,
-		NumForfeits:       7,
-	},
-
-	{
-		Rank:              9,
-		Racer:             "galnegus",
-		ProfileName:       "galnegus",
-		AdjustedAverage:   1051,
-		UnadjustedAverage: 875,
-		ForfeitPenalty:    175,
-		NumForfeits:       20,
-	},
-
-	{
-		Rank:              10,
-		Racer:             "Im_Cirno",
-		ProfileName:       "Im_Cirno",
-		AdjustedAverage:   1191,
-		UnadjustedAverage: 1001,
-		ForfeitPenalty:    190,
-		NumForfeits:       19,
-	},
-}
@@ -1,103 +0,0 @@
-package server
-
-var season2R7Rep = []HallOfFameEntry{
-	{
-		Rank:        1,
-		Racer:       "mgln",
-		ProfileName: "mgln",
-		Time:        2716,
-		Date:        "2022-05-22",
-		Proof:       "https://www.twitch.tv/videos/1490540193",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        2,
-		Racer:       "Finalkids",
-		ProfileName: "Finalkids",
-		Time:        2738,
-		Date:        "2022-03-17",
-		Proof:       "https://www.youtube.com/watch?v=_FMJjWuz9a0",
-		Site:        "YouTube",
-	},
-
-	{
-		Rank:        3,
-		Racer:       "tayu",
-		ProfileName: "tayu",
-		Time:        2888,
-		Date:        "2022-08-07",
-		Proof:       "https://www.youtube.com/watch?v=Y9-05jneA24",
-		Site:        "YouTube",
-	},
-
-	{
-		Rank:        4,
-		Racer:       "KiraKeepKool",
-		ProfileName: "KiraKeepKool",
-		Time:        2892,
-		Date:        "2022-05-13",
-		Proof:       "https://www.twitch.tv/videos/1482375296",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        5,
-		Racer:       "leo_ze_tron",
-		ProfileName: "leo_ze_tron",
-		Time:        2926,
-		Date:        "2022-04-14",
-		Proof:       "https://www.twitch.tv/videos/1455280672",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        6,
-		Racer:       "sisuka",
-		ProfileName: "sisuka",
-		Time:        2946,
-		Date:        "2022-05-17",
-		Proof:       "https://www.twitch.tv/videos/1486409779",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        7,
-		Racer:       "Cyber_1",
-		ProfileName: "Cyber_1",
-		Time:        2958,
-		Date:        "2022-08-23",
-		Proof:       "https://www.twitch.tv/videos/1570091193",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        8,
-		Racer:       "YuCaesar",
-		ProfileName: "YuCaesar",
-		Time:        2965,
-		Date:        "2022-04-26",
-		Proof:       "https://www.twitch.tv/videos/1467714564",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        9,
-		Racer:       "Adrayon",
-		ProfileName: "Adrayon",
-		Time:        2992,
-		Date:        "2022-06-12",
-		Proof:       "https://www.twitch.tv/videos/1501950411",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        10,
-		Racer:       "thereisnofuture",
-		ProfileName: "thereisnofuture",
-		Time:        3011,
-		Date:        "2022-03-21",
-		Proof:       "https://www.twitch.tv/videos/1432566879",
-		Site:        "Twitch",
-	},
-}
@@ -1,103 +0,0 @@
-package server
-
-var season3R7AB = []HallOfFameEntry{
-	{
-		Rank:        1,
-		Racer:       "Dea1h",
-		ProfileName: "Dea1h",
-		Time:        3797,
-		Date:        "2017-12-04",
-		Proof:       "https://www.twitch.tv/videos/206706029",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        2,
-		Racer:       "ReidMercury__",
-		ProfileName: "ReidMercury__",
-		Time:        3844,
-		Date:        "2017-11-28",
-		Proof:       "https://www.twitch.tv/videos/205034914",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        3,
-		Racer:       "bmzloop",
-		ProfileName: "Loopy",
-		Time:        4091,
-		Date:        "2017-12-15",
-		Proof:       "https://www.youtube.com/watch?v=g02cqQsrH1M",
-		Site:        "YouTube",
-	},
-
-	{
-		Rank:        4,
-		Racer:       "Shigan",
-		ProfileName: "Shigan",
-		Time:        4130,
-		Date:        "2018-01-19",
-		Proof:       "https://www.twitch.tv/videos/220225421",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        5,
-		Racer:       "CrafterLynx",
-		ProfileName: "CrafterLynx",
-		Time:        4133,
-		Date:        "2018-01-16",
-		Proof:       "https://www.twitch.tv/videos/219308798",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        6,
-		Racer:       "SapphireHX",
-		ProfileName: "Sapphire",
-		Time:        4249,
-		Date:        "2018-02-06",
-		Proof:       "https://www.youtube.com/watch?v=Zw2Ot5hjgZQ",
-		Site:        "YouTube",
-	},
-
-	{
-		Rank:        7,
-		Racer:       "MoucheronQuipet",
-		ProfileName: "MoucheronQuipet",
-		Time:        4269,
-		Date:        "2018-03-14",
-		Proof:       "https://www.youtube.com/watch?v=gMT-caoKJE0",
-		Site:        "YouTube",
-	},
-
-	{
-		Rank:        8,
-		Racer:       "leo_ze_tron",
-		ProfileName: "leo_ze_tron",
-		Time:        4290,
-		Date:        "2018-03-08",
-		Proof:       "https://www.twitch.tv/videos/236448621",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        9,
-		Racer:       "Zamiel",
-		ProfileName: "Zamiel",
-		Time:        4362,
-		Date:        "2017-12-13",
-		Proof:       "https://www.twitch.tv/videos/209120653",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        10,
-		Racer:       "ceehe",
-		ProfileName: "ceehe",
-		Time:        4448,
-		Date:        "2017-12-13",
-		Proof:       "https://www.twitch.tv/videos/209219042",
-		Site:        "Twitch",
-	},
-}
@@ -1,103 +0,0 @@
-package server
-
-var season4R7AB = []HallOfFameEntry{
-	{
-		Rank:        1,
-		Racer:       "antizoubilamakA",
-		ProfileName: "CRAZYEIGHTSFAN69",
-		Time:        3406,
-		Date:        "2018-09-07",
-		Proof:       "https://www.twitch.tv/videos/307245073",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        2,
-		Racer:       "Cyber_1",
-		ProfileName: "Cyber_1",
-		Time:        3475,
-		Date:        "2018-05-11",
-		Proof:       "https://www.twitch.tv/videos/260283073",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        3,
-		Racer:       "leo_ze_tron",
-		ProfileName: "leo_ze_tron",
-		Time:        3522,
-		Date:        "2018-07-11",
-		Proof:       "https://www.twitch.tv/videos/283570282",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        4,
-		Racer:       "thereisnofuture",
-		ProfileName: "thereisnofuture",
-		Time:        3543,
-		Date:        "2018-08-31",
-		Proof:       "https://www.twitch.tv/videos/304179755",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        5,
-		Racer:       "Shigan",
-		ProfileName: "Shigan",
-		Time:        3617,
-		Date:        "2018-10-29",
-		Proof:       "https://www.twitch.tv/videos/328752825",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        6,
-		Racer:       "ReidMercury__",
-		ProfileName: "ReidMercury__",
-		Time:        3622,
-		Date:        "2018-05-07",
-		Proof:       "https://www.twitch.tv/videos/258743172",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        7,
-		Racer:       "ceehe",
-		ProfileName: "ceehe",
-		Time:        3727,
-		Date:        "2018-04-22",
-		Proof:       "https://www.twitch.tv/videos/252823258",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        8,
-		Racer:       "CrafterLynx",
-		ProfileName: "CrafterLynx",
-		Time:        3776,
-		Date:        "2018-09-29",
-		Proof:       "https://www.twitch.tv/videos/316373160",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        9,
-		Racer:       "bmzloop",
-		ProfileName: "Loopy",
-		Time:        3788,
-		Date:        "2018-04-19",
-		Proof:       "https://www.twitch.tv/videos/251906525",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        10,
-		Racer:       "thisguyisbarry",
-		ProfileName: "thisguyisbarry",
-		Time:        3814,
-		Date:        "2018-05-22",
-		Proof:       "https://www.twitch.tv/videos/264286364",
-		Site:        "Twitch",
-	},
-}
@@ -1,103 +0,0 @@
-package server
-
-var season5R7AB = []HallOfFameEntry{
-	{
-		Rank:        1,
-		Racer:       "Dea1h",
-		ProfileName: "Dea1h",
-		Time:        3773,
-		Date:        "2018-12-11",
-		Proof:       "https://www.twitch.tv/videos/347393032",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        2,
-		Racer:       "Cyber_1",
-		ProfileName: "Cyber_1",
-		Time:        3806,
-		Date:        "2019-01-11",
-		Proof:       "https://www.twitch.tv/videos/365298470",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        3,
-		Racer:       "CrafterLynx",
-		ProfileName: "CrafterLynx",
-		Time:        3857,
-		Date:        "2019-01-23",
-		Proof:       "https://www.twitch.tv/videos/368584877",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        4,
-		Racer:       "thereisnofuture",
-		ProfileName: "thereisnofuture",
-		Time:        3871,
-		Date:        "2018-12-05",
-		Proof:       "https://www.twitch.tv/thereisnofuture/v/344976260",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        5,
-		Racer:       "mgln",
-		ProfileName: "mgln",
-		Time:        3929,
-		Date:        "2019-04-21",
-		Proof:       "https://www.youtube.com/watch?v=LUjetqZ7O9I",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        6,
-		Racer:       "sisuka",
-		ProfileName: "sisuka",
-		Time:        4172,
-		Date:        "2018-12-23",
-		Proof:       "https://www.youtube.com/watch?v=Rth9ITDWZ4o",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        7,
-		Racer:       "Shigan",
-		ProfileName: "Shigan",
-		Time:        4228,
-		Date:        "2019-03-12",
-		Proof:       "https://www.twitch.tv/videos/394474921",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        8,
-		Racer:       "Zamiel",
-		ProfileName: "Zamiel",
-		Time:        4231,
-		Date:        "2018-11-22",
-		Proof:       "https://www.twitch.tv/videos/339146750",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        9,
-		Racer:       "Gamonymous",
-		ProfileName: "Gamonymous",
-		Time:        4293,
-		Date:        "2019-01-23",
-		Proof:       "https://www.twitch.tv/gamonymous__/v/368290597",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        10,
-		Racer:       "SlashSP",
-		ProfileName: "SlashSP",
-		Time:        4305,
-		Date:        "2018-12-15",
-		Proof:       "https://www.twitch.tv/videos/349688950",
-		Site:        "Twitch",
-	},
-}
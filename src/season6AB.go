@@ -1,103 +0,0 @@
-package server
-
-var season6R7AB = []HallOfFameEntry{
-	{
-		Rank:        1,
-		Racer:       "Shigan",
-		ProfileName: "Shigan",
-		Time:        2644,
-		Date:        "2019-06-05",
-		Proof:       "https://www.twitch.tv/videos/434894359",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        2,
-		Racer:       "thereisnofuture",
-		ProfileName: "thereisnofuture",
-		Time:        2754,
-		Date:        "2019-07-09",
-		Proof:       "https://www.twitch.tv/videos/450425805",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        3,
-		Racer:       "Greg",
-		ProfileName: "Greg570",
-		Time:        2790,
-		Date:        "2019-09-24",
-		Proof:       "https://www.twitch.tv/videos/486128516",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        4,
-		Racer:       "antizoubilamakA",
-		ProfileName: "CRAZYEIGHTSFAN69",
-		Time:        2813,
-		Date:        "2019-05-18",
-		Proof:       "https://www.twitch.tv/videos/426777217",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        5,
-		Racer:       "Gamonymous",
-		ProfileName: "Gamonymous",
-		Time:        2850,
-		Date:        "2019-06-10",
-		Proof:       "https://www.youtube.com/watch?v=nl_cvqtAjdg",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        6,
-		Racer:       "leo_ze_tron",
-		ProfileName: "leo_ze_tron",
-		Time:        2855,
-		Date:        "2019-09-17",
-		Proof:       "https://www.twitch.tv/videos/482932943",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        7,
-		Racer:       "mgln",
-		ProfileName: "mgln",
-		Time:        2912,
-		Date:        "2019-08-30",
-		Proof:       "https://www.twitch.tv/videos/474508067",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        8,
-		Racer:       "YuCaesar",
-		ProfileName: "YuCaesar",
-		Time:        3055,
-		Date:        "2019-10-16",
-		Proof:       "https://www.twitch.tv/videos/495431034",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        9,
-		Racer:       "sisuka",
-		ProfileName: "sisuka",
-		Time:        3062,
-		Date:        "2019-08-18",
-		Proof:       "https://www.youtube.com/watch?v=EaySvHPIQOw",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        10,
-		Racer:       "Pingouin23",
-		ProfileName: "Pingouin23",
-		Time:        3064,
-		Date:        "2019-10-13",
-		Proof:       "https://www.twitch.tv/videos/494086038",
-		Site:        "Twitch",
-	},
-}
@@ -1,103 +0,0 @@
-package server
-
-var season7R7AB = []HallOfFameEntry{
-	{
-		Rank:        1,
-		Racer:       "Dea1h",
-		ProfileName: "Dea1h",
-		Time:        2974,
-		Date:        "2019-12-01",
-		Proof:       "https://www.youtube.com/watch?v=F_I2SQ1uuGg",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        2,
-		Racer:       "thereisnofuture",
-		ProfileName: "thereisnofuture",
-		Time:        2981,
-		Date:        "2020-01-25",
-		Proof:       "https://www.twitch.tv/videos/541510966",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        3,
-		Racer:       "mgln",
-		ProfileName: "mgln",
-		Time:        2985,
-		Date:        "2020-03-05",
-		Proof:       "https://www.twitch.tv/videos/562138650",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        4,
-		Racer:       "Greg",
-		ProfileName: "Greg570",
-		Time:        2992,
-		Date:        "2019-12-04",
-		Proof:       "https://www.twitch.tv/videos/517220728",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        5,
-		Racer:       "antizoubilamakA",
-		ProfileName: "CRAZYEIGHTSFAN69",
-		Time:        3047,
-		Date:        "2019-11-04",
-		Proof:       "https://www.twitch.tv/videos/504118249",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        6,
-		Racer:       "sisuka",
-		ProfileName: "sisuka",
-		Time:        3103,
-		Date:        "2019-11-20",
-		Proof:       "https://www.youtube.com/watch?v=Peap-KVcCfs",
-		Site:        "YouTube",
-	},
-
-	{
-		Rank:        7,
-		Racer:       "CrafterLynx",
-		ProfileName: "CrafterLynx",
-		Time:        3112,
-		Date:        "2020-03-05",
-		Proof:       "https://www.twitch.tv/videos/562458758",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        8,
-		Racer:       "Pingouin23",
-		ProfileName: "Pingouin23",
-		Time:        3253,
-		Date:        "2019-12-04",
-		Proof:       "https://www.twitch.tv/videos/517005634",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        9,
-		Racer:       "tayu",
-		ProfileName: "tayu",
-		Time:        3299,
-		Date:        "2020-04-25",
-		Proof:       "https://www.youtube.com/watch?v=bVMGETluQXQ",
-		Site:        "YouTube",
-	},
-
-	{
-		Rank:        10,
-		Racer:       "Gamonymous",
-		ProfileName: "Gamonymous",
-		Time:        3313,
-		Date:        "2020-04-14",
-		Proof:       "https://www.youtube.com/watch?v=ArNq0dp5rs8",
-		Site:        "YouTube",
-	},
-}
@@ -1,103 +0,0 @@
-package server
-
-var season8R7AB = []HallOfFameEntry{
-	{
-		Rank:        1,
-		Racer:       "Dea1h",
-		ProfileName: "Dea1h",
-		Time:        3843,
-		Date:        "2020-05-22",
-		Proof:       "https://www.youtube.com/watch?v=qheqrQhaVaA",
-		Site:        "YouTube",
-	},
-
-	{
-		Rank:        2,
-		Racer:       "mgln",
-		ProfileName: "mgln",
-		Time:        3908,
-		Date:        "2020-05-20",
-		Proof:       "https://www.youtube.com/watch?v=Co6K3kV8J4s",
-		Site:        "YouTube",
-	},
-
-	{
-		Rank:        3,
-		Racer:       "Gamonymous",
-		ProfileName: "Gamonymous",
-		Time:        4037,
-		Date:        "2020-06-25",
-		Proof:       "https://www.youtube.com/watch?v=PPyiZHJsk_A",
-		Site:        "YouTube",
-	},
-
-	{
-		Rank:        4,
-		Racer:       "thereisnofuture",
-		ProfileName: "thereisnofuture",
-		Time:        4086,
-		Date:        "2020-05-15",
-		Proof:       "https://www.youtube.com/watch?v=hHLqcK9BZKA",
-		Site:        "YouTube",
-	},
-
-	{
-		Rank:        5,
-		Racer:       "sisuka",
-		ProfileName: "sisuka",
-		Time:        4269,
-		Date:        "2020-05-16",
-		Proof:       "https://www.twitch.tv/videos/622770475",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        6,
-		Racer:       "Pingouin23",
-		ProfileName: "Pingouin23",
-		Time:        4386,
-		Date:        "2020-10-20",
-		Proof:       "https://www.twitch.tv/videos/775990110",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        7,
-		Racer:       "Shigan",
-		ProfileName: "Shigan",
-		Time:        4707,
-		Date:        "2020-10-21",
-		Proof:       "https://www.youtube.com/watch?v=vWHFO7I0nB8",
-		Site:        "YouTube",
-	},
-
-	{
-		Rank:        8,
-		Racer:       "Anidalife",
-		ProfileName: "Anidalife",
-		Time:        4332,
-		Date:        "2020-06-13",
-		Proof:       "https://www.youtube.com/watch?v=Vf4kC_CUitQ",
-		Site:        "YouTube",
-	},
-
-	{
-		Rank:        9,
-		Racer:       "Adrayon",
-		ProfileName: "Adrayon",
-		Time:        4881,
-		Date:        "2020-06-21",
-		Proof:       "https://www.twitch.tv/videos/657464282",
-		Site:        "Twitch",
-	},
-
-	{
-		Rank:        10,
-		Racer:       "MoucheronQuipet",
-		ProfileName: "MoucheronQuipet",
-		Time:        4887,
-		Date:        "2020-05-04",
-		Proof:       "https://www.twitch.tv/videos/611042129",
-		Site:        "Twitch",
-	},
-}
@@ -0,0 +1,183 @@
+package server
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Zamiell/isaac-racing-server/models"
+)
+
+/*
+	Seasons are opened and closed based on the dates in the "seasons" table
+	When a ranked solo season closes, the leaderboard is copied into the hall of fame and the
+	ranked solo stats are reset
+*/
+
+const (
+	SeasonsCheckInterval = time.Minute
+)
+
+var (
+	// A copy of the "seasons" table, in the order that they should be displayed
+	seasons      = make([]models.Season, 0)
+	seasonsMutex = sync.Mutex{}
+)
+
+func seasonsInit() {
+	// The ranked solo leaderboard queries need to know the current season right away
+	seasonsCheck()
+
+	go func() {
+		for {
+			time.Sleep(SeasonsCheckInterval)
+			seasonsCheck()
+		}
+	}()
+}
+
+// Close any seasons that have ended and update the period of time that counts for the ranked solo
+// leaderboard
+func seasonsCheck() {
+	seasonsMutex.Lock()
+	defer seasonsMutex.Unlock()
+
+	var allSeasons []models.Season
+	if v, err := db.Seasons.GetAll(); err != nil {
+		logger.Error("Database error while getting the seasons:", err)
+		return
+	} else {
+		allSeasons = v
+	}
+
+	now := getTimestamp()
+	for i, season := range allSeasons {
+		if season.Category == string(SeasonCategoryRankedSolo) &&
+			!season.Archived &&
+			season.DatetimeEnd != 0 &&
+			season.DatetimeEnd <= now {
+
+			if seasonClose(season) {
+				allSeasons[i].Archived = true
+			}
+		}
+	}
+	seasons = allSeasons
+
+	// Ranked solo races count for the season that started most recently
+	// (after a season closes, the leaderboard is empty until the next one starts)
+	if season, ok := seasonGetLatestRankedSolo(now); ok {
+		models.SetRankedSoloSeason(season.DatetimeStart, season.DatetimeEnd)
+	}
+}
+
+// Copy the ranked solo leaderboard to the hall of fame and reset everyone's stats
+// Returns false if something went wrong, so that it can be tried again later
+func seasonClose(season models.Season) bool {
+	logger.Info("Closing season #" + strconv.Itoa(season.ID) + ": " + season.Name)
+
+	var leaderboard []models.LeaderboardRowUnseededSolo
+	if v, err := db.Users.GetLeaderboardRankedSolo(LeaderboardRankedSoloRacesNeeded); err != nil {
+		logger.Error("Database error while getting the ranked solo leaderboard:", err)
+		return false
+	} else {
+		leaderboard = v
+	}
+
+	entries := make([]models.HallOfFameEntry, 0, len(leaderboard))
+	for i, row := range leaderboard {
+		numRaces := row.NumRaces
+		if numRaces > NumRankedSoloRacesForAverage {
+			numRaces = NumRankedSoloRacesForAverage
+		}
+
+		// The hall of fame stores times in seconds
+		entries = append(entries, models.HallOfFameEntry{
+			Rank:              i + 1,
+			Racer:             row.Name,
+			ProfileName:       row.Name,
+			AdjustedAverage:   row.AdjustedAverage / 1000,
+			UnadjustedAverage: row.RealAverage / 1000,
+			ForfeitPenalty:    row.ForfeitPenalty / 1000,
+			NumForfeits:       row.NumForfeits,
+			NumRaces:          numRaces,
+		})
+	}
+
+	// Everyone starts from scratch in the next season
+	if err := db.Seasons.Close(season.ID, entries); err != nil {
+		logger.Error("Database error while closing season #"+strconv.Itoa(season.ID)+":", err)
+		return false
+	}
+	leaderboardJobsInvalidate(LeaderboardJobFormatRankedSolo, false)
+
+	logger.Info("Archived " + strconv.Itoa(len(entries)) + " hall of fame entries for season #" + strconv.Itoa(season.ID) + ".")
+	return true
+}
+
+// Get the ranked solo season that started most recently
+// (the second return value is false if there has never been a ranked solo season)
+func seasonGetLatestRankedSolo(now int64) (models.Season, bool) {
+	var latest models.Season
+	found := false
+	for _, season := range seasons {
+		if season.Category != string(SeasonCategoryRankedSolo) ||
+			season.DatetimeStart == 0 ||
+			season.DatetimeStart > now {
+
+			continue
+		}
+		if !found || season.DatetimeStart > latest.DatetimeStart {
+			latest = season
+			found = true
+		}
+	}
+
+	return latest, found
+}
+
+// Get the ranked solo season that is going on right now
+// (the second return value is false if there is not one)
+func seasonGetCurrentRankedSolo() (models.Season, bool) {
+	seasonsMutex.Lock()
+	defer seasonsMutex.Unlock()
+
+	now := getTimestamp()
+	season, ok := seasonGetLatestRankedSolo(now)
+	if !ok || !seasonIsOpen(season, now) {
+		return season, false
+	}
+
+	return season, true
+}
+
+// The second return value is false if the season does not exist
+func seasonsGet(seasonID int) (models.Season, bool) {
+	seasonsMutex.Lock()
+	defer seasonsMutex.Unlock()
+
+	for _, season := range seasons {
+		if season.ID == seasonID {
+			return season, true
+		}
+	}
+
+	return models.Season{}, false
+}
+
+// Seasons take submissions (or count ranked solo races) between their start and end dates
+func seasonIsOpen(season models.Season, now int64) bool {
+	return !season.Archived &&
+		(season.DatetimeStart == 0 || season.DatetimeStart <= now) &&
+		(season.DatetimeEnd == 0 || now < season.DatetimeEnd)
+}
+
+// Get a copy of every season
+func seasonsGetAll() []models.Season {
+	seasonsMutex.Lock()
+	defer seasonsMutex.Unlock()
+
+	allSeasons := make([]models.Season, len(seasons))
+	copy(allSeasons, seasons)
+	return allSeasons
+}
@@ -0,0 +1,496 @@
+package server
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync"
+
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	A series is a best-of-N match between the same racers (e.g. a tournament match)
+	Each race in a series is a normal race that points back to the series with "Race.SeriesID"
+
+	A series is touched by the goroutines of its races and by the goroutine that creates its next
+	race, so the fields that change are guarded by its own mutex
+*/
+
+const (
+	SeriesBestOfMax = 9
+)
+
+/*
+	Data structures
+*/
+
+type Series struct {
+	ID               int
+	Name             string
+	BestOf           int
+	RotateCharacters bool
+	RotateBuilds     bool
+	Captain          string
+
+	// The fields below can only be accessed while holding the mutex
+	// (the rest never change after the series is created)
+	Ruleset  Ruleset // The ruleset of the most recent race
+	NumRaces int
+	Entrants map[string]int // Indexed by racer name, values are user IDs (empty until the first race starts)
+	Scores   map[string]int // Indexed by racer name
+
+	// Set when the next race could not be created because none of the racers were online
+	// (it is created as soon as one of them connects)
+	WaitingForRacers bool
+	mutex            sync.Mutex
+}
+
+// Received in the "raceCreate" command
+type SeriesOptions struct {
+	BestOf           int  `json:"bestOf"` // 0 for a race that is not part of a series
+	RotateCharacters bool `json:"rotateCharacters"`
+	RotateBuilds     bool `json:"rotateBuilds"`
+}
+
+var (
+	allSeries      = make(map[int]*Series)
+	allSeriesMutex = new(sync.Mutex)
+)
+
+/*
+	Series map helpers
+*/
+
+func seriesGet(seriesID int) (*Series, bool) {
+	allSeriesMutex.Lock()
+	defer allSeriesMutex.Unlock()
+
+	series, ok := allSeries[seriesID]
+	return series, ok
+}
+
+func seriesAdd(series *Series) {
+	allSeriesMutex.Lock()
+	defer allSeriesMutex.Unlock()
+
+	allSeries[series.ID] = series
+}
+
+func seriesDelete(seriesID int) {
+	allSeriesMutex.Lock()
+	defer allSeriesMutex.Unlock()
+
+	delete(allSeries, seriesID)
+}
+
+/*
+	Series object methods
+*/
+
+// Only the racers that started the first race are allowed to join the rest of the races
+func (series *Series) IsEntrant(username string) bool {
+	series.mutex.Lock()
+	defer series.mutex.Unlock()
+
+	if len(series.Entrants) == 0 {
+		return true
+	}
+
+	_, ok := series.Entrants[username]
+	return ok
+}
+
+// Lock in the entrants when the first race of the series starts
+// (called from the "race.Start" function)
+func (series *Series) SetEntrants(race *Race) {
+	series.mutex.Lock()
+	defer series.mutex.Unlock()
+
+	if len(series.Entrants) > 0 {
+		return
+	}
+
+	for _, racer := range race.Racers {
+		series.Entrants[racer.Name] = racer.ID
+		series.Scores[racer.Name] = 0
+		if err := db.SeriesParticipants.Insert(series.ID, racer.ID); err != nil {
+			logger.Error("Database error while inserting the participants for series #"+strconv.Itoa(series.ID)+":", err)
+		}
+	}
+}
+
+// Get the ruleset for the next race in the series
+func (series *Series) GetNextRuleset() Ruleset {
+	series.mutex.Lock()
+	defer series.mutex.Unlock()
+
+	return raceRerollRuleset(series.Ruleset, series.RotateCharacters, series.RotateBuilds)
+}
+
+// Create the next race in the series and put all of the online entrants into it
+// This has to be done in a new goroutine since it needs the lobby lock
+// (and the race goroutine that just finished could otherwise deadlock with a lobby command)
+func (series *Series) CreateNextRace() {
+	lobbyMutex.Lock()
+	defer lobbyMutex.Unlock()
+
+	// A race that nobody is in would never be cleaned up
+	// (this always happens when the server first starts, since nobody is connected yet)
+	if !series.HasOnlineRacer() {
+		series.SetWaitingForRacers(true)
+		logger.Info("Waiting for a racer to come online before creating the next race for series #" + strconv.Itoa(series.ID) + ".")
+		return
+	}
+	series.SetWaitingForRacers(false)
+
+	ruleset := series.GetNextRuleset()
+	if v, err := json.Marshal(ruleset); err != nil {
+		logger.Error("Failed to marshal the ruleset for series #"+strconv.Itoa(series.ID)+":", err)
+	} else if err := db.Series.SetRuleset(series.ID, string(v)); err != nil {
+		logger.Error("Database error while setting the ruleset for series #"+strconv.Itoa(series.ID)+":", err)
+	}
+
+	series.mutex.Lock()
+	name := series.Name + " (race " + strconv.Itoa(series.NumRaces+1) + ")"
+	series.mutex.Unlock()
+
+	var race *Race
+	if v, err := raceCreateSub(name, ruleset, series.Captain, "", series); err != nil {
+		logger.Error("Failed to create the next race for series #"+strconv.Itoa(series.ID)+":", err)
+		return
+	} else {
+		race = v
+	}
+	logger.Info("Created race " + strconv.Itoa(race.ID) + " for series #" + strconv.Itoa(series.ID) + ".")
+
+	race.Send(func() {
+		race.SendCreated()
+
+		for _, racerName := range series.GetRacerNames() {
+			// Not all entrants may be online between races
+			var s *melody.Session
+			if v, ok := websocketGetSession(racerName); !ok {
+				continue
+			} else {
+				s = v
+			}
+
+			d := &IncomingWebsocketData{
+				Command: "raceJoin",
+				ID:      race.ID,
+			}
+			if !websocketGetSessionValues(s, d) {
+				continue
+			}
+			websocketRaceJoin(s, d)
+		}
+
+		// Everyone might have gone offline in the meantime
+		if len(race.Racers) == 0 {
+			race.Delete()
+			series.RemoveRace()
+			series.SetWaitingForRacers(true)
+		}
+	})
+}
+
+func (series *Series) Finish(winner string) {
+	logger.Info("Series #" + strconv.Itoa(series.ID) + " was won by \"" + winner + "\".")
+	seriesDelete(series.ID)
+
+	series.mutex.Lock()
+	winnerID := series.Entrants[winner]
+	series.mutex.Unlock()
+
+	if err := db.Series.Finish(series.ID, winnerID); err != nil {
+		logger.Error("Database error while finishing series #"+strconv.Itoa(series.ID)+":", err)
+	}
+
+	series.SendUpdate(winner)
+}
+
+// Called when the last racer leaves a race in the series before it starts
+func (series *Series) Abandon() {
+	logger.Info("Series #" + strconv.Itoa(series.ID) + " was abandoned.")
+	seriesDelete(series.ID)
+
+	if err := db.Series.Abandon(series.ID); err != nil {
+		logger.Error("Database error while abandoning series #"+strconv.Itoa(series.ID)+":", err)
+	}
+
+	msg := series.GetMessage()
+	msg.Abandoned = true
+	for _, s := range websocketGetAllSessions() {
+		websocketEmit(s, "seriesUpdate", msg)
+	}
+}
+
+// Get the names of the people who go into the next race, so that they can be looped over without
+// holding the lock
+// (before the first race starts, this is only the captain)
+func (series *Series) GetRacerNames() []string {
+	series.mutex.Lock()
+	defer series.mutex.Unlock()
+
+	if len(series.Entrants) == 0 {
+		return []string{series.Captain}
+	}
+
+	names := make([]string, 0, len(series.Entrants))
+	for racerName := range series.Entrants {
+		names = append(names, racerName)
+	}
+	return names
+}
+
+func (series *Series) HasOnlineRacer() bool {
+	for _, racerName := range series.GetRacerNames() {
+		if _, ok := websocketGetSession(racerName); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (series *Series) SetWaitingForRacers(waiting bool) {
+	series.mutex.Lock()
+	defer series.mutex.Unlock()
+
+	series.WaitingForRacers = waiting
+}
+
+// Called when a race in the series is deleted before anyone joins it
+func (series *Series) RemoveRace() {
+	series.mutex.Lock()
+	defer series.mutex.Unlock()
+
+	series.NumRaces--
+}
+
+// Record a new race in the series
+// (called from the "raceCreateSub" function)
+func (series *Series) AddRace(raceID int, ruleset Ruleset) error {
+	series.mutex.Lock()
+	defer series.mutex.Unlock()
+
+	series.NumRaces++
+	series.Ruleset = ruleset
+	return db.SeriesRaces.Insert(series.ID, raceID, series.NumRaces)
+}
+
+// Let everyone know the current score
+func (series *Series) SendUpdate(winner string) {
+	msg := series.GetMessage()
+	msg.Winner = winner
+	for _, s := range websocketGetAllSessions() {
+		websocketEmit(s, "seriesUpdate", msg)
+	}
+}
+
+func (series *Series) GetMessage() *SeriesMessage {
+	series.mutex.Lock()
+	defer series.mutex.Unlock()
+
+	scores := make(map[string]int)
+	for racerName, score := range series.Scores {
+		scores[racerName] = score
+	}
+
+	return &SeriesMessage{
+		ID:      series.ID,
+		Name:    series.Name,
+		BestOf:  series.BestOf,
+		RaceNum: series.NumRaces,
+		Scores:  scores,
+	}
+}
+
+/*
+	Series subroutines
+*/
+
+// Create the next race of any series that was waiting for this person to come online
+// (called from the "websocketHandleConnect" function)
+func seriesRacerConnected(username string) {
+	waitingSeries := make([]*Series, 0)
+	allSeriesMutex.Lock()
+	for _, series := range allSeries {
+		series.mutex.Lock()
+		waiting := series.WaitingForRacers
+		series.mutex.Unlock()
+		if !waiting {
+			continue
+		}
+
+		for _, racerName := range series.GetRacerNames() {
+			if racerName == username {
+				series.SetWaitingForRacers(false)
+				waitingSeries = append(waitingSeries, series)
+				break
+			}
+		}
+	}
+	allSeriesMutex.Unlock()
+
+	for _, series := range waitingSeries {
+		go series.CreateNextRace()
+	}
+}
+
+// Create a new series (before its first race is created)
+// (called from the "websocketRaceCreate" function)
+func seriesCreate(name string, ruleset Ruleset, options SeriesOptions, captainID int, captain string) (*Series, error) {
+	var rulesetJSON []byte
+	if v, err := json.Marshal(ruleset); err != nil {
+		return nil, err
+	} else {
+		rulesetJSON = v
+	}
+
+	var seriesID int
+	if v, err := db.Series.Insert(
+		name,
+		options.BestOf,
+		string(rulesetJSON),
+		options.RotateCharacters,
+		options.RotateBuilds,
+		captainID,
+	); err != nil {
+		return nil, err
+	} else {
+		seriesID = v
+	}
+
+	series := &Series{
+		ID:               seriesID,
+		Name:             name,
+		BestOf:           options.BestOf,
+		Ruleset:          ruleset,
+		RotateCharacters: options.RotateCharacters,
+		RotateBuilds:     options.RotateBuilds,
+		Captain:          captain,
+		NumRaces:         0, // Incremented when the race is created
+		Entrants:         make(map[string]int),
+		Scores:           make(map[string]int),
+	}
+	seriesAdd(series)
+	logger.Info("Created series #" + strconv.Itoa(series.ID) + " (best of " + strconv.Itoa(series.BestOf) + ").")
+
+	return series, nil
+}
+
+// Update the score after one of the races in a series finishes
+// (called from the "race.Finish" function)
+func seriesRaceFinished(race *Race) {
+	var series *Series
+	if v, ok := seriesGet(race.SeriesID); !ok {
+		return
+	} else {
+		series = v
+	}
+
+	// The winner of the race gets a point
+	// (if everyone quit, then nobody gets a point)
+	series.mutex.Lock()
+	for _, racer := range race.Racers {
+		if racer.Place != 1 {
+			continue
+		}
+
+		series.Scores[racer.Name]++
+		if err := db.SeriesParticipants.IncrementScore(series.ID, racer.ID); err != nil {
+			logger.Error("Database error while incrementing the score for series #"+strconv.Itoa(series.ID)+":", err)
+		}
+	}
+
+	// Check to see if someone has won the series
+	winsNeeded := series.BestOf/2 + 1
+	winner := ""
+	for racerName, score := range series.Scores {
+		if score >= winsNeeded {
+			winner = racerName
+		}
+	}
+	series.mutex.Unlock()
+
+	if winner != "" {
+		series.Finish(winner)
+		return
+	}
+
+	series.SendUpdate("")
+	go series.CreateNextRace()
+}
+
+func seriesValidateOptions(s *melody.Session, d *IncomingWebsocketData) bool {
+	options := d.Series
+
+	if options.BestOf < 3 || options.BestOf > SeriesBestOfMax || options.BestOf%2 == 0 {
+		websocketWarning(s, d.Command, "A series must be a best of 3, 5, 7, or 9.")
+		return false
+	}
+
+	if d.Ruleset.Solo {
+		websocketWarning(s, d.Command, "Solo races cannot be part of a series.")
+		return false
+	}
+
+	if d.Ruleset.TeamSize != 0 {
+		websocketWarning(s, d.Command, "Team races cannot be part of a series.")
+		return false
+	}
+
+	if options.RotateBuilds && d.Ruleset.Format != RaceFormatSeeded {
+		websocketWarning(s, d.Command, "You can only rotate starting builds in a seeded series.")
+		return false
+	}
+
+	return true
+}
+
+// Load all of the series that were being played when the server was last shut down
+// (called from the "Init" function, after the races are restored)
+func seriesRestoreAll() {
+	allSeriesRows, err := db.Series.GetAllUnfinished()
+	if err != nil {
+		logger.Fatal("Failed to get the unfinished series:", err)
+		return
+	}
+
+	for _, row := range allSeriesRows {
+		series := &Series{
+			ID:               row.ID,
+			Name:             row.Name,
+			BestOf:           row.BestOf,
+			RotateCharacters: row.RotateCharacters,
+			RotateBuilds:     row.RotateBuilds,
+			Captain:          row.Captain,
+			NumRaces:         row.NumRaces,
+			Entrants:         row.UserIDs,
+			Scores:           row.Scores,
+		}
+		if err := json.Unmarshal([]byte(row.Ruleset), &series.Ruleset); err != nil {
+			logger.Error("Failed to unmarshal the ruleset for series #"+strconv.Itoa(series.ID)+":", err)
+			continue
+		}
+		seriesAdd(series)
+
+		// If the current race of the series was restored, it will continue like normal
+		// (the series ID of a race never changes, so it is safe to read it here)
+		currentRaceExists := false
+		for _, race := range racesGetAll() {
+			if race.SeriesID == series.ID {
+				currentRaceExists = true
+				break
+			}
+		}
+
+		// Otherwise, it was an open race that was deleted during the cleanup, so make it again
+		if !currentRaceExists {
+			series.CreateNextRace()
+		}
+
+		logger.Info("Restored series #" + strconv.Itoa(series.ID) + ".")
+	}
+}
@@ -151,6 +151,14 @@ func twitchConnect(oauthToken string) {
 				twitchSend(channel, "Racing+ is a mod for The Binding of Isaac: Repentance: https://isaacracing.net", 0)
 				// } else if message == "!left" {
 				// } else if message == "!entrants" {
+			} else if message == "!h2h" {
+				// The arguments are the rest of the message
+				// (this queries the database, so do it in a new goroutine to avoid holding up the
+				// IRC connection)
+				args := strings.Fields(strings.Join(msgParts[5:], " "))
+				go func() {
+					twitchSend(channel, headToHeadChatCommand(args), 0)
+				}()
 			}
 		}
 	}
@@ -183,7 +191,7 @@ func twitchNotMod(channel string) {
 	}
 
 	// If the user is online, send them a warning to let them know what happened
-	if s, ok := websocketSessions[username]; ok {
+	if s, ok := websocketGetSession(username); ok {
 		websocketWarning(s, "twitchNotMod", "The Twitch bot has been disabled for your account because it is not a moderator in your channel. Please type <code>/mod IsaacRacingPlus</code> in your Twitch chat, wait a few minutes, and then check the box again in the Racing+ settings.")
 	}
 }
@@ -263,7 +271,7 @@ func twitchRacerSend(username string, message string) {
 		return
 	}
 
-	s, ok := websocketSessions[username]
+	s, ok := websocketGetSession(username)
 	if !ok {
 		// Don't bother sending anything to their Twitch chat if they are not even connected
 		return
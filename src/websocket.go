@@ -14,17 +14,30 @@ var (
 	m *melody.Melody
 
 	// We keep track of all WebSocket sessions
-	websocketSessions = make(map[string]*melody.Session)
+	websocketSessions      = make(map[string]*melody.Session)
+	websocketSessionsMutex = new(sync.RWMutex)
+
+	// Melody does not guard the values stored on a session,
+	// but race goroutines need to read the values of other sessions
+	websocketSessionValuesMutex = new(sync.RWMutex)
 
 	// We keep track of which chat rooms exist and which users are in each room
-	chatRooms = make(map[string][]User)
+	chatRooms      = make(map[string][]User)
+	chatRoomsMutex = new(sync.Mutex)
 
 	// Used to store all of the functions that handle each command
-	commandHandlerMap = make(map[string]func(*melody.Session, *IncomingWebsocketData))
-
-	// The WebSocket server needs to processes one action at a time;
-	// otherwise, there would be chaos
-	commandMutex = new(sync.Mutex)
+	// - Lobby commands are processed one at a time (guarded by "lobbyMutex")
+	// - Chat commands only touch the chat rooms, which are guarded by "chatRoomsMutex"
+	// - Race commands are sent to the goroutine of the race that they refer to
+	//   (in "raceActor.go")
+	// - Latency commands only touch the values of their own session, which are guarded by
+	//   "websocketSessionValuesMutex"
+	commandHandlerMap        = make(map[string]func(*melody.Session, *IncomingWebsocketData))
+	chatCommandHandlerMap    = make(map[string]func(*melody.Session, *IncomingWebsocketData))
+	raceCommandHandlerMap    = make(map[string]func(*melody.Session, *IncomingWebsocketData))
+	latencyCommandHandlerMap = make(map[string]func(*melody.Session, *IncomingWebsocketData))
+
+	lobbyMutex = new(sync.Mutex)
 )
 
 func websocketInit() {
@@ -33,33 +46,68 @@ func websocketInit() {
 	*/
 
 	// Room (chat) commands
-	commandHandlerMap["roomJoin"] = websocketRoomJoin
-	commandHandlerMap["roomLeave"] = websocketRoomLeave
-	commandHandlerMap["roomMessage"] = websocketRoomMessage
-	commandHandlerMap["privateMessage"] = websocketPrivateMessage
+	chatCommandHandlerMap["roomJoin"] = websocketRoomJoin
+	chatCommandHandlerMap["roomLeave"] = websocketRoomLeave
+	chatCommandHandlerMap["roomMessage"] = websocketRoomMessage
+	chatCommandHandlerMap["privateMessage"] = websocketPrivateMessage
+
+	// Latency commands
+	latencyCommandHandlerMap["pong"] = websocketPong
 
 	// Race commands
+	// (creating a race is a lobby command since the race does not exist yet)
+	// (asking for a rematch is a lobby command since the old race no longer exists)
 	commandHandlerMap["raceCreate"] = websocketRaceCreate
-	commandHandlerMap["raceJoin"] = websocketRaceJoin
-	commandHandlerMap["raceLeave"] = websocketRaceLeave
-	commandHandlerMap["raceReady"] = websocketRaceReady
-	commandHandlerMap["raceUnready"] = websocketRaceUnready
-	commandHandlerMap["raceFinish"] = websocketRaceFinish
-	commandHandlerMap["raceQuit"] = websocketRaceQuit
-	commandHandlerMap["raceSeed"] = websocketRaceSeed
-	commandHandlerMap["raceFloor"] = websocketRaceFloor
-	commandHandlerMap["raceItem"] = websocketRaceItem
-	commandHandlerMap["raceRoom"] = websocketRaceRoom
+	commandHandlerMap["raceRematch"] = websocketRaceRematch
+	raceCommandHandlerMap["raceJoin"] = websocketRaceJoin
+	raceCommandHandlerMap["raceLeave"] = websocketRaceLeave
+	raceCommandHandlerMap["raceReady"] = websocketRaceReady
+	raceCommandHandlerMap["raceUnready"] = websocketRaceUnready
+	raceCommandHandlerMap["raceFinish"] = websocketRaceFinish
+	raceCommandHandlerMap["raceQuit"] = websocketRaceQuit
+	raceCommandHandlerMap["raceSeed"] = websocketRaceSeed
+	raceCommandHandlerMap["raceFloor"] = websocketRaceFloor
+	raceCommandHandlerMap["raceItem"] = websocketRaceItem
+	raceCommandHandlerMap["raceRoom"] = websocketRaceRoom
+	raceCommandHandlerMap["raceSpectate"] = websocketRaceSpectate
+	raceCommandHandlerMap["raceUnspectate"] = websocketRaceUnspectate
+	raceCommandHandlerMap["raceKick"] = websocketRaceKick
+	raceCommandHandlerMap["raceTransferCaptain"] = websocketRaceTransferCaptain
+	raceCommandHandlerMap["raceLock"] = websocketRaceLock
+
+	// Challenge commands
+	// (starting a challenge is a lobby command since the race does not exist yet)
+	commandHandlerMap["challengeStart"] = websocketChallengeStart
+
+	// Queue commands
+	commandHandlerMap["queueJoin"] = websocketQueueJoin
+	commandHandlerMap["queueLeave"] = websocketQueueLeave
 
 	// Profile commands
 	commandHandlerMap["profileSetStream"] = websocketProfileSetStream
 
+	// Hall of fame commands
+	commandHandlerMap["hallOfFameSubmit"] = websocketHallOfFameSubmit
+
 	// Admin commands
 	commandHandlerMap["adminMessage"] = websocketAdminMessage
 	commandHandlerMap["adminShutdown"] = websocketAdminShutdown
 	commandHandlerMap["adminUnshutdown"] = websocketAdminUnshutdown
 	commandHandlerMap["adminBan"] = websocketAdminBan
 	commandHandlerMap["adminUnban"] = websocketAdminUnban
+	commandHandlerMap["adminDisqualify"] = websocketAdminDisqualify
+	commandHandlerMap["adminDisqualifyFinished"] = websocketAdminDisqualifyFinished
+	commandHandlerMap["adminFlags"] = websocketAdminFlags
+	commandHandlerMap["adminResolveFlag"] = websocketAdminResolveFlag
+	commandHandlerMap["adminCompareTrueSkill"] = websocketAdminCompareTrueSkill
+	commandHandlerMap["adminLeaderboardJobStart"] = websocketAdminLeaderboardJobStart
+	commandHandlerMap["adminLeaderboardJobs"] = websocketAdminLeaderboardJobs
+	commandHandlerMap["adminLeaderboardJobDiff"] = websocketAdminLeaderboardJobDiff
+	commandHandlerMap["adminLeaderboardJobApply"] = websocketAdminLeaderboardJobApply
+	commandHandlerMap["adminLeaderboardJobDiscard"] = websocketAdminLeaderboardJobDiscard
+	commandHandlerMap["adminHallOfFameSubmissions"] = websocketAdminHallOfFameSubmissions
+	commandHandlerMap["adminReviewHallOfFame"] = websocketAdminReviewHallOfFame
+	commandHandlerMap["adminSeasonOpen"] = websocketAdminSeasonOpen
 	/*
 		commandHandlerMap["adminBanIP"] = websocketAdminBanIP
 		commandHandlerMap["adminUnbanIP"] = websocketAdminUnbanIP
@@ -89,6 +137,9 @@ func websocketInit() {
 
 // Get all values from the session and fill in the IncomingWebsocketData object
 func websocketGetSessionValues(s *melody.Session, d *IncomingWebsocketData) bool {
+	websocketSessionValuesMutex.RLock()
+	defer websocketSessionValuesMutex.RUnlock()
+
 	/*
 		Get the values from the session
 	*/
@@ -185,6 +236,53 @@ func websocketGetSessionValues(s *melody.Session, d *IncomingWebsocketData) bool
 	return true
 }
 
+// Store a value on a session
+// (this must be used instead of "s.Set()" since other goroutines may be reading the session)
+func websocketSetSessionValue(s *melody.Session, key string, value interface{}) {
+	websocketSessionValuesMutex.Lock()
+	defer websocketSessionValuesMutex.Unlock()
+
+	s.Set(key, value)
+}
+
+// Get a single value from a session
+// (this must be used instead of "s.Get()" since other goroutines may be writing to the session)
+func websocketGetSessionValue(s *melody.Session, key string) (interface{}, bool) {
+	websocketSessionValuesMutex.RLock()
+	defer websocketSessionValuesMutex.RUnlock()
+
+	return s.Get(key)
+}
+
+// Get the session for a user who is currently online
+func websocketGetSession(username string) (*melody.Session, bool) {
+	websocketSessionsMutex.RLock()
+	defer websocketSessionsMutex.RUnlock()
+
+	s, ok := websocketSessions[username]
+	return s, ok
+}
+
+// Get the sessions for every user who is currently online
+func websocketGetAllSessions() []*melody.Session {
+	websocketSessionsMutex.RLock()
+	defer websocketSessionsMutex.RUnlock()
+
+	sessions := make([]*melody.Session, 0, len(websocketSessions))
+	for _, s := range websocketSessions {
+		sessions = append(sessions, s)
+	}
+
+	return sessions
+}
+
+func websocketCountSessions() int {
+	websocketSessionsMutex.RLock()
+	defer websocketSessionsMutex.RUnlock()
+
+	return len(websocketSessions)
+}
+
 // Send a message to a client using the Golem-style protocol described above
 func websocketEmit(s *melody.Session, command string, d interface{}) {
 	// Convert the data to JSON
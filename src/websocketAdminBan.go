@@ -77,27 +77,19 @@ func websocketAdminBan(s *melody.Session, d *IncomingWebsocketData) {
 	}
 
 	// Find out if the banned user is in any races that are currently going on
-	for _, race := range races {
-		for _, racer := range race.Racers {
-			if racer.ID == recipientID {
-				/*
-					Disqualify
-				*/
-
-				racer.Place = -2
-				racer.PlaceMid = -1
-				race.SetRacerStatus(username, RacerStatusDisqualified)
-				racer.DatetimeFinished = getTimestamp()
-				racer.RunTime = racer.DatetimeFinished - race.DatetimeStarted
-				race.SetAllPlaceMid()
-				twitchRacerQuit(race, racer)
-				race.CheckFinish()
+	for _, race := range racesGetAll() {
+		// The race fields can only be safely modified from the race goroutine
+		race.Call(func() {
+			for _, racer := range race.Racers {
+				if racer.ID == recipientID && race.Status == RaceStatusInProgress {
+					race.Disqualify(racer, "banned")
+				}
 			}
-		}
+		})
 	}
 
 	// Boot them offline if they are currently connected
-	if s2, ok := websocketSessions[recipient]; ok {
+	if s2, ok := websocketGetSession(recipient); ok {
 		websocketError(
 			s2,
 			"Banned",
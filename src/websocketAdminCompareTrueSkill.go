@@ -0,0 +1,91 @@
+package server
+
+import (
+	"math"
+	"strconv"
+
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	Command example:
+	adminCompareTrueSkill {
+		format: "seeded", // Either "unseeded", "seeded", or "diversity"
+		engine: "glicko2", // Optional; defaults to the engine that the format currently uses
+	}
+*/
+
+func websocketAdminCompareTrueSkill(s *melody.Session, d *IncomingWebsocketData) {
+	username := d.v.Username
+	admin := d.v.Admin
+	format := d.Format
+	engineName := d.Engine
+
+	/*
+		Validation
+	*/
+
+	// Validate that the user is an admin
+	if admin == 0 {
+		logger.Warning("User \"" + username + "\" tried to compare the TrueSkill leaderboard, but they are not an administrator.")
+		websocketError(s, d.Command, "Only administrators can do that.")
+		return
+	}
+
+	// Validate the format
+	if format != RaceFormatUnseeded && format != RaceFormatSeeded && format != RaceFormatDiversity {
+		websocketWarning(s, d.Command, "That is not a valid format.")
+		return
+	}
+
+	// Validate the rating engine
+	engine := ratingEngineGetFormat(format)
+	if engineName != "" {
+		if v, ok := RatingEngineGet(engineName); !ok {
+			websocketWarning(s, d.Command, "That is not a valid rating engine.")
+			return
+		} else {
+			engine = v
+		}
+	}
+
+	/*
+		Compare
+	*/
+
+	// This goes through every race, so do it in a new goroutine to avoid holding up the lobby
+	logger.Info("User \"" + username + "\" started a TrueSkill comparison for " + string(format) + " with the " + engine.Name() + " rating engine.")
+	go func() {
+		comparisons, err := leaderboardCompareTrueSkill(format, engine)
+		if err != nil {
+			logger.Error("Database error while comparing the TrueSkill leaderboard:", err)
+			websocketError(s, d.Command, "")
+			return
+		}
+
+		totalDifference := 0.0
+		for _, comparison := range comparisons {
+			totalDifference += math.Abs(comparison.Difference)
+		}
+		averageDifference := 0.0
+		if len(comparisons) > 0 {
+			averageDifference = totalDifference / float64(len(comparisons))
+		}
+		logger.Info("The TrueSkill comparison for " + string(format) + " found " + strconv.Itoa(len(comparisons)) + " player(s) with an average change of " + strconv.FormatFloat(averageDifference, 'f', 2, 64) + ".")
+
+		// When the engine is different from the current one, the ratings are on different scales,
+		// so the ranks should be compared instead
+		type AdminCompareTrueSkillMessage struct {
+			Format            RaceFormat            `json:"format"`
+			Engine            string                `json:"engine"`
+			AverageDifference float64               `json:"averageDifference"`
+			Players           []TrueSkillComparison `json:"players"`
+		}
+		websocketEmit(s, "adminCompareTrueSkill", &AdminCompareTrueSkillMessage{
+			Format:            format,
+			Engine:            engine.Name(),
+			AverageDifference: averageDifference,
+			Players:           comparisons,
+		})
+	}()
+}
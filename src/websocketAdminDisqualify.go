@@ -0,0 +1,88 @@
+package server
+
+import (
+	"strconv"
+
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	Command example:
+	adminDisqualify {
+		id: 123,
+		name: "Zamiel",
+		comment: "used a glitch",
+	}
+*/
+
+func websocketAdminDisqualify(s *melody.Session, d *IncomingWebsocketData) {
+	username := d.v.Username
+	admin := d.v.Admin
+	recipient := d.Name
+	reason := d.Comment
+
+	/*
+		Validation
+	*/
+
+	// Validate that the user is an admin
+	if admin == 0 {
+		logger.Warning("User \"" + username + "\" tried to disqualify \"" + recipient + "\", but they are not an administrator.")
+		websocketError(s, d.Command, "Only administrators can do that.")
+		return
+	}
+
+	// Validate that the race exists
+	var race *Race
+	if v, ok := racesGet(d.ID); !ok {
+		websocketWarning(s, d.Command, "Race ID "+strconv.Itoa(d.ID)+" is not an ongoing race. (Use \"adminDisqualifyFinished\" for races that are already over.)")
+		return
+	} else {
+		race = v
+	}
+
+	// The race fields can only be safely modified from the race goroutine
+	disqualified := false
+	race.Call(func() {
+		// Validate that the race has started
+		if race.Status != RaceStatusInProgress {
+			websocketWarning(s, d.Command, "You can only disqualify people from races that are in progress.")
+			return
+		}
+
+		// Validate that the requested person is in the race
+		var racer *Racer
+		if v, ok := race.Racers[recipient]; !ok {
+			websocketWarning(s, d.Command, "\""+recipient+"\" is not in race ID "+strconv.Itoa(race.ID)+".")
+			return
+		} else {
+			racer = v
+		}
+
+		// Validate that they are not already disqualified
+		if racer.Status == RacerStatusDisqualified {
+			websocketWarning(s, d.Command, "\""+recipient+"\" is already disqualified.")
+			return
+		}
+
+		/*
+			Disqualify
+		*/
+
+		race.Disqualify(racer, reason)
+		disqualified = true
+	})
+	if !disqualified {
+		return
+	}
+
+	// Send the admin a message to let them know that the disqualification was successful
+	websocketEmit(s, "roomMessage", &RoomMessageMessage{
+		"lobby",
+		"!server",
+		"User \"" + recipient + "\" successfully disqualified from race " + strconv.Itoa(d.ID) + ".",
+	})
+
+	// Log the disqualification
+	logger.Info("User \"" + username + "\" disqualified user \"" + recipient + "\" from race " + strconv.Itoa(d.ID) + ".")
+}
@@ -0,0 +1,121 @@
+package server
+
+import (
+	"strconv"
+
+	"github.com/Zamiell/isaac-racing-server/models"
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	Command example:
+	adminDisqualifyFinished {
+		id: 123,
+		name: "Zamiel",
+		comment: "used a glitch",
+	}
+*/
+
+func websocketAdminDisqualifyFinished(s *melody.Session, d *IncomingWebsocketData) {
+	username := d.v.Username
+	admin := d.v.Admin
+	recipient := d.Name
+	reason := d.Comment
+	raceID := d.ID
+
+	/*
+		Validation
+	*/
+
+	// Validate that the user is an admin
+	if admin == 0 {
+		logger.Warning("User \"" + username + "\" tried to disqualify \"" + recipient + "\", but they are not an administrator.")
+		websocketError(s, d.Command, "Only administrators can do that.")
+		return
+	}
+
+	// Validate that the race is finished
+	var race models.Race
+	if v, finished, err := db.Races.GetFinished(raceID); err != nil {
+		logger.Error("Database error while getting race "+strconv.Itoa(raceID)+":", err)
+		websocketError(s, d.Command, "")
+		return
+	} else if !finished {
+		websocketWarning(s, d.Command, "Race ID "+strconv.Itoa(raceID)+" does not exist or is not finished yet.")
+		return
+	} else {
+		race = v
+	}
+
+	// Get everyone's place
+	var places []models.RaceParticipantPlace
+	if v, err := db.RaceParticipants.GetPlaces(raceID); err != nil {
+		logger.Error("Database error while getting the places for race "+strconv.Itoa(raceID)+":", err)
+		websocketError(s, d.Command, "")
+		return
+	} else {
+		places = v
+	}
+
+	// Validate that the requested person was in the race
+	recipientID := 0
+	placesMap := make(map[string]int)
+	userIDs := make(map[string]int)
+	for _, place := range places {
+		placesMap[place.Username] = place.Place
+		userIDs[place.Username] = place.UserID
+		if place.Username == recipient {
+			recipientID = place.UserID
+		}
+	}
+	if recipientID == 0 {
+		websocketWarning(s, d.Command, "\""+recipient+"\" was not in race ID "+strconv.Itoa(raceID)+".")
+		return
+	}
+
+	// Validate that they are not already disqualified
+	if placesMap[recipient] == -2 {
+		websocketWarning(s, d.Command, "\""+recipient+"\" is already disqualified.")
+		return
+	}
+
+	/*
+		Disqualify
+	*/
+
+	if err := db.RaceParticipants.SetPlace(raceID, recipientID, -2); err != nil {
+		logger.Error("Database error while setting the place for user "+strconv.Itoa(recipientID)+":", err)
+		websocketError(s, d.Command, "")
+		return
+	}
+
+	// Move up everyone who finished behind them
+	for racerName, place := range getPlacesAfterDisqualification(placesMap, recipient) {
+		if place == placesMap[racerName] {
+			continue
+		}
+		if err := db.RaceParticipants.SetPlace(raceID, userIDs[racerName], place); err != nil {
+			logger.Error("Database error while setting the place for user "+strconv.Itoa(userIDs[racerName])+":", err)
+			websocketError(s, d.Command, "")
+			return
+		}
+	}
+
+	racerNames := make([]string, 0, len(placesMap))
+	for racerName := range placesMap {
+		racerNames = append(racerNames, racerName)
+	}
+	raceSendDisqualifiedFinishedMessage(raceID, racerNames, recipient, reason)
+
+	leaderboardRecalculateFinishedRace(race, recipientID)
+
+	// Send the admin a message to let them know that the disqualification was successful
+	websocketEmit(s, "roomMessage", &RoomMessageMessage{
+		"lobby",
+		"!server",
+		"User \"" + recipient + "\" successfully disqualified from finished race " + strconv.Itoa(raceID) + ".",
+	})
+
+	// Log the disqualification
+	logger.Info("User \"" + username + "\" disqualified user \"" + recipient + "\" from finished race " + strconv.Itoa(raceID) + ".")
+}
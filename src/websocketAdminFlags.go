@@ -0,0 +1,42 @@
+package server
+
+import (
+	"github.com/Zamiell/isaac-racing-server/models"
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	Command example:
+	adminFlags {}
+*/
+
+func websocketAdminFlags(s *melody.Session, d *IncomingWebsocketData) {
+	username := d.v.Username
+	admin := d.v.Admin
+
+	/*
+		Validation
+	*/
+
+	// Validate that the user is an admin
+	if admin == 0 {
+		logger.Warning("User \"" + username + "\" tried to get the race flags, but they are not an administrator.")
+		websocketError(s, d.Command, "Only administrators can do that.")
+		return
+	}
+
+	/*
+		Get the review queue
+	*/
+
+	var flags []models.RaceFlag
+	if v, err := db.RaceFlags.GetAllUnresolved(); err != nil {
+		logger.Error("Database error while getting the race flags:", err)
+		websocketError(s, d.Command, "")
+		return
+	} else {
+		flags = v
+	}
+
+	websocketEmit(s, "adminFlags", flags)
+}
@@ -0,0 +1,42 @@
+package server
+
+import (
+	"github.com/Zamiell/isaac-racing-server/models"
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	Command example:
+	adminHallOfFameSubmissions {}
+*/
+
+func websocketAdminHallOfFameSubmissions(s *melody.Session, d *IncomingWebsocketData) {
+	username := d.v.Username
+	admin := d.v.Admin
+
+	/*
+		Validation
+	*/
+
+	// Validate that the user is an admin
+	if admin == 0 {
+		logger.Warning("User \"" + username + "\" tried to get the hall of fame submissions, but they are not an administrator.")
+		websocketError(s, d.Command, "Only administrators can do that.")
+		return
+	}
+
+	/*
+		Get the review queue
+	*/
+
+	var submissions []models.HallOfFameSubmission
+	if v, err := db.HallOfFameSubmissions.GetAllPending(); err != nil {
+		logger.Error("Database error while getting the hall of fame submissions:", err)
+		websocketError(s, d.Command, "")
+		return
+	} else {
+		submissions = v
+	}
+
+	websocketEmit(s, "adminHallOfFameSubmissions", submissions)
+}
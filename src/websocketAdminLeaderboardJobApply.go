@@ -0,0 +1,86 @@
+package server
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/Zamiell/isaac-racing-server/models"
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	Command example:
+	adminLeaderboardJobApply {
+		id: 123, // The ID of the leaderboard job
+	}
+*/
+
+func websocketAdminLeaderboardJobApply(s *melody.Session, d *IncomingWebsocketData) {
+	username := d.v.Username
+	admin := d.v.Admin
+	jobID := d.ID
+
+	/*
+		Validation
+	*/
+
+	// Validate that the user is an admin
+	if admin == 0 {
+		logger.Warning("User \"" + username + "\" tried to apply a leaderboard job, but they are not an administrator.")
+		websocketError(s, d.Command, "Only administrators can do that.")
+		return
+	}
+
+	// Validate that the job exists and that it is ready
+	var job models.LeaderboardJob
+	if v, exists, err := db.LeaderboardJobs.Get(jobID); err != nil {
+		logger.Error("Database error while getting the leaderboard job:", err)
+		websocketError(s, d.Command, "")
+		return
+	} else if !exists {
+		websocketWarning(s, d.Command, "That leaderboard job does not exist.")
+		return
+	} else {
+		job = v
+	}
+	if job.Status != string(LeaderboardJobStatusReady) {
+		websocketWarning(s, d.Command, "That leaderboard job is "+job.Status+", so it cannot be applied.")
+		return
+	}
+
+	// Validate that the job used the same rating engine as the live leaderboard
+	// (the engine is changed with the "RATING_ENGINES" environment variable)
+	if job.Format != LeaderboardJobFormatRankedSolo {
+		engine := ratingEngineGetFormat(RaceFormat(job.Format))
+		if job.Engine != engine.Name() {
+			websocketWarning(s, d.Command, "That leaderboard job used the "+job.Engine+" rating engine, but "+job.Format+" races are rated with the "+engine.Name()+" rating engine.")
+			return
+		}
+	}
+
+	/*
+		Apply
+	*/
+
+	leaderboardWriteMutex.Lock()
+	err := db.LeaderboardJobs.Apply(job.ID, job.Format)
+	leaderboardWriteMutex.Unlock()
+	if errors.Is(err, models.ErrLeaderboardJobStale) {
+		websocketWarning(s, d.Command, "Races for "+job.Format+" have finished since that leaderboard job was started. Please start a new one.")
+		return
+	} else if errors.Is(err, models.ErrLeaderboardJobNotReady) {
+		websocketWarning(s, d.Command, "That leaderboard job cannot be applied anymore.")
+		return
+	} else if err != nil {
+		logger.Error("Database error while applying leaderboard job #"+strconv.Itoa(job.ID)+":", err)
+		websocketError(s, d.Command, "")
+		return
+	}
+
+	logger.Info("User \"" + username + "\" applied leaderboard job #" + strconv.Itoa(job.ID) + " for " + job.Format + ".")
+	websocketEmit(s, "roomMessage", &RoomMessageMessage{
+		"lobby",
+		"!server",
+		"The leaderboard for " + job.Format + " was replaced with the results of job #" + strconv.Itoa(job.ID) + ".",
+	})
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"math"
+	"sort"
+
+	"github.com/Zamiell/isaac-racing-server/models"
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	Command example:
+	adminLeaderboardJobDiff {
+		id: 123, // The ID of the leaderboard job
+	}
+*/
+
+func websocketAdminLeaderboardJobDiff(s *melody.Session, d *IncomingWebsocketData) {
+	username := d.v.Username
+	admin := d.v.Admin
+	jobID := d.ID
+
+	/*
+		Validation
+	*/
+
+	// Validate that the user is an admin
+	if admin == 0 {
+		logger.Warning("User \"" + username + "\" tried to get the diff of a leaderboard job, but they are not an administrator.")
+		websocketError(s, d.Command, "Only administrators can do that.")
+		return
+	}
+
+	// Validate that the job exists and that it has results
+	var job models.LeaderboardJob
+	if v, exists, err := db.LeaderboardJobs.Get(jobID); err != nil {
+		logger.Error("Database error while getting the leaderboard job:", err)
+		websocketError(s, d.Command, "")
+		return
+	} else if !exists {
+		websocketWarning(s, d.Command, "That leaderboard job does not exist.")
+		return
+	} else {
+		job = v
+	}
+	if job.Status != string(LeaderboardJobStatusReady) {
+		websocketWarning(s, d.Command, "That leaderboard job is "+job.Status+", so it does not have any results to compare.")
+		return
+	}
+
+	/*
+		Compare the staged leaderboard with the live one
+	*/
+
+	var diff []models.LeaderboardJobDiffRow
+	if v, err := db.LeaderboardJobs.GetDiff(job.ID, job.Format); err != nil {
+		logger.Error("Database error while getting the diff of the leaderboard job:", err)
+		websocketError(s, d.Command, "")
+		return
+	} else {
+		diff = v
+	}
+
+	// The ratings are adjusted averages in milliseconds for ranked solo
+	type LeaderboardJobDiffPlayer struct {
+		UserID      int     `json:"userID"` // nolint:tagliatelle
+		Username    string  `json:"username"`
+		OldRating   float64 `json:"oldRating"`
+		NewRating   float64 `json:"newRating"`
+		Difference  float64 `json:"difference"`
+		OldNumRaces int     `json:"oldNumRaces"`
+		NewNumRaces int     `json:"newNumRaces"`
+	}
+	players := make([]LeaderboardJobDiffPlayer, 0, len(diff))
+	numChanged := 0
+	for _, row := range diff {
+		player := LeaderboardJobDiffPlayer{
+			UserID:      row.UserID,
+			Username:    row.Username,
+			OldRating:   row.OldRating,
+			NewRating:   row.NewRating,
+			Difference:  row.NewRating - row.OldRating,
+			OldNumRaces: row.OldNumRaces,
+			NewNumRaces: row.NewNumRaces,
+		}
+		if player.Difference != 0 || player.OldNumRaces != player.NewNumRaces {
+			numChanged++
+		}
+		players = append(players, player)
+	}
+
+	// The biggest changes are first
+	sort.SliceStable(players, func(i, j int) bool {
+		return math.Abs(players[i].Difference) > math.Abs(players[j].Difference)
+	})
+
+	type AdminLeaderboardJobDiffMessage struct {
+		ID         int                        `json:"id"`
+		Format     string                     `json:"format"`
+		Engine     string                     `json:"engine"`
+		NumChanged int                        `json:"numChanged"`
+		Players    []LeaderboardJobDiffPlayer `json:"players"`
+	}
+	websocketEmit(s, "adminLeaderboardJobDiff", &AdminLeaderboardJobDiffMessage{
+		ID:         job.ID,
+		Format:     job.Format,
+		Engine:     job.Engine,
+		NumChanged: numChanged,
+		Players:    players,
+	})
+}
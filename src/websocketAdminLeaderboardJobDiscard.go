@@ -0,0 +1,71 @@
+package server
+
+import (
+	"strconv"
+
+	"github.com/Zamiell/isaac-racing-server/models"
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	Command example:
+	adminLeaderboardJobDiscard {
+		id: 123, // The ID of the leaderboard job
+	}
+*/
+
+func websocketAdminLeaderboardJobDiscard(s *melody.Session, d *IncomingWebsocketData) {
+	username := d.v.Username
+	admin := d.v.Admin
+	jobID := d.ID
+
+	/*
+		Validation
+	*/
+
+	// Validate that the user is an admin
+	if admin == 0 {
+		logger.Warning("User \"" + username + "\" tried to discard a leaderboard job, but they are not an administrator.")
+		websocketError(s, d.Command, "Only administrators can do that.")
+		return
+	}
+
+	// Validate that the job exists and that it has finished
+	var job models.LeaderboardJob
+	if v, exists, err := db.LeaderboardJobs.Get(jobID); err != nil {
+		logger.Error("Database error while getting the leaderboard job:", err)
+		websocketError(s, d.Command, "")
+		return
+	} else if !exists {
+		websocketWarning(s, d.Command, "That leaderboard job does not exist.")
+		return
+	} else {
+		job = v
+	}
+	if job.Status != string(LeaderboardJobStatusReady) {
+		websocketWarning(s, d.Command, "That leaderboard job is "+job.Status+", so it cannot be discarded.")
+		return
+	}
+
+	/*
+		Discard
+	*/
+
+	if err := db.LeaderboardJobs.SetStatus(job.ID, string(LeaderboardJobStatusDiscarded)); err != nil {
+		logger.Error("Database error while discarding leaderboard job #"+strconv.Itoa(job.ID)+":", err)
+		websocketError(s, d.Command, "")
+		return
+	}
+	if err := db.LeaderboardJobs.DeleteStaging(job.ID); err != nil {
+		logger.Error("Database error while deleting the staged results of leaderboard job #"+strconv.Itoa(job.ID)+":", err)
+		websocketError(s, d.Command, "")
+		return
+	}
+
+	logger.Info("User \"" + username + "\" discarded leaderboard job #" + strconv.Itoa(job.ID) + ".")
+	websocketEmit(s, "roomMessage", &RoomMessageMessage{
+		"lobby",
+		"!server",
+		"Leaderboard job #" + strconv.Itoa(job.ID) + " was discarded.",
+	})
+}
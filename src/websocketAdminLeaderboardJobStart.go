@@ -0,0 +1,78 @@
+package server
+
+import (
+	"strconv"
+
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	Command example:
+	adminLeaderboardJobStart {
+		format: "seeded", // Either "unseeded", "seeded", "diversity", or "ranked_solo"
+		engine: "glicko2", // Optional; defaults to the engine that the format currently uses
+	}
+*/
+
+func websocketAdminLeaderboardJobStart(s *melody.Session, d *IncomingWebsocketData) {
+	userID := d.v.UserID
+	username := d.v.Username
+	admin := d.v.Admin
+	format := string(d.Format)
+	engineName := d.Engine
+
+	/*
+		Validation
+	*/
+
+	// Validate that the user is an admin
+	if admin == 0 {
+		logger.Warning("User \"" + username + "\" tried to start a leaderboard job, but they are not an administrator.")
+		websocketError(s, d.Command, "Only administrators can do that.")
+		return
+	}
+
+	// Validate the format and the rating engine
+	// (ranked solo is an average of times, so it does not use a rating engine)
+	var engine RatingEngine
+	if format == LeaderboardJobFormatRankedSolo {
+		if engineName != "" {
+			websocketWarning(s, d.Command, "The ranked solo leaderboard does not use a rating engine.")
+			return
+		}
+	} else if format == string(RaceFormatUnseeded) ||
+		format == string(RaceFormatSeeded) ||
+		format == string(RaceFormatDiversity) {
+
+		engine = ratingEngineGetFormat(RaceFormat(format))
+		if engineName != "" {
+			if v, ok := RatingEngineGet(engineName); !ok {
+				websocketWarning(s, d.Command, "That is not a valid rating engine.")
+				return
+			} else {
+				engine = v
+			}
+		}
+	} else {
+		websocketWarning(s, d.Command, "That is not a valid format.")
+		return
+	}
+
+	/*
+		Start the job
+	*/
+
+	var jobID int
+	if v, started, err := leaderboardJobStart(format, engine, userID, s, false, 1); err != nil {
+		logger.Error("Database error while starting a leaderboard job:", err)
+		websocketError(s, d.Command, "")
+		return
+	} else if !started {
+		websocketWarning(s, d.Command, "There is already a leaderboard job running for "+format+".")
+		return
+	} else {
+		jobID = v
+	}
+
+	logger.Info("User \"" + username + "\" started leaderboard job #" + strconv.Itoa(jobID) + " for " + format + ".")
+}
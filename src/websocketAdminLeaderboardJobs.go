@@ -0,0 +1,60 @@
+package server
+
+import (
+	"github.com/Zamiell/isaac-racing-server/models"
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	Command example:
+	adminLeaderboardJobs {}
+*/
+
+func websocketAdminLeaderboardJobs(s *melody.Session, d *IncomingWebsocketData) {
+	username := d.v.Username
+	admin := d.v.Admin
+
+	/*
+		Validation
+	*/
+
+	// Validate that the user is an admin
+	if admin == 0 {
+		logger.Warning("User \"" + username + "\" tried to get the leaderboard jobs, but they are not an administrator.")
+		websocketError(s, d.Command, "Only administrators can do that.")
+		return
+	}
+
+	/*
+		Get the most recent jobs
+	*/
+
+	var jobs []models.LeaderboardJob
+	if v, err := db.LeaderboardJobs.GetRecent(leaderboardJobsNumRecent); err != nil {
+		logger.Error("Database error while getting the leaderboard jobs:", err)
+		websocketError(s, d.Command, "")
+		return
+	} else {
+		jobs = v
+	}
+
+	type LeaderboardJobsRow struct {
+		models.LeaderboardJob
+		Progress int `json:"progress"` // From 0 to 100
+	}
+	rows := make([]LeaderboardJobsRow, 0, len(jobs))
+	for _, job := range jobs {
+		progress := 0
+		if job.Status != string(LeaderboardJobStatusRunning) {
+			progress = 100
+		} else if v, ok := leaderboardJobGetProgress(job.ID); ok {
+			progress = v
+		}
+		rows = append(rows, LeaderboardJobsRow{
+			LeaderboardJob: job,
+			Progress:       progress,
+		})
+	}
+
+	websocketEmit(s, "adminLeaderboardJobs", rows)
+}
@@ -65,7 +65,7 @@ func websocketAdminMessage(s *melody.Session, d *IncomingWebsocketData) {
 	}
 
 	// Send everyone the server broadcast notification
-	for _, s := range websocketSessions {
+	for _, s := range websocketGetAllSessions() {
 		websocketEmit(s, "adminMessage", &AdminMessageMessage{
 			Message: message,
 		})
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"strconv"
+
+	"github.com/Zamiell/isaac-racing-server/models"
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	Command example:
+	adminResolveFlag {
+		id: 123, // The ID of the flag
+	}
+*/
+
+// Mark a flag as reviewed; if the racer was actually cheating,
+// they should be disqualified with the "adminDisqualifyFinished" command first
+func websocketAdminResolveFlag(s *melody.Session, d *IncomingWebsocketData) {
+	userID := d.v.UserID
+	username := d.v.Username
+	admin := d.v.Admin
+	flagID := d.ID
+
+	/*
+		Validation
+	*/
+
+	// Validate that the user is an admin
+	if admin == 0 {
+		logger.Warning("User \"" + username + "\" tried to resolve flag " + strconv.Itoa(flagID) + ", but they are not an administrator.")
+		websocketError(s, d.Command, "Only administrators can do that.")
+		return
+	}
+
+	// Validate that the flag exists
+	var flag models.RaceFlag
+	if v, exists, err := db.RaceFlags.Get(flagID); err != nil {
+		logger.Error("Database error while getting flag "+strconv.Itoa(flagID)+":", err)
+		websocketError(s, d.Command, "")
+		return
+	} else if !exists {
+		websocketWarning(s, d.Command, "Flag ID "+strconv.Itoa(flagID)+" does not exist.")
+		return
+	} else {
+		flag = v
+	}
+
+	// Validate that the flag has not already been resolved
+	if flag.Resolved {
+		websocketWarning(s, d.Command, "Flag ID "+strconv.Itoa(flagID)+" has already been resolved.")
+		return
+	}
+
+	/*
+		Resolve
+	*/
+
+	if err := db.RaceFlags.Resolve(flagID, userID); err != nil {
+		logger.Error("Database error while resolving flag "+strconv.Itoa(flagID)+":", err)
+		websocketError(s, d.Command, "")
+		return
+	}
+
+	// If this was the last flag keeping the race out of the leaderboards, then let it back in
+	// (if the race is still going, then it will be handled normally when it finishes)
+	if flag.HoldLeaderboard {
+		if held, err := db.RaceFlags.RaceIsHeld(flag.RaceID); err != nil {
+			logger.Error("Database error while checking to see if race "+strconv.Itoa(flag.RaceID)+" is held:", err)
+		} else if !held {
+			if race, finished, err := db.Races.GetFinished(flag.RaceID); err != nil {
+				logger.Error("Database error while getting race "+strconv.Itoa(flag.RaceID)+":", err)
+			} else if finished {
+				leaderboardRecalculateFinishedRace(race, flag.UserID)
+			}
+		}
+	}
+
+	// Send the admin a message to let them know that the flag was resolved
+	websocketEmit(s, "roomMessage", &RoomMessageMessage{
+		"lobby",
+		"!server",
+		"Flag " + strconv.Itoa(flagID) + " for user \"" + flag.Username + "\" in race " + strconv.Itoa(flag.RaceID) + " successfully resolved.",
+	})
+
+	// Log the resolution
+	logger.Info("User \"" + username + "\" resolved flag " + strconv.Itoa(flagID) + ".")
+}
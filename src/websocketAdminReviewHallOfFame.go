@@ -0,0 +1,119 @@
+package server
+
+import (
+	"strconv"
+
+	"github.com/Zamiell/isaac-racing-server/models"
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	Command example:
+	adminReviewHallOfFame {
+		id: 123, // The ID of the submission
+		approve: true,
+		message: "The video does not show the ending.", // Optional; sent to the racer
+	}
+*/
+
+func websocketAdminReviewHallOfFame(s *melody.Session, d *IncomingWebsocketData) {
+	userID := d.v.UserID
+	username := d.v.Username
+	admin := d.v.Admin
+	submissionID := d.ID
+	approve := d.Approve
+	reason := d.Message
+
+	/*
+		Validation
+	*/
+
+	// Validate that the user is an admin
+	if admin == 0 {
+		logger.Warning("User \"" + username + "\" tried to review hall of fame submission " + strconv.Itoa(submissionID) + ", but they are not an administrator.")
+		websocketError(s, d.Command, "Only administrators can do that.")
+		return
+	}
+
+	// Validate that the submission exists
+	var submission models.HallOfFameSubmission
+	if v, exists, err := db.HallOfFameSubmissions.Get(submissionID); err != nil {
+		logger.Error("Database error while getting hall of fame submission "+strconv.Itoa(submissionID)+":", err)
+		websocketError(s, d.Command, "")
+		return
+	} else if !exists {
+		websocketWarning(s, d.Command, "Submission ID "+strconv.Itoa(submissionID)+" does not exist.")
+		return
+	} else {
+		submission = v
+	}
+
+	// Validate that the submission has not already been reviewed
+	if submission.Status != string(HallOfFameSubmissionStatusPending) {
+		websocketWarning(s, d.Command, "Submission ID "+strconv.Itoa(submissionID)+" has already been reviewed.")
+		return
+	}
+
+	// Validate the reason
+	if len(reason) > HallOfFameReasonMaxLength {
+		websocketWarning(s, d.Command, "The reason must be "+strconv.Itoa(HallOfFameReasonMaxLength)+" characters or less.")
+		return
+	}
+
+	/*
+		Review
+	*/
+
+	// The run is added to the hall of fame before the submission is marked as approved so that a
+	// failure leaves the submission pending and it can be reviewed again
+	// (approving it again is safe, since a run that is not faster does not change anything)
+	var msg string
+	if approve {
+		if improved, err := hallOfFameApproveSubmission(submission); err != nil {
+			logger.Error("Database error while adding hall of fame submission "+strconv.Itoa(submissionID)+" to the hall of fame:", err)
+			websocketError(s, d.Command, "")
+			return
+		} else if improved {
+			msg = "Your run for the " + submission.SeasonName + " season was approved and has been added to the Hall of Fame."
+		} else {
+			msg = "Your run for the " + submission.SeasonName + " season was approved, but it is not faster than your existing run, so the Hall of Fame was not changed."
+		}
+	} else {
+		msg = "Your run for the " + submission.SeasonName + " season was rejected."
+	}
+
+	status := HallOfFameSubmissionStatusRejected
+	if approve {
+		status = HallOfFameSubmissionStatusApproved
+	}
+	if err := db.HallOfFameSubmissions.SetReviewed(submissionID, string(status), userID, reason); err != nil {
+		logger.Error("Database error while reviewing hall of fame submission "+strconv.Itoa(submissionID)+":", err)
+		websocketError(s, d.Command, "")
+		return
+	}
+	if reason != "" {
+		msg += " Reason: " + reason
+	}
+
+	// Let the racer know, if they are online
+	if s2, ok := websocketGetSession(submission.Username); ok {
+		type PrivateMessageMessage struct {
+			Name    string `json:"name"`
+			Message string `json:"message"`
+		}
+		websocketEmit(s2, "privateMessage", &PrivateMessageMessage{
+			"SERVER",
+			msg,
+		})
+	}
+
+	// Send the admin a message to let them know that the submission was reviewed
+	websocketEmit(s, "roomMessage", &RoomMessageMessage{
+		"lobby",
+		"!server",
+		"Submission " + strconv.Itoa(submissionID) + " for user \"" + submission.Username + "\" successfully " + string(status) + ".",
+	})
+
+	// Log the review
+	logger.Info("User \"" + username + "\" " + string(status) + " hall of fame submission " + strconv.Itoa(submissionID) + ".")
+}
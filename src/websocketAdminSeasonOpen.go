@@ -0,0 +1,92 @@
+package server
+
+import (
+	"strconv"
+
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	Command example:
+	adminSeasonOpen {
+		name: "R+7 Season 10 (Repentance)",
+		category: "speedrun", // Either "speedrun" or "ranked_solo"
+		datetimeEnd: 1625000000000, // Optional; the season stays open until it is given an end date
+	}
+*/
+
+const (
+	SeasonNameMaxLength = 100
+)
+
+func websocketAdminSeasonOpen(s *melody.Session, d *IncomingWebsocketData) {
+	username := d.v.Username
+	admin := d.v.Admin
+	name := d.Name
+	category := d.Category
+	datetimeEnd := d.DatetimeEnd
+
+	/*
+		Validation
+	*/
+
+	// Validate that the user is an admin
+	if admin == 0 {
+		logger.Warning("User \"" + username + "\" tried to open a season, but they are not an administrator.")
+		websocketError(s, d.Command, "Only administrators can do that.")
+		return
+	}
+
+	// Validate the name
+	if name == "" || len(name) > SeasonNameMaxLength {
+		websocketWarning(s, d.Command, "The name of the season must be between 1 and "+strconv.Itoa(SeasonNameMaxLength)+" characters.")
+		return
+	}
+
+	// Validate the category
+	if category != SeasonCategorySpeedrun && category != SeasonCategoryRankedSolo {
+		websocketWarning(s, d.Command, "That is not a valid season category.")
+		return
+	}
+
+	// Validate the end date
+	if datetimeEnd != 0 && datetimeEnd <= getTimestamp() {
+		websocketWarning(s, d.Command, "The end of the season must be in the future.")
+		return
+	}
+
+	// Validate that there is only one ranked solo season at a time
+	// (races count for the ranked solo season that started most recently)
+	if category == SeasonCategoryRankedSolo {
+		if season, ok := seasonGetCurrentRankedSolo(); ok {
+			websocketWarning(s, d.Command, "The \""+season.Name+"\" season has to end before a new ranked solo season can be opened.")
+			return
+		}
+	}
+
+	/*
+		Open
+	*/
+
+	var seasonID int
+	if v, err := db.Seasons.Insert(name, string(category), datetimeEnd); err != nil {
+		logger.Error("Database error while inserting the season:", err)
+		websocketError(s, d.Command, "")
+		return
+	} else {
+		seasonID = v
+	}
+
+	// Reload the seasons so that the new one takes submissions (or counts races) right away
+	seasonsCheck()
+
+	// Send the admin a message to let them know that the season was opened
+	websocketEmit(s, "roomMessage", &RoomMessageMessage{
+		"lobby",
+		"!server",
+		"Season #" + strconv.Itoa(seasonID) + " (" + name + ") successfully opened.",
+	})
+
+	// Log the new season
+	logger.Info("User \"" + username + "\" opened season #" + strconv.Itoa(seasonID) + ": " + name)
+}
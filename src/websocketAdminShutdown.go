@@ -28,7 +28,7 @@ func websocketAdminShutdown(s *melody.Session, d *IncomingWebsocketData) {
 		shutdownMode = 2
 	}
 
-	if len(races) > 0 {
+	if racesCount() > 0 {
 		d.Message = "The server will restart when all ongoing races have finished. New race creation has been disabled."
 		websocketAdminMessage(s, d)
 		go websocketAdminShutdownSub(s, d)
@@ -47,7 +47,7 @@ func websocketAdminShutdownSub(s *melody.Session, d *IncomingWebsocketData) {
 		}
 
 		// Check to see if all races are finished
-		if len(races) == 0 {
+		if racesCount() == 0 {
 			// Wait 30 seconds so that the last people finishing a race are not immediately booted upon finishing
 			time.Sleep(time.Second * 30)
 
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"strconv"
+
+	"github.com/Zamiell/isaac-racing-server/models"
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	Command example:
+	challengeStart {
+		challengeType: "daily", // Either "daily" or "weekly"
+	}
+*/
+
+func websocketChallengeStart(s *melody.Session, d *IncomingWebsocketData) {
+	userID := d.v.UserID
+	username := d.v.Username
+	admin := d.v.Admin
+	challengeType := d.ChallengeType
+
+	/*
+		Validation
+	*/
+
+	// Validate that the server is not shutting down soon
+	if shutdownMode > 0 && admin == 0 {
+		websocketWarning(s, d.Command, "The server is restarting soon (when all ongoing races have finished). You cannot start any new races for the time being.")
+		return
+	}
+
+	// Validate the challenge type
+	if challengeType != ChallengeTypeDaily && challengeType != ChallengeTypeWeekly {
+		websocketWarning(s, d.Command, "That is not a valid challenge type.")
+		return
+	}
+
+	var challenge models.Challenge
+	if v, err := challengeGetCurrent(challengeType); err != nil {
+		logger.Error("Database error while getting the current "+string(challengeType)+" challenge:", err)
+		websocketError(s, d.Command, "")
+		return
+	} else {
+		challenge = v
+	}
+
+	// Validate that they have not already played this challenge
+	// (this is also processed as a lobby command, so they cannot be creating it twice at the same time)
+	if participated, err := db.Challenges.HasParticipated(challenge.ID, userID); err != nil {
+		logger.Error("Database error while checking to see if user \""+username+"\" played challenge #"+strconv.Itoa(challenge.ID)+":", err)
+		websocketError(s, d.Command, "")
+		return
+	} else if participated {
+		websocketWarning(s, d.Command, "You have already played the current "+string(challengeType)+" challenge.")
+		return
+	}
+
+	/*
+		Create
+	*/
+
+	ruleset := Ruleset{
+		Ranked:        false,
+		Solo:          true,
+		Format:        RaceFormatSeeded,
+		Character:     challenge.Character,
+		Goal:          RaceGoal(challenge.Goal),
+		StartingBuild: challenge.StartingBuild,
+		Seed:          challenge.Seed,
+		Difficulty:    "normal",
+	}
+	name := "Daily Challenge #" + strconv.Itoa(challenge.ID)
+	if challengeType == ChallengeTypeWeekly {
+		name = "Weekly Challenge #" + strconv.Itoa(challenge.ID)
+	}
+
+	var race *Race
+	if v, err := raceCreateSub(name, ruleset, username, "", nil); err != nil {
+		logger.Error("Database error while inserting the race:", err)
+		websocketError(s, d.Command, "")
+		return
+	} else {
+		race = v
+	}
+
+	// They are about to see the seed, so this counts as their attempt
+	if err := db.Challenges.InsertParticipant(challenge.ID, userID, race.ID); err != nil {
+		logger.Error("Database error while inserting the participant for challenge #"+strconv.Itoa(challenge.ID)+":", err)
+		websocketError(s, d.Command, "")
+
+		// Nobody knows about the race yet, so we can just get rid of it
+		racesDelete(race.ID)
+		race.Stop()
+		if err := db.Races.Delete(race.ID); err != nil {
+			logger.Error("Database error when deleting race ID "+strconv.Itoa(race.ID)+":", err)
+		}
+		return
+	}
+	logger.Info("User \"" + username + "\" started challenge #" + strconv.Itoa(challenge.ID) + " in race " + strconv.Itoa(race.ID) + ".")
+
+	d.ID = race.ID
+	race.Send(func() {
+		race.ChallengeID = challenge.ID
+		race.SendCreated()
+
+		// The player automatically joins the race
+		websocketRaceJoin(s, d)
+	})
+
+	// Playing a challenge can continue a streak
+	achievementsCheck(userID, username)
+}
@@ -10,25 +10,37 @@ import (
 
 // Received in all commands
 type IncomingWebsocketData struct {
-	Room          string                `json:"room"`
-	Message       string                `json:"message"`
-	Name          string                `json:"name"`
-	Ruleset       Ruleset               `json:"ruleset"`
-	Password      string                `json:"password"`
-	ID            int                   `json:"id"`
-	Comment       string                `json:"comment"`
-	Seed          string                `json:"seed"`
-	ItemID        int                   `json:"itemID"` // nolint:tagliatelle
-	FloorNum      int                   `json:"floorNum"`
-	StageType     int                   `json:"stageType"`
-	BackwardsPath bool                  `json:"backwardsPath"`
-	RoomID        string                `json:"roomID"` // nolint:tagliatelle
-	IP            string                `json:"ip"`
-	Enabled       bool                  `json:"enabled"`
-	Value         int                   `json:"value"`
-	Time          int64                 `json:"time"`
-	Command       string                // Added by the server after demarshaling
-	v             *models.SessionValues // Added by the server after demarshaling
+	Room              string                `json:"room"`
+	Message           string                `json:"message"`
+	Name              string                `json:"name"`
+	Ruleset           Ruleset               `json:"ruleset"`
+	Password          string                `json:"password"`
+	ID                int                   `json:"id"`
+	Comment           string                `json:"comment"`
+	Seed              string                `json:"seed"`
+	ItemID            int                   `json:"itemID"` // nolint:tagliatelle
+	FloorNum          int                   `json:"floorNum"`
+	StageType         int                   `json:"stageType"`
+	BackwardsPath     bool                  `json:"backwardsPath"`
+	RoomID            string                `json:"roomID"` // nolint:tagliatelle
+	IP                string                `json:"ip"`
+	Enabled           bool                  `json:"enabled"`
+	Value             int                   `json:"value"`
+	Time              int64                 `json:"time"`
+	Team              int                   `json:"team"`
+	Series            SeriesOptions         `json:"series"`
+	DatetimeScheduled int64                 `json:"datetimeScheduled"` // Epoch timestamp in milliseconds
+	ChallengeType     ChallengeType         `json:"challengeType"`
+	Format            RaceFormat            `json:"format"`
+	Engine            string                `json:"engine"`
+	SeasonID          int                   `json:"seasonID"` // nolint:tagliatelle
+	Version           string                `json:"version"`
+	Proof             string                `json:"proof"`
+	Approve           bool                  `json:"approve"`
+	Category          SeasonCategory        `json:"category"`
+	DatetimeEnd       int64                 `json:"datetimeEnd"` // Epoch timestamp in milliseconds
+	Command           string                // Added by the server after demarshaling
+	v                 *models.SessionValues // Added by the server after demarshaling
 }
 
 /*
@@ -60,15 +72,32 @@ type RoomMessageMessage struct {
 // Sent in the "raceCreate" command (in the "websocketRaceCreate()" function)
 // Sent in the "raceList" command (in the "websocketHandleConnect()" function)
 type RaceCreatedMessage struct {
-	ID                  int        `json:"id"`
-	Name                string     `json:"name"`
-	Status              RaceStatus `json:"status"`
-	Ruleset             Ruleset    `json:"ruleset"`
-	Captain             string     `json:"captain"`
-	IsPasswordProtected bool       `json:"isPasswordProtected"`
-	DatetimeCreated     int64      `json:"datetimeCreated"`
-	DatetimeStarted     int64      `json:"datetimeStarted"`
-	Racers              []string   `json:"racers"`
+	ID                  int            `json:"id"`
+	Name                string         `json:"name"`
+	Status              RaceStatus     `json:"status"`
+	Ruleset             Ruleset        `json:"ruleset"`
+	Captain             string         `json:"captain"`
+	IsPasswordProtected bool           `json:"isPasswordProtected"`
+	IsLocked            bool           `json:"isLocked"`
+	DatetimeCreated     int64          `json:"datetimeCreated"`
+	DatetimeStarted     int64          `json:"datetimeStarted"`
+	DatetimeScheduled   int64          `json:"datetimeScheduled"` // 0 if the race is not scheduled
+	ChallengeID         int            `json:"challengeID"`       // nolint:tagliatelle
+	Racers              []string       `json:"racers"`
+	Teams               map[string]int `json:"teams"`  // Indexed by racer name; only filled in for team races
+	Series              *SeriesMessage `json:"series"` // nil if the race is not part of a series
+}
+
+// Sent in the "raceCreate" command (as part of the "RaceCreatedMessage")
+// Sent in the "seriesUpdate" command (in the "series.SendUpdate()" function)
+type SeriesMessage struct {
+	ID        int            `json:"id"`
+	Name      string         `json:"name"`
+	BestOf    int            `json:"bestOf"`
+	RaceNum   int            `json:"raceNum"`
+	Scores    map[string]int `json:"scores"`    // Indexed by racer name
+	Winner    string         `json:"winner"`    // Blank until the series is over
+	Abandoned bool           `json:"abandoned"` // True if everyone left before someone won
 }
 
 // Sent in the "raceStart" command (in the "raceCheckStart()" and "websocketHandleConnect()" functions)
@@ -77,6 +106,16 @@ type RaceStartMessage struct {
 	SecondsToWait int `json:"secondsToWait"`
 }
 
+/*
+	Queue data types
+*/
+
+// Sent in the "queueJoined" and "queueLeft" commands
+type QueueMessage struct {
+	Format    RaceFormat `json:"format"`
+	NumQueued int        `json:"numQueued"`
+}
+
 /*
 	Admin data types
 */
@@ -0,0 +1,117 @@
+package server
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Zamiell/isaac-racing-server/models"
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	Command example:
+	hallOfFameSubmit {
+		seasonID: 5,
+		time: 1234567, // In milliseconds
+		version: "v1.7.5",
+		proof: "https://www.twitch.tv/videos/123456789",
+	}
+*/
+
+// Submit an offline speedrun to the staff review queue
+func websocketHallOfFameSubmit(s *melody.Session, d *IncomingWebsocketData) {
+	userID := d.v.UserID
+	username := d.v.Username
+	seasonID := d.SeasonID
+	runTime := d.Time
+	version := strings.TrimSpace(d.Version)
+	proof := strings.TrimSpace(d.Proof)
+
+	/*
+		Validation
+	*/
+
+	// Validate that the season exists and is still taking runs
+	var season models.Season
+	if v, ok := seasonsGet(seasonID); !ok {
+		websocketWarning(s, d.Command, "That is not a valid season.")
+		return
+	} else {
+		season = v
+	}
+	if season.Category != string(SeasonCategorySpeedrun) {
+		websocketWarning(s, d.Command, "You can only submit runs to speedrun seasons.")
+		return
+	}
+	if !seasonIsOpen(season, getTimestamp()) {
+		websocketWarning(s, d.Command, "The "+season.Name+" season is not taking submissions right now.")
+		return
+	}
+
+	// Validate the time
+	// (it is stored in seconds, so anything less than a second is not a valid run)
+	if runTime < 1000 {
+		websocketWarning(s, d.Command, "That is not a valid time.")
+		return
+	}
+
+	// Validate the version
+	if version == "" {
+		websocketWarning(s, d.Command, "You must specify the version of the mod that you played on.")
+		return
+	}
+	if len(version) > HallOfFameVersionMaxLength {
+		websocketWarning(s, d.Command, "The version must be "+strconv.Itoa(HallOfFameVersionMaxLength)+" characters or less.")
+		return
+	}
+
+	// Validate the proof
+	var site string
+	if v, ok := HallOfFameGetProofSite(proof); !ok {
+		websocketWarning(s, d.Command, "The proof must be a link to a Twitch or YouTube video.")
+		return
+	} else {
+		site = v
+	}
+
+	// Validate that they do not already have a run waiting to be reviewed
+	if pending, err := db.HallOfFameSubmissions.HasPending(seasonID, userID); err != nil {
+		logger.Error("Database error while checking to see if user \""+username+"\" has a pending hall of fame submission:", err)
+		websocketError(s, d.Command, "")
+		return
+	} else if pending {
+		websocketWarning(s, d.Command, "You already have a run for the "+season.Name+" season that is waiting to be reviewed.")
+		return
+	}
+
+	/*
+		Submit
+	*/
+
+	var submissionID int
+	if v, err := db.HallOfFameSubmissions.Insert(models.HallOfFameSubmission{
+		SeasonID: seasonID,
+		UserID:   userID,
+		Time:     int(runTime / 1000),
+		Version:  version,
+		Proof:    proof,
+		Site:     site,
+	}); err != nil {
+		logger.Error("Database error while inserting the hall of fame submission for user \""+username+"\":", err)
+		websocketError(s, d.Command, "")
+		return
+	} else {
+		submissionID = v
+	}
+
+	type PrivateMessageMessage struct {
+		Name    string `json:"name"`
+		Message string `json:"message"`
+	}
+	websocketEmit(s, "privateMessage", &PrivateMessageMessage{
+		"SERVER",
+		"Your run for the " + season.Name + " season was submitted and will be reviewed by a staff member.",
+	})
+
+	logger.Info("User \"" + username + "\" submitted hall of fame run " + strconv.Itoa(submissionID) + " for season " + strconv.Itoa(seasonID) + ".")
+}
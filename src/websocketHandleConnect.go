@@ -3,7 +3,6 @@ package server
 import (
 	"io/ioutil"
 	"path"
-	"sort"
 	"strconv"
 
 	melody "gopkg.in/olahol/melody.v1"
@@ -27,32 +26,25 @@ func websocketHandleConnect(s *melody.Session) {
 		Establish the WebSocket session
 	*/
 
-	// Lock the command mutex for the duration of the function to ensure synchronous execution
-	commandMutex.Lock()
-	defer commandMutex.Unlock()
-
 	// Disconnect any existing connections with this username
-	if s2, ok := websocketSessions[username]; ok {
+	if s2, ok := websocketGetSession(username); ok {
 		logger.Info("Closing existing connection for user \"" + username + "\".")
 		websocketError(s2, "logout", "You have logged on from somewhere else, so you have been disconnected here.")
 		websocketClose(s2)
 
 		// Wait until the existing connection is terminated
-		commandMutex.Unlock()
-		for {
-			commandMutex.Lock()
-			_, ok := websocketSessions[username]
-			commandMutex.Unlock()
-			if !ok {
-				break
-			}
+		// (the channel is closed at the end of the "websocketHandleDisconnect()" function)
+		if v, ok := websocketGetSessionValue(s2, "disconnected"); ok {
+			<-v.(chan struct{})
 		}
-		commandMutex.Lock()
 	}
 
 	// Add the connection to a session map so that we can keep track of all of the connections
+	websocketSessionsMutex.Lock()
 	websocketSessions[username] = s
-	logger.Info("User \""+username+"\" connected;", len(websocketSessions), "user(s) now connected.")
+	numSessions := len(websocketSessions)
+	websocketSessionsMutex.Unlock()
+	logger.Info("User \""+username+"\" connected;", numSessions, "user(s) now connected.")
 
 	// Send them various settings tied to their account
 	type SettingsMessage struct {
@@ -76,65 +68,32 @@ func websocketHandleConnect(s *melody.Session) {
 	// (we only want to send the client a subset of the race information in
 	// order to conserve bandwidth and hide some things that they don't need to
 	// see)
-	// https://stackoverflow.com/questions/18342784/how-to-iterate-through-a-map-in-golang-in-order/18342865
-	raceIDs := make([]int, 0)
-	for id := range races {
-		raceIDs = append(raceIDs, id)
-	}
-	sort.Ints(raceIDs)
+	// (the races are already sorted by ID)
+	raceList := racesGetAll()
 	raceListMessage := make([]RaceCreatedMessage, 0)
-	for _, id := range raceIDs {
-		race := races[id]
-		msg := RaceCreatedMessage{
-			ID:                  race.ID,
-			Name:                race.Name,
-			Status:              race.Status,
-			Ruleset:             race.Ruleset,
-			Captain:             race.Captain,
-			IsPasswordProtected: len(race.Password) > 0,
-			DatetimeCreated:     race.DatetimeCreated,
-			DatetimeStarted:     race.DatetimeStarted,
-		}
-		racers := make([]string, 0)
-		for racerName := range race.Racers {
-			racers = append(racers, racerName)
-		}
-		msg.Racers = racers
-
-		raceListMessage = append(raceListMessage, msg)
+	for _, race := range raceList {
+		// The race fields can only be safely read from the race goroutine
+		race.Call(func() {
+			raceListMessage = append(raceListMessage, *race.GetCreatedMessage(username))
+		})
 	}
 
 	// Send it to the user
 	websocketEmit(s, "raceList", raceListMessage)
 
 	// Check to see if this user is in any ongoing races
-	for _, id := range raceIDs {
-		race := races[id]
-		if _, ok := race.Racers[username]; !ok {
-			// They are not in this race
-			continue
-		}
-
-		// Join the user to the chat room coresponding to this race
-		d.Room = "_race_" + strconv.Itoa(race.ID)
-		websocketRoomJoinSub(s, d)
-
-		// Send them all the information about the racers in this race
-		racerListMessage(s, race)
-
-		// If the race is currently in the 10 second countdown
-		if race.Status == "starting" {
-			// Send them a message describing when it will start
-			websocketEmit(s, "raceStart", &RaceStartMessage{
-				ID:            race.ID,
-				SecondsToWait: 10,
-				// This will make them start behind the other racers,
-				// but it gives them 10 seconds to get ready after a disconnect;
-				// times are reported via client side start and finish anyway
-			})
-		}
+	for _, race := range raceList {
+		race.Call(func() {
+			websocketHandleConnectRejoin(s, d, race)
+		})
 	}
 
+	// The next race of a series might have been waiting for them
+	seriesRacerConnected(username)
+
+	// Measure their latency so that we can validate their run times later on
+	websocketSendPing(s)
+
 	// Send them the message(s) of the day
 	websocketEmit(s, "adminMessage", &AdminMessageMessage{
 		"[Server Notice] Most racers hang out in the Isaac Discord chat: https://discord.gg/JzbhWQb",
@@ -151,3 +110,45 @@ func websocketHandleConnect(s *melody.Session) {
 		})
 	}
 }
+
+// Put a reconnecting user back into a race that they were in
+// (this is executed by the race goroutine)
+func websocketHandleConnectRejoin(s *melody.Session, d *IncomingWebsocketData, race *Race) {
+	username := d.v.Username
+
+	var racer *Racer
+	if v, ok := race.Racers[username]; !ok {
+		// They are not in this race
+		return
+	} else {
+		racer = v
+	}
+
+	// They made it back before the grace period ended (in "raceDisconnect.go")
+	if racer.DatetimeDisconnected != 0 {
+		race.RacerReconnected(racer)
+	}
+
+	// Join the user to the chat room coresponding to this race
+	d.Room = "_race_" + strconv.Itoa(race.ID)
+	websocketRoomJoinSub(s, d)
+
+	// Send them all the information about the racers in this race
+	racerListMessage(s, race)
+
+	// If the race is currently in the 10 second countdown
+	if race.Status == RaceStatusStarting {
+		// Send them a message describing when it will start
+		websocketEmit(s, "raceStart", &RaceStartMessage{
+			ID:            race.ID,
+			SecondsToWait: int(RaceCountdownRejoin.Seconds()),
+			// This will make them start behind the other racers,
+			// but it gives them 10 seconds to get ready after a disconnect;
+			// times are reported via client side start and finish anyway
+		})
+
+		// Their run time will be shorter than the time that the server measures
+		// (in "websocketRaceFinish.go")
+		racer.DatetimeRejoined = getTimestamp()
+	}
+}
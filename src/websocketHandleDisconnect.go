@@ -13,38 +13,60 @@ func websocketHandleDisconnect(s *melody.Session) {
 	}
 	username := d.v.Username
 
-	// Lock the command mutex for the duration of the function to ensure synchronous execution
-	commandMutex.Lock()
-	defer commandMutex.Unlock()
-
-	// Eject this player from any races that have not started yet
-	for _, race := range races {
-		if race.Status != RaceStatusOpen {
-			continue
-		}
-
-		if _, ok := race.Racers[username]; ok {
-			d.ID = race.ID
-			websocketRaceLeave(s, d)
-		}
+	// Stop looking for a match (in "matchmaking.go")
+	queueRemove(username)
+
+	for _, race := range racesGetAll() {
+		// The race fields can only be safely read from the race goroutine
+		race.Call(func() {
+			// Eject this player from any races that have not started yet
+			// (but keep them signed up for scheduled races; they just cannot be ready while offline)
+			if racer, ok := race.Racers[username]; ok && race.Status == RaceStatusOpen {
+				if race.DatetimeScheduled == 0 {
+					d.ID = race.ID
+					websocketRaceLeave(s, d)
+				} else if racer.Status == RacerStatusReady {
+					race.SetRacerStatus(username, RacerStatusNotReady)
+					race.Checkpoint()
+				}
+			}
+
+			// Give them some time to come back to any races that are in progress
+			if racer, ok := race.Racers[username]; ok &&
+				race.Status == RaceStatusInProgress &&
+				racer.Status == RacerStatusRacing {
+
+				race.RacerDisconnected(racer)
+			}
+
+			// Stop spectating any races
+			if _, ok := race.Spectators[username]; ok {
+				d.ID = race.ID
+				websocketRaceUnspectate(s, d)
+			}
+		})
 	}
 
 	// Leave all the chat rooms that this person is in
 	// (we want this part after the race ejection because that step involves leaving rooms)
 	// (at this point the user should only be in the lobby, but iterate through all of the chat rooms to make sure)
-	for room, users := range chatRooms {
-		for _, user := range users {
-			if user.Name == username {
-				d.Room = room
-				websocketRoomLeaveSub(s, d)
-				break
-			}
-		}
+	for _, room := range chatRoomsGetRoomsForUser(username) {
+		d.Room = room
+		websocketRoomLeaveSub(s, d)
 	}
 
 	// Delete the connection from the session map
+	websocketSessionsMutex.Lock()
 	delete(websocketSessions, username)
+	numSessions := len(websocketSessions)
+	websocketSessionsMutex.Unlock()
+
+	// Let a new connection for this user know that this one is gone
+	// (see the "websocketHandleConnect()" function)
+	if v, ok := websocketGetSessionValue(s, "disconnected"); ok {
+		close(v.(chan struct{}))
+	}
 
 	// Log the disconnection
-	logger.Info("User \""+username+"\" disconnected;", len(websocketSessions), "user(s) now connected.")
+	logger.Info("User \""+username+"\" disconnected;", numSessions, "user(s) now connected.")
 }
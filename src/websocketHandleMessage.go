@@ -2,6 +2,7 @@ package server
 
 import (
 	"encoding/json"
+	"strconv"
 	"strings"
 
 	melody "gopkg.in/olahol/melody.v1"
@@ -25,7 +26,7 @@ func websocketHandleMessage(s *melody.Session, msg []byte) {
 	// (we will get all of the values from the session later on, but for now we
 	// only need the username)
 	var username string
-	if v, exists := s.Get("username"); !exists {
+	if v, exists := websocketGetSessionValue(s, "username"); !exists {
 		logger.Error("Failed to get \"username\" from the session (in the \"" + functionName + "\" function).")
 		websocketClose(s)
 		return
@@ -47,7 +48,11 @@ func websocketHandleMessage(s *melody.Session, msg []byte) {
 	jsonData := []byte(result[1])
 
 	// Check to see if there is a command handler for this command
-	if _, ok := commandHandlerMap[command]; !ok {
+	commandHandler, isLobbyCommand := commandHandlerMap[command]
+	chatCommandHandler, isChatCommand := chatCommandHandlerMap[command]
+	raceCommandHandler, isRaceCommand := raceCommandHandlerMap[command]
+	latencyCommandHandler, isLatencyCommand := latencyCommandHandlerMap[command]
+	if !isLobbyCommand && !isChatCommand && !isRaceCommand && !isLatencyCommand {
 		logger.Warning("User \"" + username + "\" sent an invalid command of \"" + command + "\".")
 		return
 	}
@@ -70,7 +75,28 @@ func websocketHandleMessage(s *melody.Session, msg []byte) {
 	if isDev {
 		logger.Info("User \"" + username + "\" sent a command of \"" + command + "\".")
 	}
-	commandMutex.Lock()
-	commandHandlerMap[command](s, d)
-	commandMutex.Unlock()
+
+	if isRaceCommand {
+		// Race commands are executed by the goroutine for that race,
+		// so that they do not block the lobby or any other race
+		race, ok := racesGet(d.ID)
+		if !ok {
+			websocketWarning(s, d.Command, "Race #"+strconv.Itoa(d.ID)+" does not exist.")
+			return
+		}
+		race.Send(func() {
+			raceCommandHandler(s, d)
+		})
+	} else if isChatCommand {
+		// The chat rooms are guarded by their own mutex
+		chatCommandHandler(s, d)
+	} else if isLatencyCommand {
+		// These are answered right away so that waiting on a lock does not make the latency look
+		// worse than it really is
+		latencyCommandHandler(s, d)
+	} else {
+		lobbyMutex.Lock()
+		commandHandler(s, d)
+		lobbyMutex.Unlock()
+	}
 }
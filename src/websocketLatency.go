@@ -0,0 +1,81 @@
+package server
+
+import (
+	"time"
+
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	We measure the round-trip time to each client so that we can tell the difference between a
+	slow connection and a run time that was made up
+	(the server sends "ping" and the client replies with "pong" as soon as it receives it)
+*/
+
+const (
+	// Used for clients that have never replied to a ping
+	LatencyDefault = 500 * time.Millisecond
+
+	// A client could deliberately wait before replying to look like it has a slow connection
+	LatencyMax = 3 * time.Second
+
+	// How far a reported run time can be from the time that the server measured
+	// (on top of the latency and any delay from reconnecting during the countdown)
+	RunTimeTolerance = 5 * time.Second
+)
+
+// Called when a user connects and at the start of every race that they are in
+func websocketSendPing(s *melody.Session) {
+	websocketSetSessionValue(s, "pingSent", getTimestamp())
+
+	type PingMessage struct{}
+	websocketEmit(s, "ping", &PingMessage{})
+}
+
+/*
+	Command example:
+	pong {}
+*/
+
+func websocketPong(s *melody.Session, d *IncomingWebsocketData) {
+	/*
+		Validation
+	*/
+
+	// Validate that we sent them a ping that they have not replied to yet
+	var pingSent int64
+	if v, ok := websocketGetSessionValue(s, "pingSent"); !ok {
+		return
+	} else {
+		pingSent = v.(int64)
+	}
+	if pingSent == 0 {
+		return
+	}
+
+	/*
+		Pong
+	*/
+
+	latency := getTimestamp() - pingSent
+	if v, ok := websocketGetSessionValue(s, "latency"); ok {
+		// Smooth out the measurements so that a single slow reply does not count for too much
+		latency = (v.(int64)*3 + latency) / 4
+	}
+	websocketSetSessionValue(s, "latency", latency)
+	websocketSetSessionValue(s, "pingSent", int64(0))
+}
+
+// Get the round-trip time to a client in milliseconds
+func websocketGetLatency(s *melody.Session) int64 {
+	latency := LatencyDefault.Milliseconds()
+	if v, ok := websocketGetSessionValue(s, "latency"); ok {
+		latency = v.(int64)
+	}
+
+	if latency > LatencyMax.Milliseconds() {
+		latency = LatencyMax.Milliseconds()
+	}
+
+	return latency
+}
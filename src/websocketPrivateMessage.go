@@ -48,7 +48,7 @@ func websocketPrivateMessage(s *melody.Session, d *IncomingWebsocketData) {
 	}
 
 	// Validate that the person is online
-	s2, ok := websocketSessions[recipient]
+	s2, ok := websocketGetSession(recipient)
 	if !ok {
 		logger.Info("User \"" + username + "\" tried to private message \"" + recipient + "\", but they are offline.")
 		websocketWarning(s, d.Command, "That user is not online.")
@@ -67,7 +67,7 @@ func websocketPrivateMessage(s *melody.Session, d *IncomingWebsocketData) {
 
 	// Get the user ID from the recipient's session
 	var recipientID int
-	if v, exists := s.Get("userID"); !exists {
+	if v, exists := websocketGetSessionValue(s, "userID"); !exists {
 		logger.Error("Failed to get \"userID\" from the session (in the \"" + d.Command + "\" function).")
 		websocketError(s, d.Command, "")
 		return
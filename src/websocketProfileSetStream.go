@@ -125,7 +125,7 @@ func websocketProfileSetStream(s *melody.Session, d *IncomingWebsocketData) {
 		}
 
 		// Set the new stream URL in the WebSocket session
-		s.Set("streamURL", newStreamURL)
+		websocketSetSessionValue(s, "streamURL", newStreamURL)
 
 		// It has to also be updated in all chat rooms
 		chatRoomsUpdate(username, "StreamURL", newStreamURL)
@@ -170,7 +170,7 @@ func websocketProfileSetStream(s *melody.Session, d *IncomingWebsocketData) {
 		}
 
 		// Set the new stream URL in the WebSocket session
-		s.Set("twitchBotEnabled", newTwitchBotEnabled)
+		websocketSetSessionValue(s, "twitchBotEnabled", newTwitchBotEnabled)
 	}
 
 	/*
@@ -193,6 +193,6 @@ func websocketProfileSetStream(s *melody.Session, d *IncomingWebsocketData) {
 		}
 
 		// Set the new stream URL in the WebSocket session
-		s.Set("twitchBotDelay", newTwitchBotDelay)
+		websocketSetSessionValue(s, "twitchBotDelay", newTwitchBotDelay)
 	}
 }
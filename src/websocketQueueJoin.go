@@ -0,0 +1,80 @@
+package server
+
+import (
+	"strconv"
+
+	"github.com/Zamiell/isaac-racing-server/models"
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	Command example:
+	queueJoin {
+		format: "seeded", // Either "unseeded", "seeded", or "diversity"
+	}
+*/
+
+func websocketQueueJoin(s *melody.Session, d *IncomingWebsocketData) {
+	userID := d.v.UserID
+	username := d.v.Username
+	admin := d.v.Admin
+	format := d.Format
+
+	/*
+		Validation
+	*/
+
+	// Validate that the server is not shutting down soon
+	if shutdownMode > 0 && admin == 0 {
+		websocketWarning(s, d.Command, "The server is restarting soon (when all ongoing races have finished). You cannot start any new races for the time being.")
+		return
+	}
+
+	// Validate the format
+	if !queueFormatIsValid(format) {
+		websocketWarning(s, d.Command, "You can only queue for unseeded, seeded, or diversity races.")
+		return
+	}
+
+	// Validate that they are not already in a queue
+	if queuedFormat, ok := queueGetFormat(username); ok {
+		websocketWarning(s, d.Command, "You are already in the queue for "+string(queuedFormat)+" races.")
+		return
+	}
+
+	// Validate that they are not already in a race
+	if racerIsInRace(username) {
+		websocketWarning(s, d.Command, "You cannot join the queue while you are in a race.")
+		return
+	}
+
+	// Matches are made based on their rating in this format
+	engine := ratingEngineGetFormat(format)
+	var stats models.StatsTrueSkill
+	if v, err := ratingEngineGetStats(engine, userID, format); err != nil {
+		logger.Error("Database error while getting the TrueSkill stats for \""+username+"\":", err)
+		websocketError(s, d.Command, "")
+		return
+	} else {
+		stats = v
+	}
+
+	/*
+		Join
+	*/
+
+	queueAdd(format, &QueueEntry{
+		UserID:         userID,
+		Username:       username,
+		Mu:             stats.Mu / engine.Scale(),
+		Sigma:          stats.Sigma / engine.Scale(),
+		DatetimeJoined: getTimestamp(),
+	})
+	numQueued := queueCount(format)
+	logger.Info("User \"" + username + "\" joined the " + string(format) + " queue (with " + strconv.Itoa(numQueued) + " player(s) now queued).")
+
+	websocketEmit(s, "queueJoined", &QueueMessage{
+		Format:    format,
+		NumQueued: numQueued,
+	})
+}
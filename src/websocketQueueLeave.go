@@ -0,0 +1,32 @@
+package server
+
+import (
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	Command example:
+	queueLeave {}
+*/
+
+func websocketQueueLeave(s *melody.Session, d *IncomingWebsocketData) {
+	username := d.v.Username
+
+	/*
+		Leave
+	*/
+
+	var format RaceFormat
+	if v, ok := queueRemove(username); !ok {
+		// They were probably matched at the same time that they tried to leave
+		return
+	} else {
+		format = v
+	}
+	logger.Info("User \"" + username + "\" left the " + string(format) + " queue.")
+
+	websocketEmit(s, "queueLeft", &QueueMessage{
+		Format:    format,
+		NumQueued: queueCount(format),
+	})
+}
@@ -23,8 +23,6 @@ func websocketRaceCreate(s *melody.Session, d *IncomingWebsocketData) {
 	userID := d.v.UserID
 	username := d.v.Username
 	admin := d.v.Admin
-	rateLimitAllowance := d.v.RateLimitAllowance
-	rateLimitLastCheck := d.v.RateLimitLastCheck
 	name := d.Name
 	ruleset := d.Ruleset
 	password := d.Password
@@ -67,6 +65,16 @@ func websocketRaceCreate(s *melody.Session, d *IncomingWebsocketData) {
 		return
 	}
 
+	// Validate the series options
+	if d.Series.BestOf != 0 && !seriesValidateOptions(s, d) {
+		return
+	}
+
+	// Validate the scheduled start time
+	if d.DatetimeScheduled != 0 && !raceValidateSchedule(s, d) {
+		return
+	}
+
 	// Fix the ranking for multiplayer races
 	if !ruleset.Solo {
 		ruleset.Ranked = true
@@ -102,36 +110,17 @@ func websocketRaceCreate(s *melody.Session, d *IncomingWebsocketData) {
 	}
 
 	// Check if there are any ongoing races with this name
-	for _, race := range races {
+	// (the name of a race never changes, so it is safe to read it here)
+	for _, race := range racesGetAll() {
 		if race.Name == name {
 			websocketError(s, d.Command, "There is already a non-finished race with that name.")
 			return
 		}
 	}
 
-	// Validate that the user is not creating new races over and over, which will generate an annoying sound effect for everyone in the lobby
-	// Algorithm from: http://stackoverflow.com/questions/667508/whats-a-good-rate-limiting-algorithm
-	// (allow staff/admins to create unlimited races)
-	if admin == 0 && !ruleset.Solo {
-		now := time.Now()
-		timePassed := now.Sub(rateLimitLastCheck).Seconds()
-		s.Set("rateLimitLastCheck", now)
-		logger.Info("User \"" + username + "\" has \"" + strconv.FormatFloat(timePassed, 'f', 2, 64) + "\" time passed since the last race creation.")
-
-		newRateLimitAllowance := rateLimitAllowance + timePassed*(RateLimitRate/RateLimitPer)
-		if newRateLimitAllowance > RateLimitRate {
-			newRateLimitAllowance = RateLimitRate
-		}
-
-		if newRateLimitAllowance < 1 {
-			// They are spamming new races, so automatically ban them as punishment
-			logger.Warning("User \"" + username + "\" triggered new race rate-limiting; banning them.")
-			ban(s, d)
-			return
-		}
-
-		newRateLimitAllowance--
-		s.Set("rateLimitAllowance", newRateLimitAllowance)
+	// Validate that the user is not creating new races over and over
+	if !ruleset.Solo && !raceCreateCheckRateLimit(s, d) {
+		return
 	}
 
 	/*
@@ -148,6 +137,55 @@ func websocketRaceCreate(s *melody.Session, d *IncomingWebsocketData) {
 		ruleset.Seed = diversityGetSeed(ruleset)
 	}
 
+	// Create the series first, if necessary, so that the race can point to it
+	var series *Series
+	if d.Series.BestOf > 0 {
+		if v, err := seriesCreate(name, ruleset, d.Series, userID, username); err != nil {
+			logger.Error("Database error while inserting the series:", err)
+			websocketError(s, d.Command, "")
+			return
+		} else {
+			series = v
+		}
+	}
+
+	var race *Race
+	if v, err := raceCreateSub(name, ruleset, username, password, series); err != nil {
+		logger.Error("Database error while inserting the race:", err)
+		websocketError(s, d.Command, "")
+
+		// Otherwise, the series would never get a race
+		if series != nil {
+			seriesDelete(series.ID)
+			if err := db.Series.Delete(series.ID); err != nil {
+				logger.Error("Database error while deleting series #"+strconv.Itoa(series.ID)+":", err)
+			}
+		}
+		return
+	} else {
+		race = v
+	}
+
+	// The rest of the creation has to be done by the race goroutine, like every other race command
+	// (so that nobody else can join the race before the creator does)
+	d.ID = race.ID
+	race.Send(func() {
+		if d.DatetimeScheduled != 0 {
+			race.DatetimeScheduled = d.DatetimeScheduled
+			go race.ScheduledStart(race.DatetimeScheduled)
+		}
+
+		race.SendCreated()
+
+		// The creator automatically joins the race
+		websocketRaceJoin(s, d)
+	})
+}
+
+// Create a race and keep track of it in the races map
+// (the caller is responsible for announcing it to everyone)
+// This is also called from the "series.CreateNextRace" function
+func raceCreateSub(name string, ruleset Ruleset, captain string, password string, series *Series) (*Race, error) {
 	/*
 		Create the race in the database
 		(it will have no data associated with it other than the automatically
@@ -161,45 +199,37 @@ func websocketRaceCreate(s *melody.Session, d *IncomingWebsocketData) {
 	*/
 	var raceID int
 	if v, err := db.Races.Insert(); err != nil {
-		logger.Error("Database error while inserting the race:", err)
-		websocketError(s, d.Command, "")
-		return
+		return nil, err
 	} else {
 		raceID = v
 	}
 
-	// Create the race and keep track of it in the races map
 	race := &Race{
 		ID:              raceID,
 		Name:            name,
 		Status:          RaceStatusOpen,
 		Ruleset:         ruleset,
-		Captain:         username,
+		Captain:         captain,
 		Password:        password,
 		SoundPlayed:     false,
 		DatetimeCreated: getTimestamp(),
 		DatetimeStarted: 0,
 		Racers:          make(map[string]*Racer),
+		Spectators:      make(map[string]bool),
+		Kicked:          make(map[string]bool),
 	}
-	races[raceID] = race
 
-	// Send everyone a notification that a new race has been started
-	for _, s := range websocketSessions {
-		websocketEmit(s, "raceCreated", &RaceCreatedMessage{
-			ID:                  race.ID,
-			Name:                race.Name,
-			Status:              race.Status,
-			Ruleset:             race.Ruleset,
-			Captain:             race.Captain,
-			IsPasswordProtected: len(race.Password) > 0,
-			DatetimeCreated:     race.DatetimeCreated,
-			DatetimeStarted:     race.DatetimeStarted,
-			Racers:              make([]string, 0),
-		})
+	if series != nil {
+		if err := series.AddRace(race.ID, ruleset); err != nil {
+			return nil, err
+		}
+		race.SeriesID = series.ID
 	}
 
-	d.ID = race.ID
-	websocketRaceJoin(s, d)
+	race.Run()
+	racesAdd(race)
+
+	return race, nil
 }
 
 func ban(s *melody.Session, d *IncomingWebsocketData) {
@@ -250,3 +280,41 @@ func getRankedSoloStartingBuild(userID int) (int, error) {
 
 	return nextItem, nil
 }
+
+// Validate that the user is not creating new races over and over, which will generate an annoying sound effect for everyone in the lobby
+// Algorithm from: http://stackoverflow.com/questions/667508/whats-a-good-rate-limiting-algorithm
+// (allow staff/admins to create unlimited races)
+// Returns false if they were banned for spamming
+// This is also called from the "websocketRaceRematch" function
+func raceCreateCheckRateLimit(s *melody.Session, d *IncomingWebsocketData) bool {
+	username := d.v.Username
+	admin := d.v.Admin
+	rateLimitAllowance := d.v.RateLimitAllowance
+	rateLimitLastCheck := d.v.RateLimitLastCheck
+
+	if admin != 0 {
+		return true
+	}
+
+	now := time.Now()
+	timePassed := now.Sub(rateLimitLastCheck).Seconds()
+	websocketSetSessionValue(s, "rateLimitLastCheck", now)
+	logger.Info("User \"" + username + "\" has \"" + strconv.FormatFloat(timePassed, 'f', 2, 64) + "\" time passed since the last race creation.")
+
+	newRateLimitAllowance := rateLimitAllowance + timePassed*(RateLimitRate/RateLimitPer)
+	if newRateLimitAllowance > RateLimitRate {
+		newRateLimitAllowance = RateLimitRate
+	}
+
+	if newRateLimitAllowance < 1 {
+		// They are spamming new races, so automatically ban them as punishment
+		logger.Warning("User \"" + username + "\" triggered new race rate-limiting; banning them.")
+		ban(s, d)
+		return false
+	}
+
+	newRateLimitAllowance--
+	websocketSetSessionValue(s, "rateLimitAllowance", newRateLimitAllowance)
+
+	return true
+}
@@ -1,6 +1,8 @@
 package server
 
 import (
+	"strconv"
+
 	melody "gopkg.in/olahol/melody.v1"
 )
 
@@ -14,7 +16,7 @@ func websocketRaceFinish(s *melody.Session, d *IncomingWebsocketData) {
 
 	// Validate that the race exists
 	var race *Race
-	if v, ok := races[d.ID]; !ok {
+	if v, ok := racesGet(d.ID); !ok {
 		return
 	} else {
 		race = v
@@ -39,10 +41,18 @@ func websocketRaceFinish(s *melody.Session, d *IncomingWebsocketData) {
 	}
 
 	// Validate that they sent a time
+	serverTime := getTimestamp() - race.DatetimeStarted
+	latency := websocketGetLatency(s)
+	startDelay := RunTimeGetStartDelay(race.DatetimeStarted, racer.DatetimeRejoined)
 	if d.Time <= 0 {
 		// Vanilla races and custom races will not report the local run time,
 		// so just use the server-side time instead
-		d.Time = getTimestamp() - race.DatetimeStarted
+		d.Time = serverTime
+	} else if !RunTimeIsValid(d.Time, serverTime, latency, startDelay) {
+		// Do not trust a time that the server could not have measured
+		// (an admin can look at the flagged run later on)
+		race.Flag(racer, RaceFlagTypeRunTime, "Reported a run time of "+strconv.FormatInt(d.Time, 10)+" ms, but the server measured "+strconv.FormatInt(serverTime, 10)+" ms (with a latency of "+strconv.FormatInt(latency, 10)+" ms).")
+		d.Time = serverTime - startDelay - latency
 	}
 
 	/*
@@ -53,9 +63,13 @@ func websocketRaceFinish(s *melody.Session, d *IncomingWebsocketData) {
 	racer.PlaceMid = -1
 	racer.RunTime = d.Time
 	racer.DatetimeFinished = getTimestamp()
-	race.SetRacerStatus(username, "finished")
+	race.SetRacerStatus(username, RacerStatusFinished)
+	if race.IsTeamRace() {
+		race.FinishTeammates(racer)
+	}
 	race.SetAllPlaceMid()
 	twitchRacerFinish(race, racer)
+	race.Checkpoint()
 	race.CheckFinish()
 
 	// Check to see if the user got any achievements
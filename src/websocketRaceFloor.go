@@ -17,7 +17,7 @@ func websocketRaceFloor(s *melody.Session, d *IncomingWebsocketData) {
 
 	// Validate that the race exists
 	var race *Race
-	if v, ok := races[d.ID]; !ok {
+	if v, ok := racesGet(d.ID); !ok {
 		return
 	} else {
 		race = v
@@ -54,6 +54,15 @@ func websocketRaceFloor(s *melody.Session, d *IncomingWebsocketData) {
 		return
 	}
 
+	// Flag floors that they could not have gotten to from where they were
+	// (anything can happen in custom races)
+	if race.Ruleset.Format != RaceFormatCustom &&
+		race.Ruleset.Goal != RaceGoalCustom &&
+		!FloorTransitionIsPlausible(race.Ruleset.Goal, racer, floorNum, stageType) {
+
+		race.Flag(racer, RaceFlagTypeFloor, "Went from floor "+strconv.Itoa(racer.FloorNum)+" (stage type "+strconv.Itoa(racer.StageType)+") to floor "+strconv.Itoa(floorNum)+" (stage type "+strconv.Itoa(stageType)+").")
+	}
+
 	/*
 		Set the floor
 	*/
@@ -75,14 +84,16 @@ func websocketRaceFloor(s *melody.Session, d *IncomingWebsocketData) {
 
 	race.SetAllPlaceMid()
 	race.SendAllFloor(racer)
+	race.AddSplit(racer)
+	race.Checkpoint()
 }
 
 func (race *Race) SendAllFloor(racer *Racer) {
 	leader := race.GetLeader()
 
-	for racerName := range race.Racers {
+	for _, subscriberName := range race.GetSubscribers() {
 		// Not all racers may be online during a race
-		if s, ok := websocketSessions[racerName]; ok {
+		if s, ok := websocketGetSession(subscriberName); ok {
 			millisecondsBehindLeader := int64(0)
 			if leader != nil && racer.PlaceMid > 1 {
 				millisecondsBehindLeader = racer.DatetimeArrivedFloor - leader.DatetimeArrivedFloor
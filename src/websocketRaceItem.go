@@ -18,7 +18,7 @@ func websocketRaceItem(s *melody.Session, d *IncomingWebsocketData) {
 
 	// Validate that the race exists
 	var race *Race
-	if v, ok := races[d.ID]; !ok {
+	if v, ok := racesGet(d.ID); !ok {
 		return
 	} else {
 		race = v
@@ -56,6 +56,11 @@ func websocketRaceItem(s *melody.Session, d *IncomingWebsocketData) {
 		return
 	}
 
+	// Flag items that do not exist
+	if !plausibilityItemIsKnown(itemID) {
+		race.Flag(racer, RaceFlagTypeItem, "Picked up item "+strconv.Itoa(itemID)+", which does not exist.")
+	}
+
 	/*
 		Add the item
 	*/
@@ -72,9 +77,9 @@ func websocketRaceItem(s *melody.Session, d *IncomingWebsocketData) {
 		racer.CharacterNum++
 		race.SetAllPlaceMid()
 
-		for racerName := range race.Racers {
+		for _, subscriberName := range race.GetSubscribers() {
 			// Not all racers may be online during a race
-			if s, ok := websocketSessions[racerName]; ok {
+			if s, ok := websocketGetSession(subscriberName); ok {
 				// Send the message about the new character
 				type RacerCharacterMessage struct {
 					ID           int    `json:"id"`
@@ -127,9 +132,9 @@ func websocketRaceItem(s *melody.Session, d *IncomingWebsocketData) {
 		}
 	}
 
-	for racerName := range race.Racers {
+	for _, subscriberName := range race.GetSubscribers() {
 		// Not all racers may be online during a race
-		if s, ok := websocketSessions[racerName]; ok {
+		if s, ok := websocketGetSession(subscriberName); ok {
 			// Send the message about the item
 			websocketEmit(s, "racerAddItem", &RacerAddItemMessage{
 				raceID,
@@ -146,4 +151,6 @@ func websocketRaceItem(s *melody.Session, d *IncomingWebsocketData) {
 			}
 		}
 	}
+
+	race.Checkpoint()
 }
@@ -8,6 +8,16 @@ import (
 
 // This is also called manually by the "websocketRaceCreate" function
 func websocketRaceJoin(s *melody.Session, d *IncomingWebsocketData) {
+	raceJoinSub(s, d, false)
+}
+
+/*
+	Subroutines
+*/
+
+// "exclusive" is true for races made by the matchmaker, which have to be the only race that the
+// user is in
+func raceJoinSub(s *melody.Session, d *IncomingWebsocketData, exclusive bool) {
 	userID := d.v.UserID
 	username := d.v.Username
 	raceID := d.ID
@@ -18,7 +28,7 @@ func websocketRaceJoin(s *melody.Session, d *IncomingWebsocketData) {
 
 	// Validate that the race exists
 	var race *Race
-	if v, ok := races[d.ID]; !ok {
+	if v, ok := racesGet(d.ID); !ok {
 		return
 	} else {
 		race = v
@@ -34,6 +44,19 @@ func websocketRaceJoin(s *melody.Session, d *IncomingWebsocketData) {
 		return
 	}
 
+	// Validate that they were not kicked from the race
+	if _, ok := race.Kicked[username]; ok {
+		websocketWarning(s, d.Command, "You were kicked from race ID "+strconv.Itoa(raceID)+", so you cannot rejoin it.")
+		return
+	}
+
+	// Validate that the race is not locked
+	// (the captain has to be able to join their own race right after creating it)
+	if race.Locked && username != race.Captain {
+		websocketWarning(s, d.Command, "Race ID "+strconv.Itoa(raceID)+" is locked by the captain.")
+		return
+	}
+
 	// Validate that we are not trying to join a solo race
 	if race.Ruleset.Solo && len(race.Racers) > 0 {
 		logger.Warning("User \"" + username + "\" attempted to call " + d.Command + " on race ID " + strconv.Itoa(raceID) + ", but it is a solo race.")
@@ -41,39 +64,75 @@ func websocketRaceJoin(s *melody.Session, d *IncomingWebsocketData) {
 		return
 	}
 
+	// Validate that only the entrants of a series can join the later races in it
+	if series, ok := seriesGet(race.SeriesID); ok && !series.IsEntrant(username) {
+		websocketWarning(s, d.Command, "Only the entrants of this series can join this race.")
+		return
+	}
+
+	// Validate the team if this is a team race
+	team := 0
+	if race.IsTeamRace() {
+		if d.Team < 1 || d.Team > RaceNumTeams {
+			websocketWarning(s, d.Command, "You must pick team 1 or team 2 to join this race.")
+			return
+		}
+		if len(race.GetTeamRacers(d.Team)) >= race.Ruleset.TeamSize {
+			websocketWarning(s, d.Command, "Team "+strconv.Itoa(d.Team)+" is already full.")
+			return
+		}
+		team = d.Team
+	}
+
 	// Validate the password if the race is password protected
 	if len(race.Password) > 0 && race.Password != d.Password {
 		websocketWarning(s, d.Command, "That is not the correct password.")
 		return
 	}
 
+	// Validate that a race made by the matchmaker is the only race that they are in
+	// (this is checked again here since they could have joined another race after they were matched)
+	if !racerRacesAdd(username, race.ID, exclusive) {
+		websocketWarning(s, d.Command, "You cannot join a matchmaking race while you are in another race.")
+		return
+	}
+
 	/*
 		Join
 	*/
 
+	// Racers receive all of the updates anyway, so stop spectating if they were
+	if _, ok := race.Spectators[username]; ok {
+		websocketRaceUnspectate(s, d)
+	}
+
 	// Add this user to the race
 	racer := &Racer{
 		ID:             userID,
 		Name:           username,
 		DatetimeJoined: getTimestamp(),
-		Status:         "not ready",
+		Status:         RacerStatusNotReady,
 		FloorNum:       1,
 		Items:          make([]*Item, 0),
 		Rooms:          make([]*Room, 0),
 		CharacterNum:   1,
 		PlaceMid:       -1, // Will be set to the number of racers once the race starts
+		Team:           team,
 	}
 	race.Racers[username] = racer
+	race.Checkpoint()
 
 	// Send everyone a notification that the user joined
-	for _, s := range websocketSessions {
+	for _, s := range websocketGetAllSessions() {
 		type RaceJoinedMessage struct {
 			ID   int    `json:"id"`
 			Name string `json:"name"`
+			Team int    `json:"team"`
 		}
 		websocketEmit(s, "raceJoined", &RaceJoinedMessage{
 			raceID,
 			username,
+			team,
 		})
 	}
 
@@ -84,15 +143,28 @@ func websocketRaceJoin(s *melody.Session, d *IncomingWebsocketData) {
 	d.Room = "_race_" + strconv.Itoa(raceID)
 	websocketRoomJoinSub(s, d)
 
+	// They are not looking for a match anymore (in "matchmaking.go")
+	if format, ok := queueRemove(username); ok {
+		websocketEmit(s, "queueLeft", &QueueMessage{
+			Format:    format,
+			NumQueued: queueCount(format),
+		})
+	}
+
 	// Send a reminder message to people playing ranked solo races
 	if race.Ruleset.Ranked && race.Ruleset.Solo {
+		leaderboardName := "Ranked Solo leaderboards"
+		if season, ok := seasonGetCurrentRankedSolo(); ok {
+			leaderboardName = season.Name + " leaderboards"
+		}
+
 		type PrivateMessageMessage struct {
 			Name    string `json:"name"`
 			Message string `json:"message"`
 		}
 		websocketEmit(s, "privateMessage", &PrivateMessageMessage{
 			"SERVER",
-			"In order to prevent cheating, you must stream your races on Twitch or YouTube to be eligible for the Season 3 Ranked Solo leaderboards.",
+			"In order to prevent cheating, you must stream your races on Twitch or YouTube to be eligible for the " + leaderboardName + ".",
 		})
 	}
 }
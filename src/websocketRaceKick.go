@@ -0,0 +1,77 @@
+package server
+
+import (
+	"strconv"
+
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	Command example:
+	raceKick {
+		id: 123,
+		name: "Zamiel",
+	}
+*/
+
+func websocketRaceKick(s *melody.Session, d *IncomingWebsocketData) {
+	username := d.v.Username
+	name := d.Name
+
+	/*
+		Validation
+	*/
+
+	// Validate that the race exists
+	var race *Race
+	if v, ok := racesGet(d.ID); !ok {
+		return
+	} else {
+		race = v
+	}
+
+	// Validate that they are the captain
+	if race.Captain != username {
+		websocketWarning(s, d.Command, "Only the captain of the race can kick people.")
+		return
+	}
+
+	// Validate that the race is open
+	if race.Status != RaceStatusOpen {
+		websocketWarning(s, d.Command, "You can only kick people before the race starts.")
+		return
+	}
+
+	// Validate that they are not trying to kick themselves
+	if name == username {
+		websocketWarning(s, d.Command, "You cannot kick yourself. Leave the race instead.")
+		return
+	}
+
+	// Validate that the person being kicked is in the race
+	if _, ok := race.Racers[name]; !ok {
+		websocketWarning(s, d.Command, "\""+name+"\" is not in race ID "+strconv.Itoa(race.ID)+".")
+		return
+	}
+
+	/*
+		Kick
+	*/
+
+	logger.Info("User \"" + username + "\" kicked \"" + name + "\" from race " + strconv.Itoa(race.ID) + ".")
+	race.Kicked[name] = true
+
+	// Remove them from the race in the same way as if they left it themselves
+	// (they might be offline, e.g. if they are in a scheduled race or a race that was restored
+	// when the server started)
+	race.RemoveRacer(name)
+
+	if s2, ok := websocketGetSession(name); ok {
+		type RaceKickedMessage struct {
+			ID int `json:"id"`
+		}
+		websocketEmit(s2, "raceKicked", &RaceKickedMessage{
+			ID: race.ID,
+		})
+	}
+}
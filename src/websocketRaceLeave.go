@@ -15,7 +15,7 @@ func websocketRaceLeave(s *melody.Session, d *IncomingWebsocketData) {
 
 	// Validate that the race exists
 	var race *Race
-	if v, ok := races[d.ID]; !ok {
+	if v, ok := racesGet(d.ID); !ok {
 		return
 	} else {
 		race = v
@@ -35,15 +35,30 @@ func websocketRaceLeave(s *melody.Session, d *IncomingWebsocketData) {
 		Leave
 	*/
 
+	race.RemoveRacer(username)
+}
+
+// Remove a racer from an open race
+// (this is also used for kicks and for dropping racers who were not ready for a scheduled race)
+func (race *Race) RemoveRacer(username string) {
 	// Disconnect the user from the channel for that race
-	d.Room = "_race_" + strconv.Itoa(race.ID)
-	websocketRoomLeaveSub(s, d)
+	// (they might be offline if they were dropped from a scheduled race)
+	if s, ok := websocketGetSession(username); ok {
+		d := &IncomingWebsocketData{
+			Command: "raceLeave",
+			Room:    "_race_" + strconv.Itoa(race.ID),
+		}
+		if websocketGetSessionValues(s, d) {
+			websocketRoomLeaveSub(s, d)
+		}
+	}
 
 	// Remove this racer from the map
 	delete(race.Racers, username)
+	racerRacesRemove(username, race.ID)
 
 	// Send everyone a notification that the user left the race
-	for _, s := range websocketSessions {
+	for _, s := range websocketGetAllSessions() {
 		type RaceLeftMessage struct {
 			ID   int    `json:"id"`
 			Name string `json:"name"`
@@ -54,26 +69,53 @@ func websocketRaceLeave(s *melody.Session, d *IncomingWebsocketData) {
 		})
 	}
 
+	// Pass the captaincy to whoever has been in the race the longest
+	if username == race.Captain && len(race.Racers) > 0 {
+		var newCaptain *Racer
+		for _, racer := range race.Racers {
+			if newCaptain == nil || racer.DatetimeJoined < newCaptain.DatetimeJoined {
+				newCaptain = racer
+			}
+		}
+		race.SetCaptain(newCaptain.Name)
+	}
+
 	if len(race.Racers) == 0 {
 		// Remove this race if this is the last person to leave
-		delete(races, d.ID)
+		race.Delete()
 
-		// Also delete it from the database
-		if err := db.Races.Delete(d.ID); err != nil {
-			logger.Error("Database error when deleting race ID "+strconv.Itoa(d.ID)+":", err)
-			websocketError(s, d.Command, "")
-			return
+		// Nobody is left to play the rest of the series
+		if series, ok := seriesGet(race.SeriesID); ok {
+			series.Abandon()
 		}
+		return
 	} else if len(race.Racers) == 1 {
 		// If the race went from 2 people to 1, check to see if the last person is ready
 		for _, lastRacer := range race.Racers {
 			if lastRacer.Status == RacerStatusReady {
 				// Automatically unready the last person so that they do not start the race by
 				// themselves
-				race.SetRacerStatus(lastRacer.Name, "not ready")
+				race.SetRacerStatus(lastRacer.Name, RacerStatusNotReady)
 			}
 		}
 	} else {
 		race.CheckStart()
 	}
+	race.Checkpoint()
+}
+
+// Get rid of a race that nobody is in anymore
+// (this must be called from the race goroutine)
+func (race *Race) Delete() {
+	race.RemoveSpectators()
+	racesDelete(race.ID)
+	race.Stop()
+
+	// Also delete it from the database
+	if err := db.Races.Delete(race.ID); err != nil {
+		logger.Error("Database error when deleting race ID "+strconv.Itoa(race.ID)+":", err)
+	}
+
+	// If this was a rematch, then the racers can ask for another one
+	rematchClearRaceID(race.ID)
 }
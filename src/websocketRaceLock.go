@@ -0,0 +1,66 @@
+package server
+
+import (
+	"strconv"
+
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	Command example:
+	raceLock {
+		id: 123,
+		enabled: true,
+	}
+*/
+
+func websocketRaceLock(s *melody.Session, d *IncomingWebsocketData) {
+	username := d.v.Username
+
+	/*
+		Validation
+	*/
+
+	// Validate that the race exists
+	var race *Race
+	if v, ok := racesGet(d.ID); !ok {
+		return
+	} else {
+		race = v
+	}
+
+	// Validate that they are the captain
+	if race.Captain != username {
+		websocketWarning(s, d.Command, "Only the captain of the race can lock it.")
+		return
+	}
+
+	// Validate that the race is open
+	// (nobody can join a race after it starts anyway)
+	if race.Status != RaceStatusOpen {
+		return
+	}
+
+	// Validate that the lock is changing
+	if race.Locked == d.Enabled {
+		return
+	}
+
+	/*
+		Lock
+	*/
+
+	logger.Info("User \""+username+"\" set the lock on race "+strconv.Itoa(race.ID)+" to:", d.Enabled)
+	race.Locked = d.Enabled
+
+	for _, s := range websocketGetAllSessions() {
+		type RaceSetLockedMessage struct {
+			ID     int  `json:"id"`
+			Locked bool `json:"locked"`
+		}
+		websocketEmit(s, "raceSetLocked", &RaceSetLockedMessage{
+			ID:     race.ID,
+			Locked: race.Locked,
+		})
+	}
+}
@@ -14,7 +14,7 @@ func websocketRaceQuit(s *melody.Session, d *IncomingWebsocketData) {
 
 	// Validate that the race exists
 	var race *Race
-	if v, ok := races[d.ID]; !ok {
+	if v, ok := racesGet(d.ID); !ok {
 		return
 	} else {
 		race = v
@@ -49,5 +49,6 @@ func websocketRaceQuit(s *melody.Session, d *IncomingWebsocketData) {
 	racer.RunTime = racer.DatetimeFinished - race.DatetimeStarted
 	race.SetAllPlaceMid()
 	twitchRacerQuit(race, racer)
+	race.Checkpoint()
 	race.CheckFinish()
 }
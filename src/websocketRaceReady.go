@@ -13,7 +13,7 @@ func websocketRaceReady(s *melody.Session, d *IncomingWebsocketData) {
 
 	// Validate that the race exists
 	var race *Race
-	if v, ok := races[d.ID]; !ok {
+	if v, ok := racesGet(d.ID); !ok {
 		return
 	} else {
 		race = v
@@ -33,7 +33,7 @@ func websocketRaceReady(s *melody.Session, d *IncomingWebsocketData) {
 	}
 
 	// Validate that their status is set to "not ready"
-	if racer.Status != "not ready" {
+	if racer.Status != RacerStatusNotReady {
 		return
 	}
 
@@ -42,5 +42,6 @@ func websocketRaceReady(s *melody.Session, d *IncomingWebsocketData) {
 	*/
 
 	race.SetRacerStatus(username, RacerStatusReady)
+	race.Checkpoint()
 	race.CheckStart()
 }
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"strconv"
+
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	Command example:
+	raceRematch {
+		id: 123, // The ID of the race that just finished
+	}
+*/
+
+func websocketRaceRematch(s *melody.Session, d *IncomingWebsocketData) {
+	username := d.v.Username
+	admin := d.v.Admin
+	oldRaceID := d.ID
+
+	/*
+		Validation
+	*/
+
+	// Validate that the server is not shutting down soon
+	if shutdownMode > 0 && admin == 0 {
+		websocketWarning(s, d.Command, "The server is restarting soon (when all ongoing races have finished). You cannot start any new races for the time being.")
+		return
+	}
+
+	// Validate that the race finished recently
+	var rematchInfo *RematchInfo
+	if v, ok := rematchGet(oldRaceID); !ok {
+		websocketWarning(s, d.Command, "You can only ask for a rematch in the first "+RematchWindow.String()+" after a race finishes.")
+		return
+	} else {
+		rematchInfo = v
+	}
+
+	// Validate that they were in the race
+	var team int
+	if v, ok := rematchInfo.Teams[username]; !ok {
+		websocketWarning(s, d.Command, "Only the people who were in race ID "+strconv.Itoa(oldRaceID)+" can ask for a rematch.")
+		return
+	} else {
+		team = v
+	}
+
+	// If someone else already asked for a rematch, then just join that race instead
+	// (this is also processed as a lobby command, so nobody else can be creating it right now)
+	if rematchRaceID, ok := rematchGetRaceID(rematchInfo); ok {
+		if race, ok := racesGet(rematchRaceID); ok {
+			d.ID = race.ID
+			d.Team = team
+			d.Password = rematchInfo.Password
+			race.Send(func() {
+				websocketRaceJoin(s, d)
+			})
+		} else {
+			websocketWarning(s, d.Command, "The rematch for race ID "+strconv.Itoa(oldRaceID)+" has already started or was deleted.")
+		}
+		return
+	}
+
+	ruleset := raceRerollRuleset(rematchInfo.Ruleset, false, false)
+
+	// Validate that the user is not creating new races over and over
+	if !ruleset.Solo && !raceCreateCheckRateLimit(s, d) {
+		return
+	}
+
+	/*
+		Rematch
+	*/
+
+	// The old race is over, so its name is free again unless someone else took it in the meantime
+	// (the name of a race never changes, so it is safe to read it here)
+	name := rematchInfo.Name
+	for _, race := range racesGetAll() {
+		if race.Name == name {
+			name += " (rematch)"
+			break
+		}
+	}
+
+	var race *Race
+	if v, err := raceCreateSub(name, ruleset, username, rematchInfo.Password, nil); err != nil {
+		logger.Error("Database error while inserting the race:", err)
+		websocketError(s, d.Command, "")
+		return
+	} else {
+		race = v
+	}
+	rematchSetRaceID(rematchInfo, race.ID)
+	logger.Info("User \"" + username + "\" created race " + strconv.Itoa(race.ID) + " as a rematch of race " + strconv.Itoa(oldRaceID) + ".")
+
+	d.ID = race.ID
+	d.Team = team
+	d.Password = rematchInfo.Password
+	race.Send(func() {
+		race.SendCreated()
+
+		// The person who asked for the rematch automatically joins the race
+		websocketRaceJoin(s, d)
+
+		// Invite everyone else from the old race
+		for racerName := range rematchInfo.Teams {
+			if racerName == username {
+				continue
+			}
+
+			// Not everyone may still be online
+			if s2, ok := websocketGetSession(racerName); ok {
+				type RaceRematchMessage struct {
+					ID        int    `json:"id"`
+					OldRaceID int    `json:"oldRaceID"` // nolint:tagliatelle
+					Name      string `json:"name"`
+				}
+				websocketEmit(s2, "raceRematch", &RaceRematchMessage{
+					ID:        race.ID,
+					OldRaceID: oldRaceID,
+					Name:      username,
+				})
+			}
+		}
+	})
+}
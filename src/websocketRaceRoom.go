@@ -14,7 +14,7 @@ func websocketRaceRoom(s *melody.Session, d *IncomingWebsocketData) {
 
 	// Validate that the race exists
 	var race *Race
-	if v, ok := races[d.ID]; !ok {
+	if v, ok := racesGet(d.ID); !ok {
 		return
 	} else {
 		race = v
@@ -49,4 +49,7 @@ func websocketRaceRoom(s *melody.Session, d *IncomingWebsocketData) {
 		getTimestamp(),
 	}
 	racer.Rooms = append(racer.Rooms, room)
+
+	// We do not checkpoint the race here since rooms are sent very frequently;
+	// they will be written to the database along with the next floor or item
 }
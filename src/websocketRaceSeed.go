@@ -14,7 +14,7 @@ func websocketRaceSeed(s *melody.Session, d *IncomingWebsocketData) {
 
 	// Validate that the race exists
 	var race *Race
-	if v, ok := races[d.ID]; !ok {
+	if v, ok := racesGet(d.ID); !ok {
 		return
 	} else {
 		race = v
@@ -44,12 +44,29 @@ func websocketRaceSeed(s *melody.Session, d *IncomingWebsocketData) {
 		return
 	}
 
+	// Validate that they are on the right seed in seeded races
+	// (the flag keeps the race out of the leaderboards in case they finish on the wrong seed anyway)
+	if !RaceSeedIsValid(race, racer, seed) {
+		race.Flag(racer, RaceFlagTypeSeed, "Reported a seed of \""+seed+"\", but the seed for the race is \""+race.Ruleset.Seed+"\".")
+		websocketWarning(s, d.Command, "You are not on the seed for this race. Please restart on seed \""+race.Ruleset.Seed+"\".")
+		return
+	}
+
 	/*
 		Add the seed
 	*/
 
+	// Keep track of how many times they restarted to get a better seed
+	// (getting to a new character in a multi-character race is not a reset)
+	if racer.Seed != "" && racer.CharacterNum == racer.SeedCharacterNum {
+		racer.NumResets++
+	}
+
 	racer.Seed = seed
+	racer.SeedCharacterNum = racer.CharacterNum
 	racer.Items = make([]*Item, 0) // Reset all of their accumulated items
 	racer.StartingItem = 0
-	racer.Rooms = make([]*Room, 0) // Reset all of their visited rooms
+	racer.Rooms = make([]*Room, 0)   // Reset all of their visited rooms
+	racer.Splits = make([]*Split, 0) // Reset all of their floor splits
+	race.Checkpoint()
 }
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"strconv"
+
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	Command example:
+	raceSpectate {
+		id: 123,
+	}
+*/
+
+func websocketRaceSpectate(s *melody.Session, d *IncomingWebsocketData) {
+	username := d.v.Username
+
+	/*
+		Validation
+	*/
+
+	// Validate that the race exists
+	var race *Race
+	if v, ok := racesGet(d.ID); !ok {
+		return
+	} else {
+		race = v
+	}
+
+	// Validate that they are not in the race
+	// (racers already receive all of the updates)
+	if _, ok := race.Racers[username]; ok {
+		return
+	}
+
+	// Validate that they are not already spectating the race
+	if _, ok := race.Spectators[username]; ok {
+		return
+	}
+
+	// Validate that the race is not a challenge
+	// (spectators would be able to see the seed before they play it themselves)
+	if race.ChallengeID != 0 {
+		websocketWarning(s, d.Command, "You cannot spectate daily or weekly challenges.")
+		return
+	}
+
+	// Validate the password if the race is password protected
+	if len(race.Password) > 0 && race.Password != d.Password {
+		websocketWarning(s, d.Command, "That is not the correct password.")
+		return
+	}
+
+	/*
+		Spectate
+	*/
+
+	race.Spectators[username] = true
+
+	// Send them all the information about the racers in this race
+	// (from now on, they will receive the same live updates as the racers)
+	racerListMessage(s, race)
+
+	// Join the user to the spectator channel for that race
+	// (spectators get their own channel so that they cannot talk to the racers mid-race)
+	d.Room = "_race_" + strconv.Itoa(race.ID) + "_spectators"
+	websocketRoomJoinSub(s, d)
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"strconv"
+
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	Command example:
+	raceTransferCaptain {
+		id: 123,
+		name: "Zamiel",
+	}
+*/
+
+func websocketRaceTransferCaptain(s *melody.Session, d *IncomingWebsocketData) {
+	username := d.v.Username
+	name := d.Name
+
+	/*
+		Validation
+	*/
+
+	// Validate that the race exists
+	var race *Race
+	if v, ok := racesGet(d.ID); !ok {
+		return
+	} else {
+		race = v
+	}
+
+	// Validate that they are the captain
+	if race.Captain != username {
+		websocketWarning(s, d.Command, "Only the captain of the race can transfer the captaincy.")
+		return
+	}
+
+	// Validate that they are not trying to transfer it to themselves
+	if name == username {
+		return
+	}
+
+	// Validate that the new captain is in the race
+	if _, ok := race.Racers[name]; !ok {
+		websocketWarning(s, d.Command, "\""+name+"\" is not in race ID "+strconv.Itoa(race.ID)+".")
+		return
+	}
+
+	/*
+		Transfer
+	*/
+
+	logger.Info("User \"" + username + "\" transferred the captaincy of race " + strconv.Itoa(race.ID) + " to \"" + name + "\".")
+	race.SetCaptain(name)
+}
@@ -13,7 +13,7 @@ func websocketRaceUnready(s *melody.Session, d *IncomingWebsocketData) {
 
 	// Validate that the race exists
 	var race *Race
-	if v, ok := races[d.ID]; !ok {
+	if v, ok := racesGet(d.ID); !ok {
 		return
 	} else {
 		race = v
@@ -41,5 +41,6 @@ func websocketRaceUnready(s *melody.Session, d *IncomingWebsocketData) {
 		Unready
 	*/
 
-	race.SetRacerStatus(username, "not ready")
+	race.SetRacerStatus(username, RacerStatusNotReady)
+	race.Checkpoint()
 }
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"strconv"
+
+	melody "gopkg.in/olahol/melody.v1"
+)
+
+/*
+	Command example:
+	raceUnspectate {
+		id: 123,
+	}
+*/
+
+// This is also called manually by the "websocketRaceJoin" and "websocketHandleDisconnect" functions
+func websocketRaceUnspectate(s *melody.Session, d *IncomingWebsocketData) {
+	username := d.v.Username
+
+	/*
+		Validation
+	*/
+
+	// Validate that the race exists
+	var race *Race
+	if v, ok := racesGet(d.ID); !ok {
+		return
+	} else {
+		race = v
+	}
+
+	// Validate that they are spectating the race
+	if _, ok := race.Spectators[username]; !ok {
+		return
+	}
+
+	/*
+		Unspectate
+	*/
+
+	delete(race.Spectators, username)
+
+	// Disconnect the user from the spectator channel for that race
+	d.Room = "_race_" + strconv.Itoa(race.ID) + "_spectators"
+	websocketRoomLeaveSub(s, d)
+}
@@ -32,7 +32,7 @@ func websocketRoomJoin(s *melody.Session, d *IncomingWebsocketData) {
 	}
 
 	// Validate that they are not already in the room
-	users, ok := chatRooms[d.Room]
+	users, ok := chatRoomsGetUsers(d.Room)
 	if ok {
 		// The room exists (at least 1 person is in it)
 		userInRoom := false
@@ -66,8 +66,10 @@ func websocketRoomJoinSub(s *melody.Session, d *IncomingWebsocketData) {
 
 	// Add the user to the chat room map
 	userObject := User{username, admin, muted, streamURL}
+	chatRoomsMutex.Lock()
 	chatRooms[room] = append(chatRooms[room], userObject) // This will create the map entry if it does not already exist
-	users := chatRooms[room]                              // Save the list of users in the room for later
+	users := append([]User{}, chatRooms[room]...)         // Save the list of users in the room for later
+	chatRoomsMutex.Unlock()
 
 	// Give the user the list of everyone in the chat room
 	type RoomListMessage struct {
@@ -82,7 +84,7 @@ func websocketRoomJoinSub(s *melody.Session, d *IncomingWebsocketData) {
 	// Tell everyone else that someone joined
 	for _, user := range users {
 		// All users in the chat room should be online, but check just in case
-		if s2, ok := websocketSessions[user.Name]; ok {
+		if s2, ok := websocketGetSession(user.Name); ok {
 			if user.Name == username {
 				// We don't need to tell the person who just joined anything
 				continue
@@ -22,7 +22,7 @@ func websocketRoomLeave(s *melody.Session, d *IncomingWebsocketData) {
 	}
 
 	// Validate that the room exists
-	users, ok := chatRooms[d.Room]
+	users, ok := chatRoomsGetUsers(d.Room)
 	if !ok {
 		logger.Warning("User \"" + username + "\" tried to leave an invalid room.")
 		websocketError(s, d.Command, "That is not a valid room name.")
@@ -56,8 +56,10 @@ func websocketRoomLeaveSub(s *melody.Session, d *IncomingWebsocketData) {
 	room := d.Room
 
 	// Get the index of the user in the chat room mapping for this room
+	chatRoomsMutex.Lock()
 	users, ok := chatRooms[room]
 	if !ok {
+		chatRoomsMutex.Unlock()
 		logger.Error("Failed to get the list of users for room \"" + room + "\".")
 		return
 	}
@@ -69,23 +71,25 @@ func websocketRoomLeaveSub(s *melody.Session, d *IncomingWebsocketData) {
 		}
 	}
 	if index == -1 {
+		chatRoomsMutex.Unlock()
 		logger.Error("Failed to get the index for the current user for room \"" + room + "\".")
 		return
 	}
 
 	// Remove the user from the chat room map
 	chatRooms[room] = append(users[:index], users[index+1:]...)
-	users = chatRooms[room] // Save the list of users in the room for later
+	users = append([]User{}, chatRooms[room]...) // Save the list of users in the room for later
 	if len(users) == 0 {
 		// If there is no-one left in the room, remove the entry from the map entirely
 		// (this prevents a memory leak)
 		delete(chatRooms, room)
 	}
+	chatRoomsMutex.Unlock()
 
 	// Tell everyone else that someone left
 	for _, user := range users {
 		// All users in the chat room should be online, but check just in case
-		if s2, ok := websocketSessions[user.Name]; ok {
+		if s2, ok := websocketGetSession(user.Name); ok {
 			type RoomLeftMessage struct {
 				Room string `json:"room"`
 				Name string `json:"name"`
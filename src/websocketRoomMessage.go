@@ -49,7 +49,7 @@ func websocketRoomMessage(s *melody.Session, d *IncomingWebsocketData) {
 	}
 
 	// Validate that the room exists
-	users, ok := chatRooms[d.Room]
+	users, ok := chatRoomsGetUsers(d.Room)
 	if !ok {
 		websocketError(s, d.Command, "That is not a valid room name.")
 		return
@@ -89,7 +89,7 @@ func websocketRoomMessage(s *melody.Session, d *IncomingWebsocketData) {
 	// Send the message to everyone in the room
 	for _, user := range users {
 		// All users in the chat room should be online, but check just in case
-		if s2, ok := websocketSessions[user.Name]; ok {
+		if s2, ok := websocketGetSession(user.Name); ok {
 			websocketEmit(s2, "roomMessage", &RoomMessageMessage{
 				d.Room,
 				username,